@@ -0,0 +1,205 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// log-simulator routes sample records through the flows of a logging configuration offline
+// and prints what each output would receive, for example:
+//
+//	kubectl get logging,flows,clusterflows -A -o yaml > resources.yaml
+//	go run ./cmd/log-simulator -resources resources.yaml -records records.jsonl \
+//	  -expect-output ClusterOutput/logging/siem
+//
+// Records are JSON objects with kubernetes metadata, one per line. Every -expect-output has to receive
+// every record, otherwise the command exits with a non-zero status.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/simulator"
+)
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	var resourcePaths, expectedOutputs stringList
+	var recordsPath, tag string
+	flag.Var(&resourcePaths, "resources", "Path of a YAML or JSON file with logging resources, can be repeated")
+	flag.StringVar(&recordsPath, "records", "-", "Path of the records in JSON lines format, - reads the standard input")
+	flag.StringVar(&tag, "tag", "", "Fluentd tag of the records")
+	flag.Var(&expectedOutputs, "expect-output", "Output in Kind/namespace/name form that has to receive every record, can be repeated")
+	flag.Parse()
+
+	if err := run(resourcePaths, recordsPath, tag, expectedOutputs, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(resourcePaths []string, recordsPath, tag string, expectedOutputs []string, out io.Writer) error {
+	var resources simulator.Resources
+	for _, p := range resourcePaths {
+		if err := loadResources(p, &resources); err != nil {
+			return err
+		}
+	}
+
+	events, err := loadEvents(recordsPath, tag)
+	if err != nil {
+		return err
+	}
+
+	result := simulator.Simulate(resources, events)
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+
+	var errs error
+	for _, expected := range expectedOutputs {
+		parts := strings.Split(expected, "/")
+		if len(parts) != 3 {
+			return errors.Errorf("expected output %q should be in Kind/namespace/name form", expected)
+		}
+		output := simulator.Ref{Kind: parts[0], Namespace: parts[1], Name: parts[2]}
+		for idx := range events {
+			if !result.Reached(idx, output) {
+				errs = errors.Append(errs, errors.Errorf("record %d did not reach %s", idx, output))
+			}
+		}
+	}
+	return errs
+}
+
+func open(p string) (io.ReadCloser, error) {
+	if p == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(p)
+}
+
+func loadResources(p string, resources *simulator.Resources) error {
+	f, err := open(p)
+	if err != nil {
+		return errors.WrapIf(err, "opening resources")
+	}
+	defer f.Close()
+
+	decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.WrapIff(err, "parsing resources in %s", p)
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		if err := addResource(raw, resources); err != nil {
+			return errors.WrapIff(err, "parsing resources in %s", p)
+		}
+	}
+}
+
+func addResource(raw json.RawMessage, resources *simulator.Resources) error {
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return err
+	}
+	switch meta.Kind {
+	case "List":
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			if err := addResource(item, resources); err != nil {
+				return err
+			}
+		}
+	case "Logging":
+		var logging v1beta1.Logging
+		if err := json.Unmarshal(raw, &logging); err != nil {
+			return err
+		}
+		if resources.Logging != nil {
+			return errors.Errorf("multiple loggings found: %s and %s", resources.Logging.Name, logging.Name)
+		}
+		resources.Logging = &logging
+	case "Flow":
+		return decodeInto(raw, &resources.Flows)
+	case "ClusterFlow":
+		return decodeInto(raw, &resources.ClusterFlows)
+	case "SyslogNGFlow":
+		return decodeInto(raw, &resources.SyslogNGFlows)
+	case "SyslogNGClusterFlow":
+		return decodeInto(raw, &resources.SyslogNGClusterFlows)
+	}
+	return nil
+}
+
+func decodeInto[T any](raw json.RawMessage, items *[]T) error {
+	var item T
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return err
+	}
+	*items = append(*items, item)
+	return nil
+}
+
+func loadEvents(p string, tag string) ([]simulator.Event, error) {
+	f, err := open(p)
+	if err != nil {
+		return nil, errors.WrapIf(err, "opening records")
+	}
+	defer f.Close()
+
+	var events []simulator.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.WrapIff(err, "parsing record on line %d", line)
+		}
+		events = append(events, simulator.Event{Tag: tag, Record: record})
+	}
+	return events, errors.WrapIf(scanner.Err(), "reading records")
+}
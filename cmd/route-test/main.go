@@ -0,0 +1,95 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// route-test reports the flows receiving the logs of a pod based on a routing table
+// published in the fluentd-routing ConfigMap, for example:
+//
+//	kubectl get cm -n logging logging-fluentd-routing -o jsonpath='{.data.routing\.json}' \
+//	  | go run ./cmd/route-test -namespace apps -label app=web -container nginx
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+type labelFlags map[string]string
+
+func (l labelFlags) String() string {
+	var pairs []string
+	for k, v := range l {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (l labelFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return errors.Errorf("label %q should be in key=value form", value)
+	}
+	l[key] = val
+	return nil
+}
+
+func main() {
+	labels := labelFlags{}
+	var tablePath string
+	var metadata types.RouteMetadata
+	flag.StringVar(&tablePath, "table", "-", "Path of the routing table JSON, - reads the standard input")
+	flag.StringVar(&metadata.Namespace, "namespace", "", "Namespace of the pod")
+	flag.StringVar(&metadata.Container, "container", "", "Name of the container")
+	flag.StringVar(&metadata.Host, "host", "", "Name of the node running the pod")
+	flag.Var(labels, "label", "Label of the pod in key=value form, can be repeated")
+	flag.Parse()
+	metadata.Labels = labels
+
+	if err := run(tablePath, metadata, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(tablePath string, metadata types.RouteMetadata, out io.Writer) error {
+	var input io.Reader = os.Stdin
+	if tablePath != "-" {
+		f, err := os.Open(tablePath)
+		if err != nil {
+			return errors.WrapIf(err, "opening routing table")
+		}
+		defer f.Close()
+		input = f
+	}
+
+	var table types.RoutingTable
+	if err := json.NewDecoder(input).Decode(&table); err != nil {
+		return errors.WrapIf(err, "parsing routing table")
+	}
+
+	matched := table.Match(metadata)
+	if matched == nil {
+		matched = []types.RouteEntry{}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(matched)
+}
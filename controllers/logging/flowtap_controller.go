@@ -0,0 +1,62 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// FlowTapReconciler removes FlowTaps after their TTL has passed
+type FlowTapReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// Reconcile deletes the tap once it expired, or requeues it until then
+func (r *FlowTapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var tap loggingv1beta1.FlowTap
+	if err := r.Client.Get(ctx, req.NamespacedName, &tap); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	expiresAt := tap.ExpiresAt()
+	if expiresAt == nil {
+		return ctrl.Result{}, nil
+	}
+	if remaining := time.Until(expiresAt.Time); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	r.Log.Info("removing expired flowtap", "flowtap", req.NamespacedName)
+	if err := r.Client.Delete(ctx, &tap); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager .
+func (r *FlowTapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&loggingv1beta1.FlowTap{}).
+		Complete(r)
+}
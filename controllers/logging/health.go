@@ -0,0 +1,193 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
+	"github.com/kube-logging/logging-operator/pkg/resources/fluentd"
+	"github.com/kube-logging/logging-operator/pkg/resources/syslogng"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+const (
+	// lastReconcileRefreshInterval limits how often an otherwise unchanged status is patched,
+	// since every status update of the logging triggers a new reconcile
+	lastReconcileRefreshInterval = time.Minute
+	// rolloutRequeueInterval is the delay of the next observation while the workloads are not ready
+	rolloutRequeueInterval = 30 * time.Second
+)
+
+// healthObservation collects what the reconcile loop knows about the desired state of the pipeline
+type healthObservation struct {
+	desiredConfigHash string
+	fluentbitAgents   map[string]string
+	succeeded         bool
+}
+
+// updateHealthStatus observes the workloads of the logging and records their state in the status.
+// Returns whether the workloads are still rolling out.
+func (r *LoggingReconciler) updateHealthStatus(ctx context.Context, name string, observation healthObservation, now time.Time) (bool, error) {
+	var logging loggingv1beta1.Logging
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, &logging); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	patchBase := client.MergeFrom(logging.DeepCopy())
+	previous := logging.Status.DeepCopy()
+	status := &logging.Status
+
+	status.Aggregator = nil
+	status.LiveConfigHash = ""
+	switch {
+	case logging.Spec.FluentdSpec != nil:
+		var shards []metav1.ObjectMeta
+		for shard := int32(0); shard < logging.Spec.FluentdSpec.Shards.ShardCount(); shard++ {
+			shards = append(shards, metav1.ObjectMeta{
+				Namespace: logging.Spec.ControlNamespace,
+				Name:      logging.FluentdShardQualifiedName(shard, fluentd.StatefulSetName),
+			})
+		}
+		aggregator, liveConfigHash, err := r.aggregatorStatus(ctx, "fluentd", shards...)
+		if err != nil {
+			return false, err
+		}
+		status.Aggregator = aggregator
+		status.LiveConfigHash = liveConfigHash
+	case logging.Spec.SyslogNGSpec != nil:
+		aggregator, liveConfigHash, err := r.aggregatorStatus(ctx, "syslog-ng", logging.SyslogNGObjectMeta(syslogng.StatefulSetName, syslogng.ComponentSyslogNG))
+		if err != nil {
+			return false, err
+		}
+		status.Aggregator = aggregator
+		status.LiveConfigHash = liveConfigHash
+	}
+
+	status.FluentbitAgents = nil
+	for agent, daemonSetName := range observation.fluentbitAgents {
+		agentStatus := loggingv1beta1.FluentbitAgentStatus{Name: agent}
+		var daemonSet appsv1.DaemonSet
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: logging.Spec.ControlNamespace, Name: daemonSetName}, &daemonSet)
+		if client.IgnoreNotFound(err) != nil {
+			return false, errors.WrapIfWithDetails(err, "getting fluent-bit daemonset", "name", daemonSetName)
+		}
+		if err == nil {
+			agentStatus.DesiredNumberScheduled = daemonSet.Status.DesiredNumberScheduled
+			agentStatus.NumberReady = daemonSet.Status.NumberReady
+			agentStatus.UpdatedNumberScheduled = daemonSet.Status.UpdatedNumberScheduled
+		}
+		status.FluentbitAgents = append(status.FluentbitAgents, agentStatus)
+	}
+	sortFluentbitAgentStatuses(status.FluentbitAgents)
+
+	drainJobs, err := r.drainJobsInProgress(ctx, &logging)
+	if err != nil {
+		return false, err
+	}
+	status.DrainJobs = drainJobs
+
+	status.DesiredConfigHash = observation.desiredConfigHash
+	status.UpdateHealth(observation.succeeded)
+	rollingOut := !status.WorkloadsReady()
+
+	changed := !equality.Semantic.DeepEqual(previous, status)
+	if observation.succeeded && (changed || status.LastSuccessfulReconcile == nil || now.Sub(status.LastSuccessfulReconcile.Time) >= lastReconcileRefreshInterval) {
+		status.LastSuccessfulReconcile = &metav1.Time{Time: now}
+		changed = true
+	}
+	if !changed {
+		return rollingOut, nil
+	}
+	return rollingOut, errors.WrapIfWithDetails(r.Client.Status().Patch(ctx, &logging, patchBase), "failed to patch health status", "logging", name)
+}
+
+// aggregatorStatus sums up the replicas of the given aggregator statefulsets, one per shard.
+// The live config hash is joined from the config hash annotations of the statefulsets the same way as the desired one,
+// it's empty until every statefulset has been observed by its controller with the annotation.
+func (r *LoggingReconciler) aggregatorStatus(ctx context.Context, aggregatorType string, metas ...metav1.ObjectMeta) (*loggingv1beta1.AggregatorStatus, string, error) {
+	result := &loggingv1beta1.AggregatorStatus{Type: aggregatorType}
+	var liveHashes []string
+	for _, meta := range metas {
+		var statefulSet appsv1.StatefulSet
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: meta.Namespace, Name: meta.Name}, &statefulSet)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, "", errors.WrapIfWithDetails(err, "getting aggregator statefulset", "name", meta.Name)
+		}
+		if err == nil {
+			if statefulSet.Spec.Replicas != nil {
+				result.Replicas += *statefulSet.Spec.Replicas
+			} else {
+				result.Replicas++
+			}
+			result.ReadyReplicas += statefulSet.Status.ReadyReplicas
+			result.UpdatedReplicas += statefulSet.Status.UpdatedReplicas
+		}
+		if hash := statefulSet.Annotations[configcheck.ConfigHashAnnotation]; err == nil && hash != "" && statefulSet.Status.ObservedGeneration >= statefulSet.Generation {
+			liveHashes = append(liveHashes, hash)
+		}
+	}
+	result.Ready = fmt.Sprintf("%d/%d", result.ReadyReplicas, result.Replicas)
+	if len(liveHashes) < len(metas) {
+		return result, "", nil
+	}
+	return result, strings.Join(liveHashes, ","), nil
+}
+
+func (r *LoggingReconciler) drainJobsInProgress(ctx context.Context, logging *loggingv1beta1.Logging) ([]string, error) {
+	if logging.Spec.FluentdSpec == nil {
+		return nil, nil
+	}
+	var jobs batchv1.JobList
+	if err := r.Client.List(ctx, &jobs,
+		client.InNamespace(logging.Spec.ControlNamespace),
+		client.MatchingLabels(logging.GetFluentdLabels(fluentd.ComponentDrainer))); err != nil {
+		return nil, errors.WrapIf(err, "listing drainer jobs")
+	}
+	var result []string
+	for _, job := range jobs.Items {
+		if !jobFinished(job) {
+			result = append(result, job.Name)
+		}
+	}
+	return result, nil
+}
+
+func jobFinished(job batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFluentbitAgentStatuses(statuses []loggingv1beta1.FluentbitAgentStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
@@ -38,14 +39,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kube-logging/logging-operator/pkg/resources"
+	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
 	"github.com/kube-logging/logging-operator/pkg/resources/fluentbit"
 	"github.com/kube-logging/logging-operator/pkg/resources/fluentd"
 	"github.com/kube-logging/logging-operator/pkg/resources/loggingdataprovider"
 	"github.com/kube-logging/logging-operator/pkg/resources/model"
 	"github.com/kube-logging/logging-operator/pkg/resources/nodeagent"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 	"github.com/kube-logging/logging-operator/pkg/resources/syslogng"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/render"
 	syslogngconfig "github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/config"
+	fluentdtypes "github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
 
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 )
@@ -64,17 +69,18 @@ type LoggingReconciler struct {
 	Log logr.Logger
 }
 
-// +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=loggings;fluentbitagents;flows;clusterflows;outputs;clusteroutputs;nodeagents,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=loggings/status;fluentbitagents/status;flows/status;clusterflows/status;outputs/status;clusteroutputs/status;nodeagents/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=loggings;fluentbitagents;flows;clusterflows;outputs;clusteroutputs;nodeagents;outputgrants;flowtaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=loggings/status;fluentbitagents/status;flows/status;clusterflows/status;outputs/status;clusteroutputs/status;nodeagents/status;flowtaps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=syslogngflows;syslogngclusterflows;syslogngoutputs;syslogngclusteroutputs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=syslogngflows/status;syslogngclusterflows/status;syslogngoutputs/status;syslogngclusteroutputs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=extensions;apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=extensions;networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=extensions;policy,resources=podsecuritypolicies,verbs=get;list;watch;create;update;patch;delete;use
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets;daemonsets;replicasets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services;persistentvolumeclaims;serviceaccounts;pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=nodes;namespaces;endpoints;nodes/proxy,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=patch
 // +kubebuilder:rbac:groups="";events.k8s.io,resources=events,verbs=create;get;list;watch
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterroles;clusterrolebindings;roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules;servicemonitors,verbs=get;list;watch;create;update;patch;delete
@@ -159,13 +165,25 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}()
 
+	// filled in by rendering the configs below, before the validation reconciler runs
+	renderErrors := model.FlowRenderErrors{}
 	reconcilers := []resources.ContextAwareComponentReconciler{
 		model.NewValidationReconciler(
 			r.Client,
 			loggingResources,
-			&secretLoaderFactory{Client: r.Client, Path: fluentd.OutputSecretPath},
+			&secretLoaderFactory{
+				Client:   r.Client,
+				Path:     fluentd.OutputSecretPath,
+				Backends: newSecretBackendResolver(r.Client, logging, fluentd.SecretBackendsPath),
+			},
+			renderErrors,
 			log.WithName("validation"),
 		),
+		podsecurity.NewNamespaceReconciler(
+			r.Client,
+			logging.Spec.ControlNamespace,
+			podsecurity.NamespaceLabels(podSecurityLevels(logging, loggingResources)),
+		),
 	}
 
 	if logging.Spec.FluentdSpec != nil && logging.Spec.SyslogNGSpec != nil {
@@ -173,24 +191,48 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	var loggingDataProvider loggingdataprovider.LoggingDataProvider
+	observation := healthObservation{
+		fluentbitAgents: map[string]string{},
+	}
 
 	if logging.Spec.FluentdSpec != nil {
-		fluentdConfig, secretList, err := r.clusterConfigurationFluentd(loggingResources)
+		shardNamespaces, err := model.FluentdShardNamespaces(loggingResources)
+		if err == nil && shardNamespaces != nil && (len(logging.Spec.NodeAgents) > 0 || len(loggingResources.NodeAgents) > 0) {
+			err = errors.New("node agents can't forward to fluentd shards")
+		}
+		var shards []fluentdShardConfiguration
+		if err == nil {
+			shards, err = r.shardConfigurationsFluentd(loggingResources, renderErrors)
+		}
 		if err != nil {
 			// TODO: move config generation into Fluentd reconciler
 			reconcilers = append(reconcilers, func(ctx context.Context) (*reconcile.Result, error) {
 				return &reconcile.Result{}, err
 			})
 		} else {
-			log.V(1).Info("flow configuration", "config", fluentdConfig)
+			var hashes []string
+			for _, shard := range shards {
+				log.V(1).Info("flow configuration", "config", shard.config, "shard", shard.index)
+				hash, err := configcheck.ConfigHash(shard.config, shard.secrets)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				hashes = append(hashes, hash)
+			}
+			observation.desiredConfigHash = strings.Join(hashes, ",")
 
-			reconcilers = append(reconcilers, fluentd.New(r.Client, r.Log, &logging, &fluentdConfig, secretList, reconcilerOpts).Reconcile)
+			for _, shard := range shards {
+				shard := shard
+				reconcilers = append(reconcilers, fluentd.NewShard(r.Client, r.Log, &logging,
+					fluentd.Shard{Index: shard.index, ConfigHashes: hashes},
+					&shard.config, shard.routingTable, shard.secrets, reconcilerOpts).Reconcile)
+			}
 		}
-		loggingDataProvider = fluentd.NewDataProvider(r.Client, &logging)
+		loggingDataProvider = fluentd.NewShardedDataProvider(r.Client, &logging, shardNamespaces)
 	}
 
 	if logging.Spec.SyslogNGSpec != nil {
-		syslogNGConfig, secretList, err := r.clusterConfigurationSyslogNG(loggingResources)
+		syslogNGConfig, secretList, err := r.clusterConfigurationSyslogNG(loggingResources, renderErrors)
 		if err != nil {
 			// TODO: move config generation into Syslog-NG reconciler
 			reconcilers = append(reconcilers, func(ctx context.Context) (*reconcile.Result, error) {
@@ -198,6 +240,9 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			})
 		} else {
 			log.V(1).Info("flow configuration", "config", syslogNGConfig)
+			if observation.desiredConfigHash, err = configcheck.ConfigHash(syslogNGConfig, secretList); err != nil {
+				return ctrl.Result{}, err
+			}
 
 			reconcilers = append(reconcilers, syslogng.New(r.Client, r.Log, &logging, syslogNGConfig, secretList, reconcilerOpts).Reconcile)
 		}
@@ -210,6 +255,7 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		log.Info("WARNING fluentbit definition inside the Logging resource is deprecated and will be removed in the next major release")
 		if logging.Spec.FluentbitSpec != nil {
 			nameProvider := fluentbit.NewLegacyFluentbitNameProvider(&logging)
+			observation.fluentbitAgents[nameProvider.Name()] = nameProvider.DaemonSetName()
 			reconcilers = append(reconcilers, fluentbit.New(
 				r.Client,
 				log.WithName("fluentbit-legacy"),
@@ -227,6 +273,14 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		l := log.WithName("fluentbit")
 		for _, f := range loggingResources.Fluentbits {
 			f := f
+			spec, err := model.InheritFluentbitBase(f, loggingResources.Fluentbits)
+			if err != nil {
+				l.Error(err, "skipping FluentbitAgent", "fluentbitagent", f.Name)
+				continue
+			}
+			f.Spec = spec
+			nameProvider := fluentbit.NewStandaloneFluentbitNameProvider(&f)
+			observation.fluentbitAgents[nameProvider.Name()] = nameProvider.DaemonSetName()
 			reconcilers = append(reconcilers, fluentbit.New(
 				r.Client,
 				l.WithValues("fluentbitagent", f.Name),
@@ -234,7 +288,7 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				reconcilerOpts,
 				&f.Spec,
 				loggingDataProvider,
-				fluentbit.NewStandaloneFluentbitNameProvider(&f),
+				nameProvider,
 			).Reconcile)
 		}
 	}
@@ -257,18 +311,79 @@ func (r *LoggingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		reconcilers = append(reconcilers, nodeagent.New(r.Client, r.Log, &logging, agents, reconcilerOpts, fluentd.NewDataProvider(r.Client, &logging)).Reconcile)
 	}
 
+	result, err := runReconcilers(ctx, reconcilers)
+	observation.succeeded = err == nil && result == nil
+	rollingOut, statusErr := r.updateHealthStatus(ctx, logging.Name, observation, time.Now())
+	if statusErr != nil {
+		log.Error(statusErr, "failed to update the health status")
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+	if rollingOut {
+		return ctrl.Result{RequeueAfter: rolloutRequeueInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// podSecurityLevels returns the Pod Security Standards levels of the workloads in the control namespace,
+// an empty level stands for a workload that does not declare one
+func podSecurityLevels(logging loggingv1beta1.Logging, resources model.LoggingResources) []string {
+	level := func(security *loggingv1beta1.Security) string {
+		if security == nil {
+			return ""
+		}
+		return security.PodSecurityStandard
+	}
+
+	var levels []string
+	if logging.Spec.FluentdSpec != nil {
+		levels = append(levels, level(logging.Spec.FluentdSpec.Security))
+	}
+	if logging.Spec.SyslogNGSpec != nil {
+		levels = append(levels, logging.Spec.SyslogNGSpec.PodSecurityStandard)
+	}
+	if logging.Spec.FluentbitSpec != nil {
+		levels = append(levels, level(logging.Spec.FluentbitSpec.Security))
+	}
+	for _, agent := range resources.Fluentbits {
+		if spec, err := model.InheritFluentbitBase(agent, resources.Fluentbits); err == nil {
+			levels = append(levels, level(spec.Security))
+		}
+	}
+	nodeAgents := make([]loggingv1beta1.NodeAgentConfig, 0, len(resources.NodeAgents)+len(logging.Spec.NodeAgents))
+	for _, agent := range resources.NodeAgents {
+		nodeAgents = append(nodeAgents, agent.Spec.NodeAgentConfig)
+	}
+	for _, agent := range logging.Spec.NodeAgents {
+		nodeAgents = append(nodeAgents, agent.NodeAgentConfig)
+	}
+	for _, agent := range nodeAgents {
+		if agent.FluentbitSpec == nil {
+			levels = append(levels, "")
+			continue
+		}
+		levels = append(levels, level(agent.FluentbitSpec.Security))
+	}
+	return levels
+}
+
+func runReconcilers(ctx context.Context, reconcilers []resources.ContextAwareComponentReconciler) (*reconcile.Result, error) {
 	for _, rec := range reconcilers {
 		result, err := rec(ctx)
 		if err != nil {
-			return reconcile.Result{}, err
+			return nil, err
 		}
 		if result != nil {
 			// short circuit if requested explicitly
-			return *result, err
+			return result, nil
 		}
 	}
-
-	return ctrl.Result{}, nil
+	return nil, nil
 }
 
 func updateResourceStateMetrics(obj client.Object, active bool, problemsCount int, statusMetric *prometheus.GaugeVec, problemsMetric *prometheus.GaugeVec) {
@@ -318,19 +433,54 @@ func boolToFloat64(b bool) float64 {
 	return 0
 }
 
-func (r *LoggingReconciler) clusterConfigurationFluentd(resources model.LoggingResources) (string, *secret.MountSecrets, error) {
+// fluentdShardConfiguration is the rendered configuration of a fluentd shard
+type fluentdShardConfiguration struct {
+	index        int32
+	config       string
+	routingTable []byte
+	secrets      *secret.MountSecrets
+}
+
+// shardConfigurationsFluentd renders the configuration of every fluentd shard, a single one if the flows aren't sharded
+func (r *LoggingReconciler) shardConfigurationsFluentd(resources model.LoggingResources, renderErrors model.FlowRenderErrors) ([]fluentdShardConfiguration, error) {
+	referenced := make(map[types.NamespacedName]struct{})
+	var shards []fluentdShardConfiguration
+	for index := int32(0); index < resources.Logging.Spec.FluentdSpec.Shards.ShardCount(); index++ {
+		config, routingTable, secrets, err := r.clusterConfigurationFluentd(model.FluentdShardResources(resources, index), referenced, renderErrors)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to render fluentd shard config", "shard", index)
+		}
+		shards = append(shards, fluentdShardConfiguration{
+			index:        index,
+			config:       config,
+			routingTable: routingTable,
+			secrets:      secrets,
+		})
+	}
+	referencedSecrets.Set(resources.Logging.Name, referenced)
+	return shards, nil
+}
+
+func (r *LoggingReconciler) clusterConfigurationFluentd(resources model.LoggingResources, referenced map[types.NamespacedName]struct{}, renderErrors model.FlowRenderErrors) (string, []byte, *secret.MountSecrets, error) {
 	if cfg := resources.Logging.Spec.FlowConfigOverride; cfg != "" {
-		return cfg, nil, nil
+		return cfg, nil, nil, nil
 	}
 
 	slf := secretLoaderFactory{
-		Client: r.Client,
-		Path:   fluentd.OutputSecretPath,
+		Client:     r.Client,
+		Path:       fluentd.OutputSecretPath,
+		Backends:   newSecretBackendResolver(r.Client, resources.Logging, fluentd.SecretBackendsPath),
+		Referenced: referenced,
 	}
 
-	fluentConfig, err := model.CreateSystem(resources, &slf, r.Log)
+	fluentConfig, err := model.CreateSystem(resources, &slf, renderErrors, r.Log)
 	if err != nil {
-		return "", nil, errors.WrapIfWithDetails(err, "failed to build model", "logging", resources.Logging)
+		return "", nil, nil, errors.WrapIfWithDetails(err, "failed to build model", "logging", resources.Logging)
+	}
+	if err := fluentdtypes.ValidateConfig(fluentConfig); err != nil {
+		// reported in the status of the logging by the validation reconciler
+		renderErrors.Record(&resources.Logging, err)
+		return "", nil, nil, errors.WrapIfWithDetails(err, "invalid fluentd config", "logging", resources.Logging)
 	}
 
 	output := &bytes.Buffer{}
@@ -339,20 +489,28 @@ func (r *LoggingReconciler) clusterConfigurationFluentd(resources model.LoggingR
 		Indent: 2,
 	}
 	if err := renderer.Render(fluentConfig); err != nil {
-		return "", nil, errors.WrapIfWithDetails(err, "failed to render fluentd config", "logging", resources.Logging)
+		return "", nil, nil, errors.WrapIfWithDetails(err, "failed to render fluentd config", "logging", resources.Logging)
 	}
 
-	return output.String(), &slf.Secrets, nil
+	var routingTable []byte
+	if resources.Logging.Spec.FluentdSpec.PublishRoutingTable {
+		routingTable, err = fluentdtypes.NewRoutingTable(fluentConfig).JSON()
+		if err != nil {
+			return "", nil, nil, errors.WrapIfWithDetails(err, "failed to render routing table", "logging", resources.Logging)
+		}
+	}
+	return output.String(), routingTable, &slf.Secrets, nil
 }
 
-func (r *LoggingReconciler) clusterConfigurationSyslogNG(resources model.LoggingResources) (string, *secret.MountSecrets, error) {
+func (r *LoggingReconciler) clusterConfigurationSyslogNG(resources model.LoggingResources, renderErrors model.FlowRenderErrors) (string, *secret.MountSecrets, error) {
 	if cfg := resources.Logging.Spec.FlowConfigOverride; cfg != "" {
 		return cfg, nil, nil
 	}
 
 	slf := secretLoaderFactory{
-		Client: r.Client,
-		Path:   syslogng.OutputSecretPath,
+		Client:   r.Client,
+		Path:     syslogng.OutputSecretPath,
+		Backends: newSecretBackendResolver(r.Client, resources.Logging, syslogng.SecretBackendsPath),
 	}
 
 	in := syslogngconfig.Input{
@@ -361,13 +519,22 @@ func (r *LoggingReconciler) clusterConfigurationSyslogNG(resources model.Logging
 		Outputs:             resources.SyslogNG.Outputs,
 		ClusterFlows:        resources.SyslogNG.ClusterFlows,
 		Flows:               resources.SyslogNG.Flows,
+		OutputGrants:        resources.OutputGrants,
 		SecretLoaderFactory: &slf,
 		SourcePort:          syslogng.ServicePort,
+		TLSDir:              syslogng.TLSPath,
+		RecordFlowError:     renderErrors.Record,
+		AuditSecrets: func(resource client.Object, secrets syslogngconfig.SecretLoaderFactory) syslogngconfig.SecretLoaderFactory {
+			return renderErrors.AuditSecrets(resource, secrets.SecretLoaderForNamespace)
+		},
 	}
 	var b strings.Builder
 	if err := syslogngconfig.RenderConfigInto(in, &b); err != nil {
+		// reported in the status of the logging by the validation reconciler
+		renderErrors.Record(&resources.Logging, err)
 		return "", nil, errors.WrapIfWithDetails(err, "failed to render syslog-ng config", "logging", resources.Logging)
 	}
+	referencedSecrets.Set(resources.Logging.Name, slf.Referenced)
 
 	return b.String(), &slf.Secrets, nil
 }
@@ -376,6 +543,22 @@ type secretLoaderFactory struct {
 	Client  client.Client
 	Secrets secret.MountSecrets
 	Path    string
+	// Referenced collects every secret loaded through the factory
+	Referenced map[types.NamespacedName]struct{}
+	// Backends resolves references to secret sources other than Kubernetes Secrets
+	Backends *secretbackend.Resolver
+}
+
+func newSecretBackendResolver(c client.Client, logging loggingv1beta1.Logging, mountPath string) *secretbackend.Resolver {
+	if logging.Spec.SecretBackends == nil {
+		return nil
+	}
+	return &secretbackend.Resolver{
+		Client:           c,
+		Backends:         logging.Spec.SecretBackends,
+		ControlNamespace: logging.Spec.ControlNamespace,
+		MountPath:        mountPath,
+	}
 }
 
 // Deprecated: use SecretLoaderForNamespace instead
@@ -384,7 +567,18 @@ func (f *secretLoaderFactory) OutputSecretLoaderForNamespace(namespace string) s
 }
 
 func (f *secretLoaderFactory) SecretLoaderForNamespace(namespace string) secret.SecretLoader {
-	return secret.NewSecretLoader(f.Client, namespace, f.Path, &f.Secrets)
+	if f.Referenced == nil {
+		f.Referenced = make(map[types.NamespacedName]struct{})
+	}
+	loader := secret.NewSecretLoader(f.Client, namespace, f.Path, &f.Secrets)
+	if f.Backends != nil {
+		loader = f.Backends.LoaderFor(loader, namespace, f.Path, &f.Secrets)
+	}
+	return &recordingSecretLoader{
+		SecretLoader: loader,
+		namespace:    namespace,
+		referenced:   f.Referenced,
+	}
 }
 
 // SetupLoggingWithManager setup logging manager
@@ -418,6 +612,15 @@ func SetupLoggingWithManager(mgr ctrl.Manager, logger logr.Logger) *ctrl.Builder
 			return reconcileRequestsForLoggingRef(loggingList.Items, o.Spec.LoggingRef)
 		case *loggingv1beta1.FluentbitAgent:
 			return reconcileRequestsForLoggingRef(loggingList.Items, o.Spec.LoggingRef)
+		case *loggingv1beta1.FlowTap:
+			return reconcileRequestsForLoggingRef(loggingList.Items, o.Spec.LoggingRef)
+		case *loggingv1beta1.OutputGrant:
+			// grants are not bound to a logging, any of them may contain flows referencing the granted outputs
+			var requestList []reconcile.Request
+			for _, l := range loggingList.Items {
+				requestList = append(requestList, reconcile.Request{NamespacedName: types.NamespacedName{Name: l.Name}})
+			}
+			return requestList
 		case *corev1.Secret:
 			r := regexp.MustCompile(`^logging\.banzaicloud\.io/(.*)`)
 			var requestList []reconcile.Request
@@ -431,6 +634,10 @@ func SetupLoggingWithManager(mgr ctrl.Manager, logger logr.Logger) *ctrl.Builder
 					requestList = append(requestList, reconcileRequestsForLoggingRef(loggingList.Items, loggingRef)...)
 				}
 			}
+			// secrets referenced by the rendered configs trigger a reconcile even without the annotation
+			for _, name := range referencedSecrets.LoggingsReferencing(client.ObjectKeyFromObject(o)) {
+				requestList = append(requestList, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+			}
 			return requestList
 		}
 		return nil
@@ -466,6 +673,8 @@ func SetupLoggingWithManager(mgr ctrl.Manager, logger logr.Logger) *ctrl.Builder
 		Watches(&loggingv1beta1.SyslogNGClusterFlow{}, requestMapper).
 		Watches(&loggingv1beta1.SyslogNGOutput{}, requestMapper).
 		Watches(&loggingv1beta1.SyslogNGFlow{}, requestMapper).
+		Watches(&loggingv1beta1.OutputGrant{}, requestMapper).
+		Watches(&loggingv1beta1.FlowTap{}, requestMapper).
 		Watches(&corev1.Secret{}, requestMapper)
 
 	// TODO remove with the next major release
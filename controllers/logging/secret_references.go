@@ -0,0 +1,81 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// referencedSecrets is shared between the reconciler, which records the secrets used by the rendered configs,
+// and the secret watch, which triggers a reconcile for every logging referencing a changed secret
+var referencedSecrets = newSecretReferences()
+
+// secretReferences keeps track of the secrets referenced by the configuration of each logging,
+// so that rotating them triggers a reconcile without having to annotate the secrets
+type secretReferences struct {
+	mu   sync.RWMutex
+	refs map[string]map[types.NamespacedName]struct{}
+}
+
+func newSecretReferences() *secretReferences {
+	return &secretReferences{
+		refs: make(map[string]map[types.NamespacedName]struct{}),
+	}
+}
+
+// Set replaces the secrets referenced by the given logging
+func (s *secretReferences) Set(logging string, secrets map[types.NamespacedName]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(secrets) == 0 {
+		delete(s.refs, logging)
+		return
+	}
+	s.refs[logging] = secrets
+}
+
+// LoggingsReferencing returns the names of the loggings referencing the given secret
+func (s *secretReferences) LoggingsReferencing(secret types.NamespacedName) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var loggings []string
+	for logging, secrets := range s.refs {
+		if _, ok := secrets[secret]; ok {
+			loggings = append(loggings, logging)
+		}
+	}
+	sort.Strings(loggings)
+	return loggings
+}
+
+// recordingSecretLoader records every kubernetes secret loaded through it
+type recordingSecretLoader struct {
+	secret.SecretLoader
+	namespace  string
+	referenced map[types.NamespacedName]struct{}
+}
+
+func (l *recordingSecretLoader) Load(s *secret.Secret) (string, error) {
+	for _, ref := range []*secret.ValueFrom{s.ValueFrom, s.MountFrom} {
+		if ref != nil && ref.SecretKeyRef != nil {
+			l.referenced[types.NamespacedName{Namespace: l.namespace, Name: ref.SecretKeyRef.Name}] = struct{}{}
+		}
+	}
+	return l.SecretLoader.Load(s)
+}
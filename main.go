@@ -32,10 +32,8 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
-	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -45,7 +43,6 @@ import (
 
 	extensionsControllers "github.com/kube-logging/logging-operator/controllers/extensions"
 	loggingControllers "github.com/kube-logging/logging-operator/controllers/logging"
-	"github.com/kube-logging/logging-operator/pkg/resources"
 	extensionsv1alpha1 "github.com/kube-logging/logging-operator/pkg/sdk/extensions/api/v1alpha1"
 	config "github.com/kube-logging/logging-operator/pkg/sdk/extensions/extensionsconfig"
 	loggingv1alpha1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1alpha1"
@@ -169,10 +166,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	if !PSPEnabled(mgr.GetConfig()) {
-		setupLog.Info("WARNING PodSecurityPolicies are disabled. Can be enabled manually with PSP_ENABLED=1")
-	}
-
 	loggingReconciler := loggingControllers.NewLoggingReconciler(mgr.GetClient(), ctrl.Log.WithName("logging"))
 
 	if err := (&extensionsControllers.EventTailerReconciler{
@@ -197,6 +190,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := (&loggingControllers.FlowTapReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("flowtap"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FlowTap")
+		os.Exit(1)
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
 		if err := loggingv1beta1.SetupWebhookWithManager(mgr, loggingv1beta1.APITypes()...); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "v1beta1.logging")
@@ -223,27 +224,6 @@ func main() {
 	}
 }
 
-func PSPEnabled(cfg *rest.Config) bool {
-	pspenv := os.Getenv("PSP_ENABLED")
-	if pspenv != "" {
-		return cast.ToBool(pspenv)
-	}
-	dsc, err := discovery.NewDiscoveryClientForConfig(cfg)
-	if err != nil {
-		setupLog.Error(err, "discovery client creation")
-		os.Exit(1)
-	}
-	serverVersion, err := dsc.ServerVersion()
-	if err != nil {
-		setupLog.Error(err, "server version")
-		os.Exit(1)
-	}
-	if cast.ToInt(serverVersion.Major) == 1 && cast.ToInt(serverVersion.Minor) < 25 {
-		resources.PSPEnabled = true
-	}
-	return resources.PSPEnabled
-}
-
 func detectContainerRuntime(ctx context.Context, c client.Reader) error {
 	var nodeList corev1.NodeList
 	if err := c.List(ctx, &nodeList, client.Limit(1)); err != nil {
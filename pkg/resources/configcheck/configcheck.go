@@ -16,8 +16,14 @@ package configcheck
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
 
 	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/secret"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,6 +31,38 @@ import (
 
 const hashLabel = "logging.banzaicloud.io/config-hash"
 
+// maxFailureOutputBytes limits the failure output kept in the status
+const maxFailureOutputBytes = 2048
+
+// ConfigHashAnnotation holds the hash of the config applied to the aggregator,
+// set on the config secret and on the statefulset serving it
+const ConfigHashAnnotation = "logging.banzaicloud.io/config-hash"
+
+// ConfigHash calculates the hash of the rendered config together with the contents of the mounted secrets,
+// so that rotating a referenced secret results in a new hash even if the config itself is unchanged
+func ConfigHash(config string, secrets *secret.MountSecrets) (string, error) {
+	hasher := fnv.New32()
+	if _, err := io.WriteString(hasher, config); err != nil {
+		return "", errors.WrapIf(err, "failed to calculate hash for the configmap data")
+	}
+	if secrets != nil {
+		mounted := make(secret.MountSecrets, len(*secrets))
+		copy(mounted, *secrets)
+		sort.Slice(mounted, func(i, j int) bool {
+			return mounted[i].MappedKey < mounted[j].MappedKey
+		})
+		for _, s := range mounted {
+			if _, err := io.WriteString(hasher, s.MappedKey); err != nil {
+				return "", errors.WrapIf(err, "failed to calculate hash for the mounted secrets")
+			}
+			if _, err := hasher.Write(s.Value); err != nil {
+				return "", errors.WrapIf(err, "failed to calculate hash for the mounted secrets")
+			}
+		}
+	}
+	return fmt.Sprintf("%x", hasher.Sum32()), nil
+}
+
 func WithHashLabel(accessor v1.Object, hash string) {
 	l := accessor.GetLabels()
 	if l == nil {
@@ -41,6 +79,15 @@ func hasHashLabel(accessor v1.Object, hash string) (has bool, match bool) {
 	return has, val == hash
 }
 
+func matchesAnyHash(accessor v1.Object, hashes []string) bool {
+	for _, hash := range hashes {
+		if _, match := hasHashLabel(accessor, hash); match {
+			return true
+		}
+	}
+	return false
+}
+
 type ConfigCheckCleaner struct {
 	client client.Client
 	labels client.MatchingLabels
@@ -56,15 +103,15 @@ func NewConfigCheckCleaner(c client.Client, component string) *ConfigCheckCleane
 }
 
 // SecretCleanup cleans up configcheck secrets that have the logging.banzaicloud.io/config-hash label, but
-// doesn't match the current config hash
-func (c *ConfigCheckCleaner) SecretCleanup(ctx context.Context, hash string) (multierr error) {
+// don't match any of the current config hashes
+func (c *ConfigCheckCleaner) SecretCleanup(ctx context.Context, hashes ...string) (multierr error) {
 	allCheckSecrets := &corev1.SecretList{}
 	if err := c.client.List(ctx, allCheckSecrets, c.labels); err != nil {
 		return errors.Wrap(err, "failed to list configcheck secrets")
 	}
 
 	for _, secret := range allCheckSecrets.Items {
-		if _, match := hasHashLabel(&secret, hash); match {
+		if matchesAnyHash(&secret, hashes) {
 			continue
 		}
 		if err := client.IgnoreNotFound(c.client.Delete(ctx, &secret)); err != nil {
@@ -78,15 +125,15 @@ func (c *ConfigCheckCleaner) SecretCleanup(ctx context.Context, hash string) (mu
 }
 
 // PodCleanup cleans up configcheck pods that have the logging.banzaicloud.io/config-hash label, but
-// doesn't match the current config hash
-func (c *ConfigCheckCleaner) PodCleanup(ctx context.Context, hash string) (multierr error) {
+// don't match any of the current config hashes
+func (c *ConfigCheckCleaner) PodCleanup(ctx context.Context, hashes ...string) (multierr error) {
 	allCheckPods := &corev1.PodList{}
 	if err := c.client.List(ctx, allCheckPods, c.labels); err != nil {
 		return errors.Wrap(err, "failed to list configcheck pods")
 	}
 
 	for _, pod := range allCheckPods.Items {
-		if _, match := hasHashLabel(&pod, hash); match {
+		if matchesAnyHash(&pod, hashes) {
 			continue
 		}
 		if err := client.IgnoreNotFound(c.client.Delete(ctx, &pod)); err != nil {
@@ -98,3 +145,27 @@ func (c *ConfigCheckCleaner) PodCleanup(ctx context.Context, hash string) (multi
 
 	return
 }
+
+// FailureOutput returns the termination messages of the failed containers of a config check pod.
+// The containers fall back to the tail of their logs as termination message, so this is why the config got rejected.
+func FailureOutput(pod *corev1.Pod) string {
+	var messages []string
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		message := strings.TrimSpace(terminated.Message)
+		if message == "" {
+			message = fmt.Sprintf("exited with code %d", terminated.ExitCode)
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", status.Name, message))
+	}
+	output := strings.Join(messages, "\n")
+	if len(output) > maxFailureOutputBytes {
+		// keep the end of the output, where the errors usually are
+		output = strings.ToValidUTF8(output[len(output)-maxFailureOutputBytes:], "")
+	}
+	return output
+}
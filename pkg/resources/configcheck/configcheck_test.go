@@ -0,0 +1,86 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configcheck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigHash(t *testing.T) {
+	mustHash := func(config string, secrets *secret.MountSecrets) string {
+		hash, err := ConfigHash(config, secrets)
+		if err != nil {
+			t.Fatalf("ConfigHash() error = %v", err)
+		}
+		return hash
+	}
+
+	a := secret.MountSecret{Namespace: "default", Name: "creds", Key: "password", MappedKey: "default-creds-password", Value: []byte("old")}
+	b := secret.MountSecret{Namespace: "default", Name: "tls", Key: "tls.key", MappedKey: "default-tls-tls.key", Value: []byte("key")}
+	rotated := a
+	rotated.Value = []byte("new")
+
+	base := mustHash("config", &secret.MountSecrets{a, b})
+
+	if got := mustHash("config", &secret.MountSecrets{b, a}); got != base {
+		t.Errorf("hash depends on the order of the secrets: %s != %s", got, base)
+	}
+	if got := mustHash("config", &secret.MountSecrets{rotated, b}); got == base {
+		t.Errorf("hash did not change after rotating a secret")
+	}
+	if got := mustHash("other", &secret.MountSecrets{a, b}); got == base {
+		t.Errorf("hash did not change after changing the config")
+	}
+	if mustHash("config", nil) != mustHash("config", &secret.MountSecrets{}) {
+		t.Errorf("hash differs for nil and empty secrets")
+	}
+}
+
+func TestFailureOutput(t *testing.T) {
+	terminated := func(name string, exitCode int32, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name: name,
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message},
+			},
+		}
+	}
+
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{terminated("config-reloader", 0, "done")},
+			ContainerStatuses:     []corev1.ContainerStatus{terminated("fluentd", 1, "config error: unknown output plugin 'nope'\n")},
+		},
+	}
+	if got, want := FailureOutput(pod), "fluentd: config error: unknown output plugin 'nope'"; got != want {
+		t.Errorf("unexpected output %q, expected %q", got, want)
+	}
+
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{terminated("config-reloader", 2, "")}
+	pod.Status.ContainerStatuses = nil
+	if got, want := FailureOutput(pod), "config-reloader: exited with code 2"; got != want {
+		t.Errorf("unexpected output %q, expected %q", got, want)
+	}
+
+	pod.Status.InitContainerStatuses = nil
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{terminated("syslog-ng", 1, strings.Repeat("x", 4000)+"the error")}
+	if got := FailureOutput(pod); len(got) != maxFailureOutputBytes || !strings.HasSuffix(got, "the error") {
+		t.Errorf("output isn't truncated to the last %d bytes: %q", maxFailureOutputBytes, got)
+	}
+}
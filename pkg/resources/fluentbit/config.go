@@ -43,32 +43,102 @@ var fluentBitConfigTemplate = `
     {{- end }}
     {{- end }}
 
+{{- range $input := .Inputs }}
+
 [INPUT]
     Name         tail
-    {{- range $key, $value := .Input.Values }}
+    {{- range $key, $value := $input.Values }}
     {{- if $value }}
     {{ $key }}  {{$value}}
     {{- end }}
     {{- end }}
-    {{- range $id, $v := .Input.ParserN }}
+    {{- range $id, $v := $input.ParserN }}
     {{- if $v }}
     Parse_{{ $id}} {{$v}}
     {{- end }}
     {{- end }}
-    {{- if .Input.MultilineParser }}
-    multiline.parser {{- range $i, $v := .Input.MultilineParser }}{{ if $i }},{{ end}} {{ $v }}{{ end }}
+    {{- if $input.MultilineParser }}
+    multiline.parser {{- range $i, $v := $input.MultilineParser }}{{ if $i }},{{ end}} {{ $v }}{{ end }}
+    {{- end }}
+{{- end }}
+
+{{- with .SystemdInput }}
+
+[INPUT]
+    Name         systemd
+    Tag          {{ .Tag }}
+    {{- range $unit := .Units }}
+    Systemd_Filter  _SYSTEMD_UNIT={{ $unit }}
+    {{- end }}
+    {{- if gt (len .Units) 1 }}
+    Systemd_Filter_Type  Or
+    {{- end }}
+    {{- range $key, $value := .Values }}
+    {{- if $value }}
+    {{ $key }}  {{$value}}
+    {{- end }}
+    {{- end }}
+{{- end }}
+
+{{- with .AuditLogsInput }}
+
+[INPUT]
+    Name         tail
+    Tag          {{ .Tag }}
+    Parser       json
+    {{- range $key, $value := .Values }}
+    {{- if $value }}
+    {{ $key }}  {{$value}}
+    {{- end }}
     {{- end }}
+{{- end }}
 
 {{- if not .DisableKubernetesFilter }}
+{{- range $input := .Inputs }}
 [FILTER]
     Name        kubernetes
-    {{- range $key, $value := .KubernetesFilter }}
+    {{- range $key, $value := $input.KubernetesFilter }}
     {{- if $value }}
     {{ $key }}  {{$value}}
     {{- end }}
     {{- end }}
+{{- end }}
 {{- end}}
 
+{{- with .SystemdInput }}
+
+[FILTER]
+    Name        modify
+    Match       {{ .Match }}
+    Copy        {{ .UnitKey }} container_name
+    Copy        {{ .HostnameKey }} host
+
+[FILTER]
+    Name        nest
+    Match       {{ .Match }}
+    Operation   nest
+    Wildcard    container_name
+    Wildcard    host
+    Nest_under  kubernetes
+{{- end }}
+
+{{- with .AuditLogsInput }}
+
+[FILTER]
+    Name        modify
+    Match       {{ .Match }}
+    Set         container_name {{ .ContainerName }}
+    Set         host {{ .NodeName }}
+
+[FILTER]
+    Name        nest
+    Match       {{ .Match }}
+    Operation   nest
+    Wildcard    container_name
+    Wildcard    host
+    Nest_under  kubernetes
+{{- end }}
+
 {{- if .AwsFilter }}
 [FILTER]
     Name        aws
@@ -95,10 +165,31 @@ var fluentBitConfigTemplate = `
     {{- end }}
 {{- end}}
 
-{{- with .FluentForwardOutput }}
+{{- range $filter := .Filters }}
+
+[FILTER]
+    Name {{ $filter.Name }}
+    Match {{ $filter.Match }}
+    {{- range $key, $value := $filter.Params }}
+    {{- if $value }}
+    {{ $key }}  {{ $value }}
+    {{- end }}
+    {{- end }}
+    {{- range $param := $filter.Repeated }}
+    {{ $param.Key }}  {{ $param.Value }}
+    {{- end }}
+{{- end }}
+
+{{- range .ForwardOutputs }}
 [OUTPUT]
     Name          forward
+    {{- if .MatchRegex }}
+    Match_Regex   {{ .MatchRegex }}
+    {{- else if $.AggregatorMatchRegex }}
+    Match_Regex   {{ $.AggregatorMatchRegex }}
+    {{- else }}
     Match         *
+    {{- end }}
     {{- if .Upstream.Enabled }}
     Upstream      {{ .Upstream.Config.Path }}
     {{- else }}
@@ -156,10 +247,21 @@ var fluentBitConfigTemplate = `
 {{- with .SyslogNGOutput }}
 [OUTPUT]
     Name tcp
+    {{- if $.AggregatorMatchRegex }}
+    Match_Regex {{ $.AggregatorMatchRegex }}
+    {{- else }}
     Match *
+    {{- end }}
     Host {{ .Host }}
     Port {{ .Port }}
     Format json_lines
+    {{- if .TLS }}
+    tls           On
+    tls.verify    On
+    tls.ca_file   /fluent-bit/tls/ca.crt
+    tls.crt_file  /fluent-bit/tls/tls.crt
+    tls.key_file  /fluent-bit/tls/tls.key
+    {{- end }}
     {{- with .JSONDateKey }}
     json_date_key {{ . }}
     {{- end }}
@@ -197,6 +299,21 @@ var fluentBitConfigTemplate = `
     net.source_address {{.Network.SourceAddress}}
     {{- end }}
 {{- end }}
+
+{{- range $output := .DirectOutputs }}
+
+[OUTPUT]
+    Name {{ $output.Name }}
+    Match {{ $output.Match }}
+    {{- range $key, $value := $output.Params }}
+    {{- if $value }}
+    {{ $key }}  {{ $value }}
+    {{- end }}
+    {{- end }}
+    {{- range $key, $value := $output.Headers }}
+    header  {{ $key }} {{ $value }}
+    {{- end }}
+{{- end }}
 `
 
 var upstreamConfigTemplate = `
@@ -0,0 +1,726 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentbit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func secretRef(name, key string) *secret.Secret {
+	return &secret.Secret{
+		ValueFrom: &secret.ValueFrom{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func TestDirectOutputsConfig(t *testing.T) {
+	loader := newEnvSecretLoader()
+	spec := []v1beta1.FluentbitDirectOutput{
+		{
+			Match:      "kubernetes.*",
+			RetryLimit: "5",
+			Loki: &v1beta1.FluentbitLokiOutput{
+				Host:       "loki.monitoring.svc",
+				Port:       3100,
+				Labels:     "job=fluent-bit",
+				HTTPUser:   &secret.Secret{Value: "admin"},
+				HTTPPasswd: secretRef("loki-auth", "password"),
+				TLS:        utils.BoolPointer(true),
+			},
+		},
+		{
+			Elasticsearch: &v1beta1.FluentbitElasticsearchOutput{
+				Host:             "elasticsearch",
+				LogstashFormat:   utils.BoolPointer(true),
+				SuppressTypeName: utils.BoolPointer(true),
+			},
+		},
+		{
+			OpenTelemetry: &v1beta1.FluentbitOpenTelemetryOutput{
+				Host: "otel-collector",
+				Port: 4318,
+				Headers: map[string]*secret.Secret{
+					"Authorization": secretRef("otel-auth", "header"),
+				},
+			},
+		},
+		{
+			Kafka: &v1beta1.FluentbitKafkaOutput{
+				Brokers:          "kafka-0:9092,kafka-1:9092",
+				Topics:           "logs",
+				SecurityProtocol: "SASL_SSL",
+				SASLMechanism:    "PLAIN",
+				SASLUsername:     &secret.Secret{Value: "fluent-bit"},
+				SASLPassword:     secretRef("kafka-auth", "password"),
+				RDKafkaOptions:   map[string]string{"queue.buffering.max.ms": "100"},
+			},
+		},
+		{
+			S3: &v1beta1.FluentbitS3Output{
+				Bucket:          "logs",
+				Region:          "eu-west-1",
+				AccessKeyID:     secretRef("s3-auth", "id"),
+				SecretAccessKey: secretRef("s3-auth", "key"),
+			},
+		},
+	}
+	outputs, err := newDirectOutputs(spec, loader)
+	require.NoError(t, err)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:          1,
+		Grace:          5,
+		LogLevel:       "info",
+		CoroStackSize:  24576,
+		DefaultParsers: "/fluent-bit/etc/parsers.conf",
+		DirectOutputs:  outputs,
+	})
+	require.NoError(t, err)
+
+	expected := `
+[OUTPUT]
+    Name loki
+    Match kubernetes.*
+    Retry_Limit  5
+    host  loki.monitoring.svc
+    http_passwd  ${FLUENTBIT_SECRET_LOKI_AUTH_PASSWORD}
+    http_user  admin
+    labels  job=fluent-bit
+    port  3100
+    tls  true
+
+[OUTPUT]
+    Name es
+    Match *
+    Host  elasticsearch
+    Logstash_Format  true
+    Suppress_Type_Name  true
+
+[OUTPUT]
+    Name opentelemetry
+    Match *
+    host  otel-collector
+    port  4318
+    header  Authorization ${FLUENTBIT_SECRET_OTEL_AUTH_HEADER}
+
+[OUTPUT]
+    Name kafka
+    Match *
+    brokers  kafka-0:9092,kafka-1:9092
+    rdkafka.queue.buffering.max.ms  100
+    rdkafka.sasl.mechanism  PLAIN
+    rdkafka.sasl.password  ${FLUENTBIT_SECRET_KAFKA_AUTH_PASSWORD}
+    rdkafka.sasl.username  fluent-bit
+    rdkafka.security.protocol  SASL_SSL
+    topics  logs
+
+[OUTPUT]
+    Name s3
+    Match *
+    bucket  logs
+    region  eu-west-1
+`
+	require.True(t, strings.HasSuffix(config, expected), "unexpected config:\n%s", config)
+	require.NotContains(t, config, "[OUTPUT]\n    Name          forward")
+
+	var envNames []string
+	for _, envVar := range loader.EnvVars() {
+		envNames = append(envNames, envVar.Name)
+		require.NotNil(t, envVar.ValueFrom)
+	}
+	require.Equal(t, []string{
+		"AWS_ACCESS_KEY_ID",
+		"AWS_SECRET_ACCESS_KEY",
+		"FLUENTBIT_SECRET_KAFKA_AUTH_PASSWORD",
+		"FLUENTBIT_SECRET_LOKI_AUTH_PASSWORD",
+		"FLUENTBIT_SECRET_OTEL_AUTH_HEADER",
+	}, envNames)
+
+	// the daemonset computes the same variables from the spec
+	envVars, err := directOutputEnvVars(spec)
+	require.NoError(t, err)
+	require.Equal(t, loader.EnvVars(), envVars)
+}
+
+func TestDirectOutputsInvalid(t *testing.T) {
+	testCases := map[string][]v1beta1.FluentbitDirectOutput{
+		"no output type": {
+			{Match: "*"},
+		},
+		"multiple output types": {
+			{
+				Loki:  &v1beta1.FluentbitLokiOutput{Host: "loki"},
+				Kafka: &v1beta1.FluentbitKafkaOutput{Brokers: "kafka:9092"},
+			},
+		},
+		"mountFrom secret": {
+			{
+				Loki: &v1beta1.FluentbitLokiOutput{
+					Host:       "loki",
+					HTTPPasswd: &secret.Secret{MountFrom: secretRef("loki-auth", "password").ValueFrom},
+				},
+			},
+		},
+		"conflicting aws credentials": {
+			{S3: &v1beta1.FluentbitS3Output{Bucket: "a", Region: "eu-west-1", AccessKeyID: secretRef("a", "id")}},
+			{S3: &v1beta1.FluentbitS3Output{Bucket: "b", Region: "eu-west-1", AccessKeyID: secretRef("b", "id")}},
+		},
+	}
+	for name, outputs := range testCases {
+		outputs := outputs
+		t.Run(name, func(t *testing.T) {
+			_, err := newDirectOutputs(outputs, newEnvSecretLoader())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFiltersConfig(t *testing.T) {
+	filters, err := newFilters([]v1beta1.FluentbitFilter{
+		{
+			Match: "kubernetes.*",
+			Grep: &v1beta1.FluentbitGrepFilter{
+				Regex:   []string{"$kubernetes['namespace_name'] ^prod-"},
+				Exclude: []string{"log ^DEBUG", "log ^TRACE"},
+			},
+		},
+		{
+			Lua: &v1beta1.FluentbitLuaFilter{
+				Script: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "lua-scripts"},
+					Key:                  "mask.lua",
+				},
+				Call:          "mask",
+				ProtectedMode: utils.BoolPointer(true),
+			},
+		},
+		{
+			RewriteTag: &v1beta1.FluentbitRewriteTagFilter{
+				Rules:       []string{"$level ^(error)$ errors.$TAG false"},
+				EmitterName: "re_emitted",
+			},
+		},
+		{
+			Throttle: &v1beta1.FluentbitThrottleFilter{Rate: 1000, Window: 5, Interval: "1s"},
+		},
+		{
+			Nest: &v1beta1.FluentbitNestFilter{Operation: "nest", Wildcard: []string{"pod_*"}, NestUnder: "pod"},
+		},
+		{
+			Multiline: &v1beta1.FluentbitMultilineFilter{Parsers: []string{"go", "java"}, KeyContent: "log"},
+		},
+	})
+	require.NoError(t, err)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:                   1,
+		Grace:                   5,
+		LogLevel:                "info",
+		CoroStackSize:           24576,
+		DefaultParsers:          "/fluent-bit/etc/parsers.conf",
+		DisableKubernetesFilter: true,
+		Filters:                 filters,
+	})
+	require.NoError(t, err)
+
+	expected := `
+[FILTER]
+    Name grep
+    Match kubernetes.*
+    Regex  $kubernetes['namespace_name'] ^prod-
+    Exclude  log ^DEBUG
+    Exclude  log ^TRACE
+
+[FILTER]
+    Name lua
+    Match *
+    call  mask
+    protected_mode  true
+    script  /fluent-bit/lua/1/mask.lua
+
+[FILTER]
+    Name rewrite_tag
+    Match *
+    emitter_name  re_emitted
+    Rule  $level ^(error)$ errors.$TAG false
+
+[FILTER]
+    Name throttle
+    Match *
+    interval  1s
+    rate  1000
+    window  5
+
+[FILTER]
+    Name nest
+    Match *
+    nest_under  pod
+    operation  nest
+    Wildcard  pod_*
+
+[FILTER]
+    Name multiline
+    Match *
+    multiline.key_content  log
+    multiline.parser  go,java
+`
+	require.True(t, strings.HasSuffix(config, expected), "unexpected config:\n%s", config)
+}
+
+func TestFiltersInvalid(t *testing.T) {
+	testCases := map[string][]v1beta1.FluentbitFilter{
+		"no filter type": {
+			{Match: "*"},
+		},
+		"multiple filter types": {
+			{
+				Grep:     &v1beta1.FluentbitGrepFilter{Regex: []string{"log .*"}},
+				Throttle: &v1beta1.FluentbitThrottleFilter{Rate: 10},
+			},
+		},
+		"grep without rules": {
+			{Grep: &v1beta1.FluentbitGrepFilter{LogicalOp: "AND"}},
+		},
+		"lua without script key": {
+			{Lua: &v1beta1.FluentbitLuaFilter{
+				Script: corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "lua-scripts"}},
+				Call:   "mask",
+			}},
+		},
+		"rewrite_tag without rules": {
+			{RewriteTag: &v1beta1.FluentbitRewriteTagFilter{}},
+		},
+		"invalid nest operation": {
+			{Nest: &v1beta1.FluentbitNestFilter{Operation: "flatten"}},
+		},
+		"multiline without parsers": {
+			{Multiline: &v1beta1.FluentbitMultilineFilter{}},
+		},
+	}
+	for name, filters := range testCases {
+		filters := filters
+		t.Run(name, func(t *testing.T) {
+			_, err := newFilters(filters)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLuaScriptVolumes(t *testing.T) {
+	volumes, mounts := luaScriptVolumes([]v1beta1.FluentbitFilter{
+		{Grep: &v1beta1.FluentbitGrepFilter{Regex: []string{"log .*"}}},
+		{Lua: &v1beta1.FluentbitLuaFilter{
+			Script: corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "lua-scripts"},
+				Key:                  "mask.lua",
+			},
+			Call: "mask",
+		}},
+	})
+	require.Len(t, volumes, 1)
+	require.Len(t, mounts, 1)
+	require.Equal(t, "lua-script-1", volumes[0].Name)
+	require.Equal(t, "lua-scripts", volumes[0].ConfigMap.Name)
+	require.Equal(t, []corev1.KeyToPath{{Key: "mask.lua", Path: "mask.lua"}}, volumes[0].ConfigMap.Items)
+	require.Equal(t, "/fluent-bit/lua/1", mounts[0].MountPath)
+}
+
+func TestMultipleTailInputsConfig(t *testing.T) {
+	tailInputs := []v1beta1.FluentbitTailInput{
+		{
+			TagPrefix: "kube-system",
+			InputTail: v1beta1.InputTail{
+				Path:        "/var/log/containers/*_kube-system_*.log",
+				DB:          utils.StringPointer("/tail-db/tail-kube-system-state.db"),
+				MemBufLimit: "50MB",
+				Tag:         "kube-system.*",
+			},
+		},
+		{
+			TagPrefix: "noisy",
+			InputTail: v1beta1.InputTail{
+				Path:          "/var/log/containers/*_noisy_*.log",
+				DB:            utils.StringPointer("/tail-db/tail-noisy-state.db"),
+				SkipLongLines: "On",
+				StorageType:   "filesystem",
+				Tag:           "noisy.*",
+			},
+		},
+	}
+	inputs, err := newTailInputs(tailInputs, v1beta1.FilterKubernetes{Match: "kubernetes.*"})
+	require.NoError(t, err)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:                1,
+		Grace:                5,
+		LogLevel:             "info",
+		CoroStackSize:        24576,
+		DefaultParsers:       "/fluent-bit/etc/parsers.conf",
+		Inputs:               inputs,
+		AggregatorMatchRegex: aggregatorMatchRegex([]string{"kube-system", "noisy"}),
+		FluentForwardOutput: &fluentForwardOutputConfig{
+			TargetHost: "fluentd.logging.svc",
+			TargetPort: 24240,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, config, `
+[INPUT]
+    Name         tail
+    DB  /tail-db/tail-kube-system-state.db
+    Mem_Buf_Limit  50MB
+    Path  /var/log/containers/*_kube-system_*.log
+    Tag  kube-system.*
+
+[INPUT]
+    Name         tail
+    DB  /tail-db/tail-noisy-state.db
+    Path  /var/log/containers/*_noisy_*.log
+    Skip_Long_Lines  On
+    Tag  noisy.*
+    storage.type  filesystem
+`)
+	require.Contains(t, config, "    Kube_Tag_Prefix  kube-system.var.log.containers\n")
+	require.Contains(t, config, "    Match  kube-system.*\n")
+	require.Contains(t, config, "    Kube_Tag_Prefix  noisy.var.log.containers\n")
+	require.Contains(t, config, "    Match  noisy.*\n")
+	require.NotContains(t, config, "Match  kubernetes.*")
+	require.Contains(t, config, `
+[OUTPUT]
+    Name          forward
+    Match_Regex   ^(kube-system|noisy)\.
+`)
+}
+
+func TestMultipleTailInputsWithRewriteTag(t *testing.T) {
+	spec := v1beta1.FluentbitSpec{
+		InputTails: []v1beta1.FluentbitTailInput{
+			{TagPrefix: "kube-system", InputTail: v1beta1.InputTail{Path: "/var/log/containers/*_kube-system_*.log", Tag: "kube-system.*"}},
+			{TagPrefix: "noisy", InputTail: v1beta1.InputTail{Path: "/var/log/containers/*_noisy_*.log", Tag: "noisy.*"}},
+		},
+		Filters: []v1beta1.FluentbitFilter{
+			{
+				Match: "noisy.*",
+				RewriteTag: &v1beta1.FluentbitRewriteTagFilter{
+					Rules: []string{`$level ^(error)$ errors.$TAG false`, `$level ^(warn)$ noisy.warn.$TAG false`},
+				},
+			},
+		},
+	}
+	inputs, err := newTailInputs(spec.InputTails, v1beta1.FilterKubernetes{})
+	require.NoError(t, err)
+	filters, err := newFilters(spec.Filters)
+	require.NoError(t, err)
+	tagPrefixes, err := aggregatorTagPrefixes(spec)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kube-system", "noisy", "errors"}, tagPrefixes)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:                1,
+		Grace:                5,
+		LogLevel:             "info",
+		CoroStackSize:        24576,
+		DefaultParsers:       "/fluent-bit/etc/parsers.conf",
+		Inputs:               inputs,
+		Filters:              filters,
+		AggregatorMatchRegex: aggregatorMatchRegex(tagPrefixes),
+		FluentForwardOutput: &fluentForwardOutputConfig{
+			TargetHost: "fluentd.logging.svc",
+			TargetPort: 24240,
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, config, "    Rule  $level ^(error)$ errors.$TAG false\n")
+	require.Contains(t, config, `
+[OUTPUT]
+    Name          forward
+    Match_Regex   ^(kube-system|noisy|errors)\.
+`)
+
+	// a tag set by a record accessor can't be matched in advance, so every record is forwarded
+	spec.Filters[0].RewriteTag.Rules = []string{`$kubernetes['namespace_name'] ^(.+)$ $kubernetes['namespace_name'].$TAG false`}
+	tagPrefixes, err = aggregatorTagPrefixes(spec)
+	require.NoError(t, err)
+	require.Nil(t, tagPrefixes)
+}
+
+func TestKubeTagPrefix(t *testing.T) {
+	testCases := map[string]struct {
+		tag     string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"default": {
+			tag:  "app.*",
+			path: "/var/log/containers/*.log",
+			want: "app.var.log.containers",
+		},
+		"nested tag and custom directory": {
+			tag:  "app.kube.*",
+			path: "/var/log/pods-link/*_app_*.log",
+			want: "app.kube.var.log.pods-link",
+		},
+		"multiple paths": {
+			tag:  "app.*",
+			path: "/var/log/containers/*_a_*.log, /var/log/containers/*_b_*.log",
+			want: "app.var.log.containers",
+		},
+		"paths in different directories": {
+			tag:     "app.*",
+			path:    "/var/log/containers/*.log,/var/log/other/*.log",
+			wantErr: true,
+		},
+		"wildcard directory": {
+			tag:     "app.*",
+			path:    "/var/log/pods/*/*/*.log",
+			wantErr: true,
+		},
+		"tag without wildcard": {
+			tag:     "app.logs",
+			path:    "/var/log/containers/*.log",
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			prefix, err := kubeTagPrefix(testCase.tag, testCase.path)
+			if testCase.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.want, prefix)
+		})
+	}
+}
+
+func TestTailInputsInvalid(t *testing.T) {
+	testCases := map[string][]v1beta1.FluentbitTailInput{
+		"missing tag prefix": {
+			{InputTail: v1beta1.InputTail{Tag: "kubernetes.*"}},
+		},
+		"duplicate tag prefix": {
+			{TagPrefix: "app", InputTail: v1beta1.InputTail{Tag: "app.*"}},
+			{TagPrefix: "app", InputTail: v1beta1.InputTail{Tag: "app.*"}},
+		},
+		"tag without prefix": {
+			{TagPrefix: "app", InputTail: v1beta1.InputTail{Tag: "kubernetes.*"}},
+		},
+	}
+	for name, inputs := range testCases {
+		inputs := inputs
+		t.Run(name, func(t *testing.T) {
+			_, err := newTailInputs(inputs, v1beta1.FilterKubernetes{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSystemdInputConfig(t *testing.T) {
+	systemdInput, err := newSystemdInput(v1beta1.InputSystemd{
+		Units:            []string{"kubelet.service", "containerd.service"},
+		StripUnderscores: "On",
+	})
+	require.NoError(t, err)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:                   1,
+		Grace:                   5,
+		LogLevel:                "info",
+		CoroStackSize:           24576,
+		DefaultParsers:          "/fluent-bit/etc/parsers.conf",
+		DisableKubernetesFilter: true,
+		SystemdInput:            systemdInput,
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, config, `
+[INPUT]
+    Name         systemd
+    Tag          systemd.*
+    Systemd_Filter  _SYSTEMD_UNIT=kubelet.service
+    Systemd_Filter  _SYSTEMD_UNIT=containerd.service
+    Systemd_Filter_Type  Or
+    DB  /tail-db/systemd-state.db
+    Path  /var/log/journal
+    Read_From_Tail  On
+    Strip_Underscores  On
+`)
+	require.Contains(t, config, `
+[FILTER]
+    Name        modify
+    Match       systemd.*
+    Copy        SYSTEMD_UNIT container_name
+    Copy        HOSTNAME host
+
+[FILTER]
+    Name        nest
+    Match       systemd.*
+    Operation   nest
+    Wildcard    container_name
+    Wildcard    host
+    Nest_under  kubernetes
+`)
+}
+
+func TestAuditLogsInputConfig(t *testing.T) {
+	auditLogsInput, err := newAuditLogsInput(v1beta1.FluentbitAuditLogs{})
+	require.NoError(t, err)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:                   1,
+		Grace:                   5,
+		LogLevel:                "info",
+		CoroStackSize:           24576,
+		DefaultParsers:          "/fluent-bit/etc/parsers.conf",
+		DisableKubernetesFilter: true,
+		AuditLogsInput:          auditLogsInput,
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, config, `
+[INPUT]
+    Name         tail
+    Tag          audit.*
+    Parser       json
+    Buffer_Max_Size  1MB
+    DB  /tail-db/tail-audit-state.db
+    Mem_Buf_Limit  5MB
+    Path  /var/log/kubernetes/audit/audit.log
+`)
+	require.Contains(t, config, `
+[FILTER]
+    Name        modify
+    Match       audit.*
+    Set         container_name kube-apiserver-audit
+    Set         host ${FLUENTBIT_NODE_NAME}
+
+[FILTER]
+    Name        nest
+    Match       audit.*
+    Operation   nest
+    Wildcard    container_name
+    Wildcard    host
+    Nest_under  kubernetes
+`)
+}
+
+func TestShardForwardOutputsConfig(t *testing.T) {
+	shardHost := func(shard int32) string {
+		return fmt.Sprintf("logging-shard%d-fluentd.logging.svc", shard)
+	}
+	shardNamespaces := map[int32][]string{
+		0: {"default"},
+		1: {"team-a", "team-c"},
+		2: {"team-b"},
+		3: nil,
+	}
+
+	output := &fluentForwardOutputConfig{
+		TargetHost: "logging-fluentd.logging.svc",
+		TargetPort: 24240,
+	}
+	shardOutputs := newShardForwardOutputs(output, shardNamespaces, nil, shardHost)
+	require.Len(t, shardOutputs, 2)
+
+	config, err := generateConfig(fluentBitConfig{
+		Flush:               1,
+		Grace:               5,
+		LogLevel:            "info",
+		CoroStackSize:       24576,
+		DefaultParsers:      "/fluent-bit/etc/parsers.conf",
+		FluentForwardOutput: output,
+		ShardForwardOutputs: shardOutputs,
+	})
+	require.NoError(t, err)
+	require.Contains(t, config, `
+[OUTPUT]
+    Name          forward
+    Match_Regex   ^(?![^.]+\.var\.log\.containers\.[^_]+_(team-a|team-b|team-c)_)
+    Host          logging-fluentd.logging.svc
+    Port          24240
+`)
+	require.Contains(t, config, `
+[OUTPUT]
+    Name          forward
+    Match_Regex   ^[^.]+\.var\.log\.containers\.[^_]+_(team-a|team-c)_
+    Host          logging-shard1-fluentd.logging.svc
+    Port          24240
+`)
+	require.Contains(t, config, `
+[OUTPUT]
+    Name          forward
+    Match_Regex   ^[^.]+\.var\.log\.containers\.[^_]+_(team-b)_
+    Host          logging-shard2-fluentd.logging.svc
+    Port          24240
+`)
+	require.NotContains(t, config, "logging-shard3-fluentd")
+
+	// the records of the other inputs stay on the first shard
+	output = &fluentForwardOutputConfig{TargetHost: "logging-fluentd.logging.svc"}
+	shardOutputs = newShardForwardOutputs(output, shardNamespaces, []string{"kube-system", "audit"}, shardHost)
+	require.Equal(t, `^(kube-system|audit)\.(?!var\.log\.containers\.[^_]+_(team-a|team-b|team-c)_)`, output.MatchRegex)
+	require.Equal(t, `^(kube-system|audit)\.var\.log\.containers\.[^_]+_(team-a|team-c)_`, shardOutputs[0].MatchRegex)
+
+	// without namespaces assigned to the other shards everything goes to the first one
+	output = &fluentForwardOutputConfig{TargetHost: "logging-fluentd.logging.svc"}
+	require.Nil(t, newShardForwardOutputs(output, map[int32][]string{0: {"default"}}, nil, shardHost))
+	require.Empty(t, output.MatchRegex)
+}
+
+func TestSyslogNGOutputTLS(t *testing.T) {
+	config, err := generateConfig(fluentBitConfig{
+		Flush:          1,
+		Grace:          5,
+		LogLevel:       "info",
+		CoroStackSize:  24576,
+		DefaultParsers: "/fluent-bit/etc/parsers.conf",
+		SyslogNGOutput: &syslogNGOutputConfig{
+			Host: "logging-syslog-ng.logging.svc.cluster.local",
+			Port: 601,
+			TLS:  true,
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, config, `
+[OUTPUT]
+    Name tcp
+    Match *
+    Host logging-syslog-ng.logging.svc.cluster.local
+    Port 601
+    Format json_lines
+    tls           On
+    tls.verify    On
+    tls.ca_file   /fluent-bit/tls/ca.crt
+    tls.crt_file  /fluent-bit/tls/tls.crt
+    tls.key_file  /fluent-bit/tls/tls.key
+`)
+}
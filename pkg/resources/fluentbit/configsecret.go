@@ -33,9 +33,10 @@ import (
 )
 
 type fluentbitInputConfig struct {
-	Values          map[string]string
-	ParserN         []string
-	MultilineParser []string
+	Values           map[string]string
+	ParserN          []string
+	MultilineParser  []string
+	KubernetesFilter map[string]string
 }
 
 type upstreamNode struct {
@@ -62,18 +63,31 @@ type fluentBitConfig struct {
 	LogLevel                string
 	CoroStackSize           int32
 	Output                  map[string]string
-	Input                   fluentbitInputConfig
+	Inputs                  []fluentbitInputConfig
+	SystemdInput            *systemdInputConfig
+	AuditLogsInput          *auditLogsInputConfig
 	DisableKubernetesFilter bool
-	KubernetesFilter        map[string]string
 	AwsFilter               map[string]string
 	BufferStorage           map[string]string
 	FilterModify            []v1beta1.FilterModify
+	Filters                 []filterConfig
 	FluentForwardOutput     *fluentForwardOutputConfig
+	ShardForwardOutputs     []fluentForwardOutputConfig
 	SyslogNGOutput          *syslogNGOutputConfig
+	DirectOutputs           []directOutputConfig
+	AggregatorMatchRegex    string
 	DefaultParsers          string
 	CustomParsers           string
 }
 
+// ForwardOutputs returns the forward output of the aggregator followed by the outputs of its other shards
+func (c fluentBitConfig) ForwardOutputs() []fluentForwardOutputConfig {
+	if c.FluentForwardOutput == nil {
+		return nil
+	}
+	return append([]fluentForwardOutputConfig{*c.FluentForwardOutput}, c.ShardForwardOutputs...)
+}
+
 type fluentForwardOutputConfig struct {
 	Network    FluentbitNetwork
 	Options    map[string]string
@@ -81,6 +95,8 @@ type fluentForwardOutputConfig struct {
 	TargetPort int32
 	TLS        fluentForwardOutputTLSConfig
 	Upstream   fluentForwardOutputUpstreamConfig
+	// MatchRegex restricts the output to the records of its shard
+	MatchRegex string
 }
 
 type fluentForwardOutputTLSConfig struct {
@@ -118,6 +134,7 @@ type syslogNGOutputConfig struct {
 	JSONDateFormat string
 	Workers        *int
 	Network        FluentbitNetwork
+	TLS            bool
 }
 
 func newFluentbitNetwork(network v1beta1.FluentbitNetwork) (result FluentbitNetwork) {
@@ -209,17 +226,19 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 	}
 
 	if r.fluentbitSpec.InputTail.Parser == "" {
-		switch types.ContainerRuntime {
-		case "docker":
-			r.fluentbitSpec.InputTail.Parser = "docker"
-		case "containerd":
-			r.fluentbitSpec.InputTail.Parser = "cri"
-		default:
-			r.fluentbitSpec.InputTail.Parser = "cri"
+		r.fluentbitSpec.InputTail.Parser = defaultTailParser()
+	}
+	for i := range r.fluentbitSpec.InputTails {
+		if r.fluentbitSpec.InputTails[i].Parser == "" {
+			r.fluentbitSpec.InputTails[i].Parser = defaultTailParser()
 		}
 	}
 
-	if r.Logging.Spec.FluentdSpec != nil {
+	if r.fluentbitSpec.DisableAggregatorOutput && len(r.fluentbitSpec.DirectOutputs) == 0 {
+		return nil, reconciler.StatePresent, errors.New("disableAggregatorOutput requires at least one direct output")
+	}
+
+	if r.Logging.Spec.FluentdSpec != nil && !r.fluentbitSpec.DisableAggregatorOutput {
 		fluentbitTargetHost := r.fluentbitSpec.TargetHost
 		if fluentbitTargetHost == "" {
 			fluentbitTargetHost = fmt.Sprintf("%s.%s.svc%s", r.Logging.QualifiedName(fluentd.ServiceName), r.Logging.Spec.ControlNamespace, r.Logging.ClusterDomainAsSuffix())
@@ -243,40 +262,52 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 	mapper := types.NewStructToStringMapper(nil)
 
 	// FluentBit input Values
-	inputTail := r.fluentbitSpec.InputTail
-
-	if len(inputTail.MultilineParser) > 0 {
-		input.Input.MultilineParser = inputTail.MultilineParser
-		inputTail.MultilineParser = nil
-
-		// If MultilineParser is set, remove other parser fields
-		// See https://docs.fluentbit.io/manual/pipeline/inputs/tail#multiline-core-v1.8
-
-		r.logger.Info("Notice: MultilineParser is enabled. Disabling other parser options")
-
-		inputTail.Parser = ""
-		inputTail.ParserFirstline = ""
-		inputTail.ParserN = nil
-		inputTail.Multiline = ""
-		inputTail.MultilineFlush = ""
-		inputTail.DockerMode = ""
-		inputTail.DockerModeFlush = ""
-		inputTail.DockerModeParser = ""
+	var err error
+	if len(r.fluentbitSpec.InputTails) > 0 {
+		for _, inputTail := range r.fluentbitSpec.InputTails {
+			if len(inputTail.MultilineParser) > 0 {
+				r.logger.Info("Notice: MultilineParser is enabled. Disabling other parser options", "tagPrefix", inputTail.TagPrefix)
+			}
+		}
+		input.Inputs, err = newTailInputs(r.fluentbitSpec.InputTails, r.fluentbitSpec.FilterKubernetes)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to configure tail inputs for fluentbit")
+		}
+	} else {
+		if len(r.fluentbitSpec.InputTail.MultilineParser) > 0 {
+			r.logger.Info("Notice: MultilineParser is enabled. Disabling other parser options")
+		}
+		tailInput, err := newTailInput(r.fluentbitSpec.InputTail)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map container tailer config for fluentbit")
+		}
+		tailInput.KubernetesFilter, err = mapper.StringsMap(r.fluentbitSpec.FilterKubernetes)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map kubernetes filter for fluentbit")
+		}
+		input.Inputs = []fluentbitInputConfig{tailInput}
+	}
 
-	} else if len(inputTail.ParserN) > 0 {
-		input.Input.ParserN = inputTail.ParserN
-		inputTail.ParserN = nil
+	if r.fluentbitSpec.InputSystemd != nil {
+		input.SystemdInput, err = newSystemdInput(*r.fluentbitSpec.InputSystemd)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map systemd input for fluentbit")
+		}
 	}
 
-	fluentbitInputValues, err := mapper.StringsMap(inputTail)
-	if err != nil {
-		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map container tailer config for fluentbit")
+	if r.fluentbitSpec.AuditLogs != nil {
+		input.AuditLogsInput, err = newAuditLogsInput(*r.fluentbitSpec.AuditLogs)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map audit logs input for fluentbit")
+		}
 	}
-	input.Input.Values = fluentbitInputValues
 
-	input.KubernetesFilter, err = mapper.StringsMap(r.fluentbitSpec.FilterKubernetes)
+	tagPrefixes, err := aggregatorTagPrefixes(*r.fluentbitSpec)
 	if err != nil {
-		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map kubernetes filter for fluentbit")
+		return nil, reconciler.StatePresent, err
+	}
+	if tagPrefixes != nil {
+		input.AggregatorMatchRegex = aggregatorMatchRegex(tagPrefixes)
 	}
 
 	input.BufferStorage, err = mapper.StringsMap(r.fluentbitSpec.BufferStorage)
@@ -325,6 +356,19 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 			r.logger.Info("Notice: fluentbit `network` settings have been configured automatically to adapt to multiple aggregator replicas. Configure it manually to avoid this notice.")
 		}
 
+		shardNamespaces := r.loggingDataProvider.GetShardNamespaces()
+		if shardNamespaces != nil {
+			if r.fluentbitSpec.EnableUpstream {
+				return nil, reconciler.StatePresent, errors.New("enableUpstream can't be used with fluentd shards")
+			}
+			if r.fluentbitSpec.TargetHost != "" {
+				return nil, reconciler.StatePresent, errors.New("targetHost can't be used with fluentd shards")
+			}
+		}
+		input.ShardForwardOutputs = newShardForwardOutputs(input.FluentForwardOutput, shardNamespaces, tagPrefixes, func(shard int32) string {
+			return fmt.Sprintf("%s.%s.svc%s", r.Logging.FluentdShardQualifiedName(shard, fluentd.ServiceName), r.Logging.Spec.ControlNamespace, r.Logging.ClusterDomainAsSuffix())
+		})
+
 		if r.fluentbitSpec.EnableUpstream {
 			input.FluentForwardOutput.Upstream.Enabled = true
 			input.FluentForwardOutput.Upstream.Config.Path = fmt.Sprintf("%s/%s", OperatorConfigPath, UpstreamConfigName)
@@ -335,7 +379,7 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 		}
 	}
 
-	if r.Logging.Spec.SyslogNGSpec != nil {
+	if r.Logging.Spec.SyslogNGSpec != nil && !r.fluentbitSpec.DisableAggregatorOutput {
 		input.SyslogNGOutput = &syslogNGOutputConfig{}
 		input.SyslogNGOutput.Host = fmt.Sprintf("%s.%s.svc%s", r.Logging.QualifiedName(syslogng.ServiceName), r.Logging.Spec.ControlNamespace, r.Logging.ClusterDomainAsSuffix())
 		input.SyslogNGOutput.Port = syslogng.ServicePort
@@ -346,6 +390,14 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 			input.SyslogNGOutput.JSONDateKey = r.fluentbitSpec.SyslogNGOutput.JsonDateKey
 			input.SyslogNGOutput.JSONDateFormat = r.fluentbitSpec.SyslogNGOutput.JsonDateFormat
 		}
+
+		if r.Logging.Spec.SyslogNGSpec.TLS.Enabled {
+			// the client certificate is taken from the TLS secret of fluent-bit, as syslog-ng verifies the peer by default
+			if !*r.fluentbitSpec.TLS.Enabled {
+				return nil, reconciler.StatePresent, errors.New("TLS is enabled on syslog-ng, enable TLS on fluent-bit as well to provide the client certificate")
+			}
+			input.SyslogNGOutput.TLS = true
+		}
 	}
 
 	if input.SyslogNGOutput != nil {
@@ -354,6 +406,17 @@ func (r *Reconciler) configSecret() (runtime.Object, reconciler.DesiredState, er
 		}
 	}
 
+	input.Filters, err = newFilters(r.fluentbitSpec.Filters)
+	if err != nil {
+		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to configure filters for fluentbit")
+	}
+
+	// the secrets are referenced by the environment variables set by directOutputEnvVars
+	input.DirectOutputs, err = newDirectOutputs(r.fluentbitSpec.DirectOutputs, newEnvSecretLoader())
+	if err != nil {
+		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to configure direct outputs for fluentbit")
+	}
+
 	conf, err := generateConfig(input)
 	if err != nil {
 		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to generate config for fluentbit")
@@ -20,9 +20,11 @@ import (
 	"strconv"
 	"strings"
 
+	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	util "github.com/cisco-open/operator-tools/pkg/utils"
 
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
 	"github.com/kube-logging/logging-operator/pkg/resources/templates"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 
@@ -55,8 +57,12 @@ func (r *Reconciler) daemonSet() (runtime.Object, reconciler.DesiredState, error
 		}
 	}
 
+	outputEnvVars, err := directOutputEnvVars(r.fluentbitSpec.DirectOutputs)
+	if err != nil {
+		return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to configure direct outputs for fluentbit")
+	}
 	containers := []corev1.Container{
-		*r.fluentbitContainer(),
+		*r.fluentbitContainer(outputEnvVars),
 	}
 	if c := r.bufferMetricsSidecarContainer(); c != nil {
 		containers = append(containers, *c)
@@ -76,10 +82,11 @@ func (r *Reconciler) daemonSet() (runtime.Object, reconciler.DesiredState, error
 					Affinity:           r.fluentbitSpec.Affinity,
 					PriorityClassName:  r.fluentbitSpec.PodPriorityClassName,
 					SecurityContext: &corev1.PodSecurityContext{
-						FSGroup:      r.fluentbitSpec.Security.PodSecurityContext.FSGroup,
-						RunAsNonRoot: r.fluentbitSpec.Security.PodSecurityContext.RunAsNonRoot,
-						RunAsUser:    r.fluentbitSpec.Security.PodSecurityContext.RunAsUser,
-						RunAsGroup:   r.fluentbitSpec.Security.PodSecurityContext.RunAsGroup,
+						FSGroup:        r.fluentbitSpec.Security.PodSecurityContext.FSGroup,
+						RunAsNonRoot:   r.fluentbitSpec.Security.PodSecurityContext.RunAsNonRoot,
+						RunAsUser:      r.fluentbitSpec.Security.PodSecurityContext.RunAsUser,
+						RunAsGroup:     r.fluentbitSpec.Security.PodSecurityContext.RunAsGroup,
+						SeccompProfile: r.fluentbitSpec.Security.PodSecurityContext.SeccompProfile,
 					},
 					ImagePullSecrets: r.fluentbitSpec.Image.ImagePullSecrets,
 					DNSPolicy:        r.fluentbitSpec.DNSPolicy,
@@ -105,10 +112,14 @@ func (r *Reconciler) daemonSet() (runtime.Object, reconciler.DesiredState, error
 		return desired, reconciler.StatePresent, err
 	}
 
+	if err := podsecurity.Validate(r.fluentbitSpec.Security.PodSecurityStandard, desired.Spec.Template.Spec); err != nil {
+		return desired, reconciler.StatePresent, errors.WrapIf(err, "fluentbit daemonset")
+	}
+
 	return desired, reconciler.StatePresent, nil
 }
 
-func (r *Reconciler) fluentbitContainer() *corev1.Container {
+func (r *Reconciler) fluentbitContainer(outputEnvVars []corev1.EnvVar) *corev1.Container {
 	return &corev1.Container{
 		Name:            containerName,
 		Image:           r.fluentbitSpec.Image.RepositoryWithTag(),
@@ -123,16 +134,32 @@ func (r *Reconciler) fluentbitContainer() *corev1.Container {
 			AllowPrivilegeEscalation: r.fluentbitSpec.Security.SecurityContext.AllowPrivilegeEscalation,
 			Privileged:               r.fluentbitSpec.Security.SecurityContext.Privileged,
 			SELinuxOptions:           r.fluentbitSpec.Security.SecurityContext.SELinuxOptions,
+			Capabilities:             r.fluentbitSpec.Security.SecurityContext.Capabilities,
+			SeccompProfile:           r.fluentbitSpec.Security.SecurityContext.SeccompProfile,
 		},
 		Command: []string{
 			StockBinPath, "-c", fmt.Sprintf("%s/%s", OperatorConfigPath, BaseConfigName),
 		},
-		Env:            r.fluentbitSpec.EnvVars,
+		Env:            r.generateEnvVars(outputEnvVars),
 		LivenessProbe:  r.fluentbitSpec.LivenessProbe,
 		ReadinessProbe: r.fluentbitSpec.ReadinessProbe,
 	}
 }
 
+func (r *Reconciler) generateEnvVars(outputEnvVars []corev1.EnvVar) []corev1.EnvVar {
+	env := append(append([]corev1.EnvVar{}, r.fluentbitSpec.EnvVars...), outputEnvVars...)
+	if r.fluentbitSpec.AuditLogs != nil {
+		// the node name is added to the audit events, as they do not contain it
+		env = append(env, corev1.EnvVar{
+			Name: nodeNameEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+			},
+		})
+	}
+	return env
+}
+
 func (r *Reconciler) generatePortsMetrics() (containerPorts []corev1.ContainerPort) {
 	if r.fluentbitSpec.Metrics != nil && r.fluentbitSpec.Metrics.Port != 0 {
 		containerPorts = append(containerPorts, corev1.ContainerPort{
@@ -170,6 +197,9 @@ func (r *Reconciler) generateVolumeMounts() (v []corev1.VolumeMount) {
 		})
 	}
 
+	_, luaMounts := luaScriptVolumes(r.fluentbitSpec.Filters)
+	v = append(v, luaMounts...)
+
 	if *r.fluentbitSpec.TLS.Enabled {
 		tlsRelatedVolume := []corev1.VolumeMount{
 			{
@@ -232,6 +262,9 @@ func (r *Reconciler) generateVolume() (v []corev1.Volume) {
 			},
 		})
 	}
+	luaVolumes, _ := luaScriptVolumes(r.fluentbitSpec.Filters)
+	v = append(v, luaVolumes...)
+
 	if *r.fluentbitSpec.TLS.Enabled {
 		tlsRelatedVolume := corev1.Volume{
 			Name: "fluent-bit-tls",
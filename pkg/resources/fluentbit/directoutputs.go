@@ -0,0 +1,206 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentbit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+type directOutputConfig struct {
+	Name    string
+	Match   string
+	Params  map[string]string
+	Headers map[string]string
+}
+
+// envSecretLoader passes secrets to fluent-bit as environment variables and references them in the config,
+// so that their values never end up in the config secret
+type envSecretLoader struct {
+	envVars map[string]corev1.EnvVar
+}
+
+func newEnvSecretLoader() *envSecretLoader {
+	return &envSecretLoader{envVars: make(map[string]corev1.EnvVar)}
+}
+
+func (l *envSecretLoader) Load(s *secret.Secret) (string, error) {
+	name, err := l.add("", s)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return s.Value, nil
+	}
+	return fmt.Sprintf("${%s}", name), nil
+}
+
+// add registers the secret as an environment variable and returns its name, or an empty name for inline values.
+// If envName is empty a name is generated from the referenced secret.
+func (l *envSecretLoader) add(envName string, s *secret.Secret) (string, error) {
+	if s.MountFrom != nil {
+		return "", errors.New("mountFrom is not supported by fluent-bit outputs, use valueFrom")
+	}
+	var envVar corev1.EnvVar
+	if s.ValueFrom != nil && s.ValueFrom.SecretKeyRef != nil {
+		ref := s.ValueFrom.SecretKeyRef
+		if envName == "" {
+			envName = envVarName("FLUENTBIT_SECRET", ref.Name, ref.Key)
+		}
+		envVar = corev1.EnvVar{
+			Name: envName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: ref.DeepCopy(),
+			},
+		}
+	} else {
+		if envName == "" {
+			return "", nil
+		}
+		envVar = corev1.EnvVar{Name: envName, Value: s.Value}
+	}
+	if existing, ok := l.envVars[envName]; ok && !reflect.DeepEqual(existing, envVar) {
+		return "", errors.Errorf("conflicting values for environment variable %s", envName)
+	}
+	l.envVars[envName] = envVar
+	return envName, nil
+}
+
+// EnvVars returns the collected environment variables ordered by name
+func (l *envSecretLoader) EnvVars() []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, envVar := range l.envVars {
+		envVars = append(envVars, envVar)
+	}
+	sort.Slice(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
+	return envVars
+}
+
+func envVarName(parts ...string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return '_'
+	}, strings.Join(parts, "_"))
+}
+
+// directOutputEnvVars returns the environment variables of the fluent-bit container that hold the secrets of the direct outputs
+func directOutputEnvVars(outputs []v1beta1.FluentbitDirectOutput) ([]corev1.EnvVar, error) {
+	loader := newEnvSecretLoader()
+	if _, err := newDirectOutputs(outputs, loader); err != nil {
+		return nil, err
+	}
+	return loader.EnvVars(), nil
+}
+
+func newDirectOutputs(outputs []v1beta1.FluentbitDirectOutput, loader *envSecretLoader) ([]directOutputConfig, error) {
+	mapper := types.NewStructToStringMapper(loader)
+	var result []directOutputConfig
+	for i, output := range outputs {
+		config := directOutputConfig{
+			Match: output.Match,
+		}
+		if config.Match == "" {
+			config.Match = "*"
+		}
+
+		var plugin interface{}
+		var kinds []string
+		if output.Loki != nil {
+			config.Name, plugin = "loki", output.Loki
+			kinds = append(kinds, "loki")
+		}
+		if output.Elasticsearch != nil {
+			config.Name, plugin = "es", output.Elasticsearch
+			kinds = append(kinds, "elasticsearch")
+		}
+		if output.OpenTelemetry != nil {
+			config.Name, plugin = "opentelemetry", output.OpenTelemetry
+			kinds = append(kinds, "opentelemetry")
+		}
+		if output.Kafka != nil {
+			config.Name, plugin = "kafka", output.Kafka
+			kinds = append(kinds, "kafka")
+		}
+		if output.S3 != nil {
+			config.Name, plugin = "s3", output.S3
+			kinds = append(kinds, "s3")
+		}
+		if len(kinds) != 1 {
+			return nil, errors.Errorf("direct output #%d must have exactly one output type, got %v", i, kinds)
+		}
+
+		params, err := mapper.StringsMap(plugin)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to map direct output #%d (%s)", i, kinds[0])
+		}
+		if output.RetryLimit != "" {
+			params["Retry_Limit"] = output.RetryLimit
+		}
+		if output.StorageTotalLimitSize != "" {
+			params["storage.total_limit_size"] = output.StorageTotalLimitSize
+		}
+
+		switch {
+		case output.OpenTelemetry != nil:
+			if len(output.OpenTelemetry.Headers) > 0 {
+				config.Headers = make(map[string]string)
+			}
+			for key, value := range output.OpenTelemetry.Headers {
+				if value == nil {
+					continue
+				}
+				if config.Headers[key], err = loader.Load(value); err != nil {
+					return nil, errors.WrapIff(err, "failed to load header %q of direct output #%d", key, i)
+				}
+			}
+		case output.Kafka != nil:
+			for key, value := range output.Kafka.RDKafkaOptions {
+				params["rdkafka."+key] = value
+			}
+		case output.S3 != nil:
+			// the s3 plugin reads static credentials from the standard AWS environment variables only
+			if output.S3.AccessKeyID != nil {
+				if _, err := loader.add("AWS_ACCESS_KEY_ID", output.S3.AccessKeyID); err != nil {
+					return nil, errors.WrapIff(err, "failed to load access key id of direct output #%d", i)
+				}
+			}
+			if output.S3.SecretAccessKey != nil {
+				if _, err := loader.add("AWS_SECRET_ACCESS_KEY", output.S3.SecretAccessKey); err != nil {
+					return nil, errors.WrapIff(err, "failed to load secret access key of direct output #%d", i)
+				}
+			}
+		}
+
+		config.Params = params
+		result = append(result, config)
+	}
+	return result, nil
+}
@@ -0,0 +1,190 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentbit
+
+import (
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+const luaScriptsPath = "/fluent-bit/lua"
+
+type filterConfig struct {
+	Name   string
+	Match  string
+	Params map[string]string
+	// Repeated holds the keys that may occur multiple times, in their original order
+	Repeated []filterParam
+}
+
+type filterParam struct {
+	Key   string
+	Value string
+}
+
+func luaScriptVolumeName(index int) string {
+	return fmt.Sprintf("lua-script-%d", index)
+}
+
+func luaScriptDir(index int) string {
+	return fmt.Sprintf("%s/%d", luaScriptsPath, index)
+}
+
+func newFilters(filters []v1beta1.FluentbitFilter) ([]filterConfig, error) {
+	mapper := types.NewStructToStringMapper(nil)
+	var result []filterConfig
+	for i, filter := range filters {
+		config := filterConfig{
+			Match: filter.Match,
+		}
+		if config.Match == "" {
+			config.Match = "*"
+		}
+
+		var plugin interface{}
+		var kinds []string
+		if filter.Grep != nil {
+			config.Name, plugin = "grep", filter.Grep
+			kinds = append(kinds, "grep")
+		}
+		if filter.Lua != nil {
+			config.Name, plugin = "lua", filter.Lua
+			kinds = append(kinds, "lua")
+		}
+		if filter.RewriteTag != nil {
+			config.Name, plugin = "rewrite_tag", filter.RewriteTag
+			kinds = append(kinds, "rewriteTag")
+		}
+		if filter.Throttle != nil {
+			config.Name, plugin = "throttle", filter.Throttle
+			kinds = append(kinds, "throttle")
+		}
+		if filter.Nest != nil {
+			config.Name, plugin = "nest", filter.Nest
+			kinds = append(kinds, "nest")
+		}
+		if filter.Multiline != nil {
+			config.Name, plugin = "multiline", filter.Multiline
+			kinds = append(kinds, "multiline")
+		}
+		if len(kinds) != 1 {
+			return nil, errors.Errorf("filter #%d must have exactly one filter type, got %v", i, kinds)
+		}
+
+		params, err := mapper.StringsMap(plugin)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to map filter #%d (%s)", i, kinds[0])
+		}
+
+		switch {
+		case filter.Grep != nil:
+			if len(filter.Grep.Regex)+len(filter.Grep.Exclude) == 0 {
+				return nil, errors.Errorf("grep filter #%d must have at least one regex or exclude rule", i)
+			}
+			config.Repeated = appendFilterParams(config.Repeated, "Regex", filter.Grep.Regex)
+			config.Repeated = appendFilterParams(config.Repeated, "Exclude", filter.Grep.Exclude)
+		case filter.Lua != nil:
+			if filter.Lua.Script.Name == "" || filter.Lua.Script.Key == "" {
+				return nil, errors.Errorf("lua filter #%d must reference a script by ConfigMap name and key", i)
+			}
+			if filter.Lua.Call == "" {
+				return nil, errors.Errorf("lua filter #%d must specify the function to call", i)
+			}
+			params["script"] = fmt.Sprintf("%s/%s", luaScriptDir(i), filter.Lua.Script.Key)
+		case filter.RewriteTag != nil:
+			if len(filter.RewriteTag.Rules) == 0 {
+				return nil, errors.Errorf("rewriteTag filter #%d must have at least one rule", i)
+			}
+			config.Repeated = appendFilterParams(config.Repeated, "Rule", filter.RewriteTag.Rules)
+		case filter.Nest != nil:
+			if filter.Nest.Operation != "nest" && filter.Nest.Operation != "lift" {
+				return nil, errors.Errorf("nest filter #%d has invalid operation %q, must be nest or lift", i, filter.Nest.Operation)
+			}
+			config.Repeated = appendFilterParams(config.Repeated, "Wildcard", filter.Nest.Wildcard)
+		case filter.Multiline != nil:
+			if len(filter.Multiline.Parsers) == 0 {
+				return nil, errors.Errorf("multiline filter #%d must have at least one parser", i)
+			}
+			params["multiline.parser"] = strings.Join(filter.Multiline.Parsers, ",")
+		}
+
+		config.Params = params
+		result = append(result, config)
+	}
+	return result, nil
+}
+
+func appendFilterParams(params []filterParam, key string, values []string) []filterParam {
+	for _, value := range values {
+		params = append(params, filterParam{Key: key, Value: value})
+	}
+	return params
+}
+
+// luaScriptVolumes mounts the scripts of the lua filters from their ConfigMaps, each filter into its own directory
+func luaScriptVolumes(filters []v1beta1.FluentbitFilter) (volumes []corev1.Volume, mounts []corev1.VolumeMount) {
+	for i, filter := range filters {
+		if filter.Lua == nil || filter.Lua.Script.Name == "" || filter.Lua.Script.Key == "" {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: luaScriptVolumeName(i),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: filter.Lua.Script.LocalObjectReference,
+					Items: []corev1.KeyToPath{
+						{Key: filter.Lua.Script.Key, Path: filter.Lua.Script.Key},
+					},
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      luaScriptVolumeName(i),
+			ReadOnly:  true,
+			MountPath: luaScriptDir(i),
+		})
+	}
+	return
+}
+
+// rewriteTagPrefixes returns the first segments of the tags the rewriteTag filters emit the records with.
+// It returns false if a tag starts with a record accessor or a rule can't be parsed, so the prefix is not known in advance.
+func rewriteTagPrefixes(filters []v1beta1.FluentbitFilter) ([]string, bool) {
+	var prefixes []string
+	for _, filter := range filters {
+		if filter.RewriteTag == nil {
+			continue
+		}
+		for _, rule := range filter.RewriteTag.Rules {
+			// $KEY REGEX NEW_TAG KEEP
+			fields := strings.Fields(rule)
+			if len(fields) < 3 {
+				return nil, false
+			}
+			prefix, _, _ := strings.Cut(fields[2], ".")
+			if prefix == "" || strings.Contains(prefix, "$") {
+				return nil, false
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, true
+}
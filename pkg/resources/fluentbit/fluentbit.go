@@ -44,7 +44,6 @@ const (
 	clusterRoleName                = "fluentbit"
 	fluentBitSecretConfigName      = "fluentbit"
 	fluentbitDaemonSetName         = "fluentbit"
-	fluentbitPodSecurityPolicyName = "fluentbit"
 	fluentbitServiceName           = "fluentbit"
 	containerName                  = "fluent-bit"
 	defaultBufferVolumeMetricsPort = 9200
@@ -129,9 +128,6 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 		r.serviceMetrics,
 		r.serviceBufferMetrics,
 	}
-	if resources.PSPEnabled {
-		objects = append(objects, r.clusterPodSecurityPolicy, r.pspClusterRole, r.pspClusterRoleBinding)
-	}
 	if resources.IsSupported(ctx, resources.ServiceMonitorKey) {
 		objects = append(objects, r.monitorServiceMetrics, r.monitorBufferServiceMetrics)
 	}
@@ -178,6 +174,11 @@ func (l *FluentbitNameProvider) Name() string {
 	return l.fluentbit.Name
 }
 
+// DaemonSetName returns the name of the fluent-bit daemonset
+func (l *FluentbitNameProvider) DaemonSetName() string {
+	return l.ComponentName(fluentbitDaemonSetName)
+}
+
 func (l *FluentbitNameProvider) OwnerRef() v1.OwnerReference {
 	if l.logging != nil {
 		return v1.OwnerReference{
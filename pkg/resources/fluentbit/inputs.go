@@ -0,0 +1,266 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentbit
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+func defaultTailParser() string {
+	switch types.ContainerRuntime {
+	case "docker":
+		return "docker"
+	case "containerd":
+		return "cri"
+	default:
+		return "cri"
+	}
+}
+
+// newTailInput maps the tail input config, the parser lists are rendered separately from the other values
+func newTailInput(inputTail v1beta1.InputTail) (fluentbitInputConfig, error) {
+	var config fluentbitInputConfig
+
+	if len(inputTail.MultilineParser) > 0 {
+		config.MultilineParser = inputTail.MultilineParser
+		inputTail.MultilineParser = nil
+
+		// If MultilineParser is set, remove other parser fields
+		// See https://docs.fluentbit.io/manual/pipeline/inputs/tail#multiline-core-v1.8
+		inputTail.Parser = ""
+		inputTail.ParserFirstline = ""
+		inputTail.ParserN = nil
+		inputTail.Multiline = ""
+		inputTail.MultilineFlush = ""
+		inputTail.DockerMode = ""
+		inputTail.DockerModeFlush = ""
+		inputTail.DockerModeParser = ""
+	} else if len(inputTail.ParserN) > 0 {
+		config.ParserN = inputTail.ParserN
+		inputTail.ParserN = nil
+	}
+
+	values, err := types.NewStructToStringMapper(nil).StringsMap(inputTail)
+	if err != nil {
+		return config, err
+	}
+	config.Values = values
+	return config, nil
+}
+
+// newTailInputs configures the tail inputs of the list, each with its own kubernetes filter matching its tag prefix
+func newTailInputs(inputs []v1beta1.FluentbitTailInput, filterKubernetes v1beta1.FilterKubernetes) ([]fluentbitInputConfig, error) {
+	mapper := types.NewStructToStringMapper(nil)
+	prefixes := make(map[string]bool)
+	var result []fluentbitInputConfig
+	for i, input := range inputs {
+		if input.TagPrefix == "" {
+			return nil, errors.Errorf("tail input #%d must have a tag prefix", i)
+		}
+		if prefixes[input.TagPrefix] {
+			return nil, errors.Errorf("tail input #%d has duplicate tag prefix %q", i, input.TagPrefix)
+		}
+		prefixes[input.TagPrefix] = true
+		if !strings.HasPrefix(input.Tag, input.TagPrefix+".") {
+			return nil, errors.Errorf("tag %q of tail input #%d must start with its tag prefix %q", input.Tag, i, input.TagPrefix+".")
+		}
+
+		config, err := newTailInput(input.InputTail)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to map tail input #%d", i)
+		}
+
+		filter := filterKubernetes
+		filter.Match = input.TagPrefix + ".*"
+		filter.KubeTagPrefix, err = kubeTagPrefix(input.Tag, input.Path)
+		if err != nil {
+			return nil, errors.WrapIff(err, "tail input #%d", i)
+		}
+		config.KubernetesFilter, err = mapper.StringsMap(filter)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to map kubernetes filter of tail input #%d", i)
+		}
+		result = append(result, config)
+	}
+	return result, nil
+}
+
+// kubeTagPrefix returns the part of the record tags the kubernetes filter has to strip to get the name of the log file.
+// Fluent-bit expands the wildcard of the tag to the path of the file, with the slashes replaced by dots,
+// so the prefix is the tag up to the wildcard followed by the directory of the files.
+func kubeTagPrefix(tag, paths string) (string, error) {
+	wildcard := strings.Index(tag, "*")
+	if wildcard < 0 {
+		return "", errors.Errorf("tag %q must contain a wildcard to be expanded to the log file", tag)
+	}
+	if paths == "" {
+		return "", errors.New("path is required")
+	}
+	var dir string
+	for _, p := range strings.Split(paths, ",") {
+		d := path.Dir(strings.TrimSpace(p))
+		if strings.ContainsAny(d, "*?[") {
+			return "", errors.Errorf("directory of path %q must not contain wildcards", p)
+		}
+		if dir != "" && d != dir {
+			return "", errors.Errorf("paths %q must be in the same directory", paths)
+		}
+		dir = d
+	}
+	return tag[:wildcard] + strings.ReplaceAll(strings.TrimPrefix(dir, "/"), "/", "."), nil
+}
+
+// aggregatorTagPrefixes returns the tag prefixes of the records forwarded to the aggregators if there are multiple tail inputs,
+// or nil if all the records are forwarded
+func aggregatorTagPrefixes(spec v1beta1.FluentbitSpec) ([]string, error) {
+	if len(spec.InputTails) == 0 {
+		return nil, nil
+	}
+	var tagPrefixes []string
+	for _, inputTail := range spec.InputTails {
+		tagPrefixes = append(tagPrefixes, inputTail.TagPrefix)
+	}
+	// the systemd and audit inputs have to be matched by the aggregator output as well
+	var otherInputs []struct{ name, prefix string }
+	if spec.InputSystemd != nil {
+		otherInputs = append(otherInputs, struct{ name, prefix string }{"systemd", systemdTagPrefix(*spec.InputSystemd)})
+	}
+	if spec.AuditLogs != nil {
+		otherInputs = append(otherInputs, struct{ name, prefix string }{"audit logs", v1beta1.AuditLogsTagPrefix})
+	}
+	for _, other := range otherInputs {
+		for _, prefix := range tagPrefixes {
+			if prefix == other.prefix {
+				return nil, errors.Errorf("tag prefix %q of the %s input is used by another input", other.prefix, other.name)
+			}
+		}
+		tagPrefixes = append(tagPrefixes, other.prefix)
+	}
+	// records re-tagged by the rewriteTag filters have to reach the aggregator as well,
+	// all the records are forwarded if the new tags can't be known in advance
+	rewritePrefixes, ok := rewriteTagPrefixes(spec.Filters)
+	if !ok {
+		return nil, nil
+	}
+	known := make(map[string]bool)
+	for _, prefix := range tagPrefixes {
+		known[prefix] = true
+	}
+	for _, prefix := range rewritePrefixes {
+		if !known[prefix] {
+			known[prefix] = true
+			tagPrefixes = append(tagPrefixes, prefix)
+		}
+	}
+	return tagPrefixes, nil
+}
+
+// aggregatorMatchRegex matches the records of the inputs with the given tag prefixes
+func aggregatorMatchRegex(tagPrefixes []string) string {
+	return fmt.Sprintf(`^%s\.`, tagPrefixRegex(tagPrefixes))
+}
+
+// tagPrefixRegex matches any of the given tag prefixes, or any first tag segment if there are none
+func tagPrefixRegex(tagPrefixes []string) string {
+	if len(tagPrefixes) == 0 {
+		return `[^.]+`
+	}
+	return fmt.Sprintf("(%s)", quotedAlternatives(tagPrefixes))
+}
+
+func quotedAlternatives(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, regexp.QuoteMeta(value))
+	}
+	return strings.Join(quoted, "|")
+}
+
+type systemdInputConfig struct {
+	Tag    string
+	Match  string
+	Units  []string
+	Values map[string]string
+	// keys of the unit name and the hostname, depending on whether the underscores are stripped
+	UnitKey     string
+	HostnameKey string
+}
+
+const defaultSystemdTagPrefix = "systemd"
+
+func systemdTagPrefix(input v1beta1.InputSystemd) string {
+	if input.TagPrefix == "" {
+		return defaultSystemdTagPrefix
+	}
+	return input.TagPrefix
+}
+
+// newSystemdInput configures the systemd input, the unit and the hostname of its records are copied
+// to kubernetes.container_name and kubernetes.host, so that they can be routed like container logs
+func newSystemdInput(input v1beta1.InputSystemd) (*systemdInputConfig, error) {
+	values, err := types.NewStructToStringMapper(nil).StringsMap(input)
+	if err != nil {
+		return nil, err
+	}
+	prefix := systemdTagPrefix(input)
+	config := &systemdInputConfig{
+		Tag:         prefix + ".*",
+		Match:       prefix + ".*",
+		Units:       input.Units,
+		Values:      values,
+		UnitKey:     "_SYSTEMD_UNIT",
+		HostnameKey: "_HOSTNAME",
+	}
+	if strings.EqualFold(input.StripUnderscores, "on") || strings.EqualFold(input.StripUnderscores, "true") {
+		config.UnitKey = "SYSTEMD_UNIT"
+		config.HostnameKey = "HOSTNAME"
+	}
+	return config, nil
+}
+
+const nodeNameEnvVar = "FLUENTBIT_NODE_NAME"
+
+type auditLogsInputConfig struct {
+	Tag           string
+	Match         string
+	Values        map[string]string
+	ContainerName string
+	// NodeName references the environment variable of the node name
+	NodeName string
+}
+
+// newAuditLogsInput configures the tail input of the API server audit log, its records are routed as the
+// kube-apiserver-audit container of the node
+func newAuditLogsInput(auditLogs v1beta1.FluentbitAuditLogs) (*auditLogsInputConfig, error) {
+	values, err := types.NewStructToStringMapper(nil).StringsMap(auditLogs)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLogsInputConfig{
+		Tag:           v1beta1.AuditLogsTagPrefix + ".*",
+		Match:         v1beta1.AuditLogsTagPrefix + ".*",
+		Values:        values,
+		ContainerName: v1beta1.AuditLogsContainerName,
+		NodeName:      fmt.Sprintf("${%s}", nodeNameEnvVar),
+	}, nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentbit
+
+import (
+	"fmt"
+	"sort"
+)
+
+// containerTagRegex matches the tag of the container logs of the given namespaces after the tag prefix,
+// the tail input expands the tag with the path of the log file: var.log.containers.<pod>_<namespace>_<container>-<id>.log
+func containerTagRegex(namespaces []string) string {
+	return fmt.Sprintf(`var\.log\.containers\.[^_]+_(%s)_`, quotedAlternatives(namespaces))
+}
+
+// shardMatchRegex matches the container logs of the given namespaces
+func shardMatchRegex(tagPrefixes []string, namespaces []string) string {
+	return fmt.Sprintf(`^%s\.%s`, tagPrefixRegex(tagPrefixes), containerTagRegex(namespaces))
+}
+
+// defaultShardMatchRegex matches every record of the inputs except the container logs of the given namespaces
+func defaultShardMatchRegex(tagPrefixes []string, namespaces []string) string {
+	if len(tagPrefixes) == 0 {
+		return fmt.Sprintf(`^(?!%s\.%s)`, tagPrefixRegex(nil), containerTagRegex(namespaces))
+	}
+	return fmt.Sprintf(`^%s\.(?!%s)`, tagPrefixRegex(tagPrefixes), containerTagRegex(namespaces))
+}
+
+// newShardForwardOutputs routes the container logs of the namespaces assigned to the other fluentd shards to their services,
+// the forward output of the first shard keeps the rest of the records
+func newShardForwardOutputs(output *fluentForwardOutputConfig, shardNamespaces map[int32][]string, tagPrefixes []string, shardHost func(shard int32) string) []fluentForwardOutputConfig {
+	var shards []int32
+	var routed []string
+	for shard, namespaces := range shardNamespaces {
+		if shard != 0 && len(namespaces) > 0 {
+			shards = append(shards, shard)
+			routed = append(routed, namespaces...)
+		}
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+	sort.Strings(routed)
+
+	output.MatchRegex = defaultShardMatchRegex(tagPrefixes, routed)
+	result := make([]fluentForwardOutputConfig, 0, len(shards))
+	for _, shard := range shards {
+		shardOutput := *output
+		shardOutput.TargetHost = shardHost(shard)
+		shardOutput.MatchRegex = shardMatchRegex(tagPrefixes, shardNamespaces[shard])
+		result = append(result, shardOutput)
+	}
+	return result
+}
@@ -17,12 +17,13 @@ package fluentd
 import (
 	"context"
 	"fmt"
-	"hash/fnv"
 
 	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	"github.com/kube-logging/logging-operator/pkg/compression"
 	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +35,8 @@ type ConfigCheckResult struct {
 	Valid   bool
 	Ready   bool
 	Message string
+	// Output of the failed config check pod
+	Output string
 }
 
 func (r *Reconciler) appConfigSecret() (runtime.Object, reconciler.DesiredState, error) {
@@ -46,19 +49,37 @@ func (r *Reconciler) appConfigSecret() (runtime.Object, reconciler.DesiredState,
 		data[AppConfigKey] = []byte(*r.config)
 	}
 
-	return &corev1.Secret{
+	hash, err := r.configHash()
+	if err != nil {
+		return nil, reconciler.StatePresent, err
+	}
+	appSecret := &corev1.Secret{
 		ObjectMeta: r.FluentdObjectMeta(AppSecretConfigName, ComponentFluentd),
 		Data:       data,
-	}, reconciler.StatePresent, nil
+	}
+	// the hash of the live config is compared to the new one to decide about a canary rollout
+	appSecret.Annotations = map[string]string{configcheck.ConfigHashAnnotation: hash}
+	return appSecret, reconciler.StatePresent, nil
 }
 
-func (r *Reconciler) configHash() (string, error) {
-	hasher := fnv.New32()
-	_, err := hasher.Write([]byte(*r.config))
-	if err != nil {
-		return "", errors.WrapIf(err, "failed to calculate hash for the configmap data")
+func (r *Reconciler) routingTableConfigMap() (runtime.Object, reconciler.DesiredState, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: r.FluentdObjectMeta(RoutingConfigMapName, ComponentFluentd),
+	}
+	if r.routingTable == nil {
+		return configMap, reconciler.StateAbsent, nil
 	}
-	return fmt.Sprintf("%x", hasher.Sum32()), nil
+	configMap.Data = map[string]string{
+		RoutingTableKey: string(r.routingTable),
+	}
+	if r.servedConfigHash != "" {
+		configMap.Annotations = map[string]string{configcheck.ConfigHashAnnotation: r.servedConfigHash}
+	}
+	return configMap, reconciler.StatePresent, nil
+}
+
+func (r *Reconciler) configHash() (string, error) {
+	return configcheck.ConfigHash(*r.config, r.secrets)
 }
 
 func (r *Reconciler) hasConfigCheckPod(ctx context.Context, hashKey string) (bool, error) {
@@ -111,6 +132,9 @@ func (r *Reconciler) configCheck(ctx context.Context) (*ConfigCheckResult, error
 
 	pod := r.newCheckPod(hashKey)
 	configcheck.WithHashLabel(pod, hashKey)
+	if err := podsecurity.Validate(r.Logging.Spec.FluentdSpec.Security.PodSecurityStandard, pod.Spec); err != nil {
+		return nil, errors.WrapIf(err, "fluentd config check pod")
+	}
 
 	existingPods := &corev1.PodList{}
 	err = r.Client.List(ctx, existingPods, client.MatchingLabels(pod.Labels))
@@ -151,8 +175,9 @@ func (r *Reconciler) configCheck(ctx context.Context) (*ConfigCheckResult, error
 			return &ConfigCheckResult{}, nil
 		case corev1.PodFailed:
 			return &ConfigCheckResult{
-				Ready: true,
-				Valid: false,
+				Ready:  true,
+				Valid:  false,
+				Output: configcheck.FailureOutput(pod),
 			}, nil
 		case corev1.PodUnknown:
 			fallthrough
@@ -234,10 +259,11 @@ func (r *Reconciler) newCheckPod(hashKey string) *corev1.Pod {
 			Affinity:           r.Logging.Spec.FluentdSpec.Affinity,
 			PriorityClassName:  r.Logging.Spec.FluentdSpec.PodPriorityClassName,
 			SecurityContext: &corev1.PodSecurityContext{
-				RunAsNonRoot: r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsNonRoot,
-				FSGroup:      r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.FSGroup,
-				RunAsUser:    r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsUser,
-				RunAsGroup:   r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsGroup,
+				RunAsNonRoot:   r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsNonRoot,
+				FSGroup:        r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.FSGroup,
+				RunAsUser:      r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsUser,
+				RunAsGroup:     r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsGroup,
+				SeccompProfile: r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.SeccompProfile,
 			},
 			Volumes:          volumes,
 			ImagePullSecrets: r.Logging.Spec.FluentdSpec.Image.ImagePullSecrets,
@@ -269,6 +295,7 @@ func (r *Reconciler) newCheckPod(hashKey string) *corev1.Pod {
 			r.Log.Error(err, "Fluentd Config check pod extraVolume attachment failed.")
 		}
 	}
+	secretbackend.ApplyVolumes(r.Logging.Spec.SecretBackends, containerName, SecretBackendsPath, &pod.Spec)
 
 	return pod
 }
@@ -279,7 +306,7 @@ func (r *Reconciler) volumesCheckPod(hashKey string) (v []corev1.Volume) {
 			Name: "config",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(fmt.Sprintf("fluentd-configcheck-%s", hashKey)),
+					SecretName: r.qualifiedName(fmt.Sprintf("fluentd-configcheck-%s", hashKey)),
 				},
 			},
 		},
@@ -287,7 +314,7 @@ func (r *Reconciler) volumesCheckPod(hashKey string) (v []corev1.Volume) {
 			Name: "output-secret",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(fmt.Sprintf("fluentd-configcheck-output-%s", hashKey)),
+					SecretName: r.qualifiedName(fmt.Sprintf("fluentd-configcheck-output-%s", hashKey)),
 				},
 			},
 		},
@@ -304,7 +331,7 @@ func (r *Reconciler) volumesCheckPod(hashKey string) (v []corev1.Volume) {
 			Name: "app-config-compress",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(fmt.Sprintf("fluentd-configcheck-app-%s", hashKey)),
+					SecretName: r.qualifiedName(fmt.Sprintf("fluentd-configcheck-app-%s", hashKey)),
 				},
 			},
 		})
@@ -313,7 +340,7 @@ func (r *Reconciler) volumesCheckPod(hashKey string) (v []corev1.Volume) {
 			Name: "app-config",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(fmt.Sprintf("fluentd-configcheck-app-%s", hashKey)),
+					SecretName: r.qualifiedName(fmt.Sprintf("fluentd-configcheck-app-%s", hashKey)),
 				},
 			},
 		})
@@ -359,8 +386,12 @@ func (r *Reconciler) containerCheckPod(hashKey string) []corev1.Container {
 				Privileged:               r.Logging.Spec.FluentdSpec.Security.SecurityContext.Privileged,
 				RunAsNonRoot:             r.Logging.Spec.FluentdSpec.Security.SecurityContext.RunAsNonRoot,
 				SELinuxOptions:           r.Logging.Spec.FluentdSpec.Security.SecurityContext.SELinuxOptions,
+				Capabilities:             r.Logging.Spec.FluentdSpec.Security.SecurityContext.Capabilities,
+				SeccompProfile:           r.Logging.Spec.FluentdSpec.Security.SecurityContext.SeccompProfile,
 			},
 			Resources: r.Logging.Spec.FluentdSpec.ConfigCheckResources,
+			// the tail of the logs ends up in the pod status, where the operator picks it up as the failure output
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		},
 	}
 
@@ -372,10 +403,11 @@ func (r *Reconciler) initContainerCheckPod() []corev1.Container {
 	if r.Logging.Spec.FluentdSpec.CompressConfigFile {
 		initContainer = []corev1.Container{
 			{
-				Name:            "config-reloader",
-				Image:           r.Logging.Spec.FluentdSpec.ConfigReloaderImage.RepositoryWithTag(),
-				ImagePullPolicy: corev1.PullPolicy(r.Logging.Spec.FluentdSpec.Image.PullPolicy),
-				Resources:       r.Logging.Spec.FluentdSpec.ConfigReloaderResources,
+				Name:                     "config-reloader",
+				Image:                    r.Logging.Spec.FluentdSpec.ConfigReloaderImage.RepositoryWithTag(),
+				ImagePullPolicy:          corev1.PullPolicy(r.Logging.Spec.FluentdSpec.Image.PullPolicy),
+				Resources:                r.Logging.Spec.FluentdSpec.ConfigReloaderResources,
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 				Args: []string{
 					"--init-mode=true",
 					"--volume-dir-archive=/tmp/archive",
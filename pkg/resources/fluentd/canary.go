@@ -0,0 +1,360 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	util "github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/prometheus/common/expfmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kube-logging/logging-operator/pkg/resources"
+	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+const (
+	CanaryStatefulSetName     = "fluentd-canary"
+	CanaryAppSecretConfigName = "fluentd-app-canary"
+	CanaryOutputSecretName    = "fluentd-output-canary"
+
+	canaryLabel                   = "logging.banzaicloud.io/fluentd-canary"
+	defaultCanaryAnalysisDuration = 5 * time.Minute
+	defaultCanaryReadyTimeout     = 5 * time.Minute
+	canaryCheckInterval           = 30 * time.Second
+	outputErrorsMetric            = "fluentd_output_status_num_errors"
+)
+
+// canaryRollout is the state of the canary rollout of the flow config
+type canaryRollout struct {
+	// hold keeps the live config on the fluentd statefulset
+	hold bool
+	// running is set while the canary statefulset is deployed, so its buffer is not drained
+	running bool
+	// result requeues the reconcile while the canary is being analyzed
+	result *reconcile.Result
+	// failure is returned after the other resources have been reconciled
+	failure error
+}
+
+// canaryObservation is the state of the canary pod at an analysis step
+type canaryObservation struct {
+	ready bool
+	// outputErrors is only set if the metrics of the canary could be read
+	outputErrors *int64
+}
+
+// startCanary returns the status of the rollout of the config, a new one is started if the config has changed
+func startCanary(status *v1beta1.ConfigCanaryStatus, hash string, now time.Time) (*v1beta1.ConfigCanaryStatus, bool) {
+	if status != nil && status.ConfigHash == hash {
+		return status.DeepCopy(), false
+	}
+	return &v1beta1.ConfigCanaryStatus{
+		ConfigHash: hash,
+		Phase:      v1beta1.ConfigCanaryProgressing,
+		StartTime:  &metav1.Time{Time: now},
+	}, true
+}
+
+// analyzeCanary advances a progressing rollout based on the observed canary pod.
+// Returns the new status, whether it has changed, and the time to check the canary again while the rollout is progressing.
+func analyzeCanary(canary v1beta1.FluentdCanary, status v1beta1.ConfigCanaryStatus, observed canaryObservation, now time.Time) (*v1beta1.ConfigCanaryStatus, bool, time.Duration) {
+	abort := func(message string) (*v1beta1.ConfigCanaryStatus, bool, time.Duration) {
+		status.Phase = v1beta1.ConfigCanaryAborted
+		status.Message = message
+		return &status, true, 0
+	}
+
+	if !observed.ready {
+		if status.ReadyTime != nil {
+			return abort("canary became unready during the analysis")
+		}
+		readyTimeout := durationOrDefault(canary.ReadyTimeout, defaultCanaryReadyTimeout)
+		if now.Sub(status.StartTime.Time) > readyTimeout {
+			return abort(fmt.Sprintf("canary did not become ready in %s", readyTimeout))
+		}
+		return &status, false, canaryCheckInterval
+	}
+
+	changed := false
+	if status.ReadyTime == nil {
+		status.ReadyTime = &metav1.Time{Time: now}
+		changed = true
+	}
+	if observed.outputErrors != nil {
+		changed = changed || status.OutputErrors != *observed.outputErrors
+		status.OutputErrors = *observed.outputErrors
+		if status.OutputErrors > int64(canary.MaxOutputErrors) {
+			return abort(fmt.Sprintf("canary had %d output errors, %d tolerated", status.OutputErrors, canary.MaxOutputErrors))
+		}
+	}
+
+	analysisDuration := durationOrDefault(canary.AnalysisDuration, defaultCanaryAnalysisDuration)
+	if remaining := analysisDuration - now.Sub(status.ReadyTime.Time); remaining > 0 {
+		if remaining > canaryCheckInterval {
+			remaining = canaryCheckInterval
+		}
+		return &status, changed, remaining
+	}
+
+	status.Phase = v1beta1.ConfigCanaryPromoted
+	status.Message = fmt.Sprintf("canary has been ready for %s", analysisDuration)
+	return &status, true, 0
+}
+
+// reconcileCanary rolls out a changed flow config through the canary statefulset, see v1beta1.FluentdCanary
+func (r *Reconciler) reconcileCanary(ctx context.Context) (canaryRollout, error) {
+	canary := r.Logging.Spec.FluentdSpec.Canary
+	if canary == nil {
+		return canaryRollout{}, r.removeCanary()
+	}
+	if r.Logging.Spec.FluentdSpec.Shards != nil {
+		return canaryRollout{}, errors.New("canary rollout can't be used with fluentd shards")
+	}
+
+	hash, err := r.configHash()
+	if err != nil {
+		return canaryRollout{}, err
+	}
+	liveHash, err := r.liveConfigHash(ctx)
+	if err != nil {
+		return canaryRollout{}, err
+	}
+	if liveHash == "" || liveHash == hash {
+		// nothing to roll out, the first config is applied to every replica directly
+		return canaryRollout{}, r.removeCanary()
+	}
+
+	now := time.Now()
+	status, started := startCanary(r.Logging.Status.ConfigCanary, hash, now)
+	if started {
+		r.Log.Info("starting the canary rollout of the fluentd config", "hash", hash, "live", liveHash)
+		if err := r.patchCanaryStatus(ctx, status); err != nil {
+			return canaryRollout{}, err
+		}
+	}
+
+	aborted := func(status *v1beta1.ConfigCanaryStatus) (canaryRollout, error) {
+		return canaryRollout{
+			hold:    true,
+			failure: errors.Errorf("canary rollout of fluentd config %s has been aborted: %s", hash, status.Message),
+		}, r.removeCanary()
+	}
+	switch status.Phase {
+	case v1beta1.ConfigCanaryPromoted:
+		return canaryRollout{}, r.removeCanary()
+	case v1beta1.ConfigCanaryAborted:
+		return aborted(status)
+	}
+
+	for _, res := range []resources.Resource{r.canaryAppConfigSecret, r.canaryOutputSecret, r.canaryStatefulset} {
+		o, state, err := res()
+		if err != nil {
+			return canaryRollout{}, errors.WrapIf(err, "failed to create desired canary object")
+		}
+		if _, err := r.ReconcileResource(o, state); err != nil {
+			return canaryRollout{}, errors.WrapIf(err, "failed to reconcile canary resource")
+		}
+	}
+
+	pod, err := r.readyCanaryPod(ctx)
+	if err != nil {
+		return canaryRollout{}, err
+	}
+	observed := canaryObservation{ready: pod != nil}
+	if pod != nil {
+		if outputErrors, ok, err := r.canaryOutputErrors(ctx, pod); err != nil {
+			r.Log.Error(err, "failed to read the metrics of the fluentd canary")
+		} else if ok {
+			observed.outputErrors = &outputErrors
+		}
+	}
+
+	status, changed, requeueAfter := analyzeCanary(*canary, *status, observed, now)
+	if changed {
+		if err := r.patchCanaryStatus(ctx, status); err != nil {
+			return canaryRollout{}, err
+		}
+	}
+	switch status.Phase {
+	case v1beta1.ConfigCanaryAborted:
+		r.Log.Info("aborting the canary rollout of the fluentd config", "hash", hash, "reason", status.Message)
+		return aborted(status)
+	case v1beta1.ConfigCanaryPromoted:
+		r.Log.Info("promoting the canary fluentd config", "hash", hash)
+		return canaryRollout{}, r.removeCanary()
+	}
+	return canaryRollout{hold: true, running: true, result: &reconcile.Result{RequeueAfter: requeueAfter}}, nil
+}
+
+func durationOrDefault(d *metav1.Duration, def time.Duration) time.Duration {
+	if d == nil || d.Duration <= 0 {
+		return def
+	}
+	return d.Duration
+}
+
+func (r *Reconciler) patchCanaryStatus(ctx context.Context, status *v1beta1.ConfigCanaryStatus) error {
+	patchBase := client.MergeFrom(r.Logging.DeepCopy())
+	r.Logging.Status.ConfigCanary = status.DeepCopy()
+	if err := r.Client.Status().Patch(ctx, r.Logging, patchBase); err != nil {
+		return errors.WrapWithDetails(err, "failed to patch canary status", "logging", r.Logging.Name)
+	}
+	return nil
+}
+
+// liveConfigHash returns the hash of the config applied to the fluentd statefulset, empty if it's unknown
+func (r *Reconciler) liveConfigHash(ctx context.Context) (string, error) {
+	var appSecret corev1.Secret
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.Logging.Spec.ControlNamespace, Name: r.qualifiedName(AppSecretConfigName)}, &appSecret)
+	if err != nil {
+		return "", errors.WrapIf(client.IgnoreNotFound(err), "getting fluentd app config secret")
+	}
+	return appSecret.Annotations[configcheck.ConfigHashAnnotation], nil
+}
+
+func (r *Reconciler) removeCanary() error {
+	objects := []runtime.Object{
+		&appsv1.StatefulSet{ObjectMeta: r.FluentdObjectMeta(CanaryStatefulSetName, ComponentFluentd)},
+		&corev1.Secret{ObjectMeta: r.FluentdObjectMeta(CanaryAppSecretConfigName, ComponentFluentd)},
+		&corev1.Secret{ObjectMeta: r.FluentdObjectMeta(CanaryOutputSecretName, ComponentFluentd)},
+	}
+	for _, o := range objects {
+		if _, err := r.ReconcileResource(o, reconciler.StateAbsent); err != nil {
+			return errors.WrapIf(err, "failed to remove canary resource")
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) canaryAppConfigSecret() (runtime.Object, reconciler.DesiredState, error) {
+	o, state, err := r.appConfigSecret()
+	if err != nil {
+		return nil, state, err
+	}
+	appSecret := o.(*corev1.Secret)
+	appSecret.ObjectMeta = r.FluentdObjectMeta(CanaryAppSecretConfigName, ComponentFluentd)
+	return appSecret, state, nil
+}
+
+func (r *Reconciler) canaryOutputSecret() (runtime.Object, reconciler.DesiredState, error) {
+	o, state, err := r.outputSecret(r.secrets, OutputSecretPath)
+	if err != nil {
+		return nil, state, err
+	}
+	outputSecret := o.(*corev1.Secret)
+	outputSecret.Name = r.qualifiedName(CanaryOutputSecretName)
+	return outputSecret, state, nil
+}
+
+// canaryStatefulset runs a single replica of the fluentd statefulset with the new config.
+// The buffer volume claims are kept, so the buffer of the canary can be drained once it's removed.
+func (r *Reconciler) canaryStatefulset() (runtime.Object, reconciler.DesiredState, error) {
+	hash, err := r.configHash()
+	if err != nil {
+		return nil, reconciler.StatePresent, err
+	}
+	o, state, err := r.statefulset()
+	if err != nil {
+		return nil, state, err
+	}
+	sts := o.(*appsv1.StatefulSet)
+	sts.Name = r.qualifiedName(CanaryStatefulSetName)
+	sts.Labels[canaryLabel] = "true"
+	sts.Annotations = util.MergeLabels(sts.Annotations, map[string]string{configcheck.ConfigHashAnnotation: hash})
+	sts.Spec.Replicas = util.IntPointer(1)
+	sts.Spec.Selector.MatchLabels[canaryLabel] = "true"
+	sts.Spec.Selector.MatchExpressions = nil
+	sts.Spec.Template.Labels[canaryLabel] = "true"
+
+	for i := range sts.Spec.Template.Spec.Volumes {
+		secret := sts.Spec.Template.Spec.Volumes[i].Secret
+		if secret == nil {
+			continue
+		}
+		switch secret.SecretName {
+		case r.qualifiedName(AppSecretConfigName):
+			secret.SecretName = r.qualifiedName(CanaryAppSecretConfigName)
+		case r.qualifiedName(OutputSecretName):
+			secret.SecretName = r.qualifiedName(CanaryOutputSecretName)
+		}
+	}
+	return sts, state, nil
+}
+
+// readyCanaryPod returns the canary pod if it's ready
+func (r *Reconciler) readyCanaryPod(ctx context.Context) (*corev1.Pod, error) {
+	var pod corev1.Pod
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.Logging.Spec.ControlNamespace, Name: r.qualifiedName(CanaryStatefulSetName) + "-0"}, &pod)
+	if err != nil {
+		return nil, errors.WrapIf(client.IgnoreNotFound(err), "getting fluentd canary pod")
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return &pod, nil
+		}
+	}
+	return nil, nil
+}
+
+// canaryOutputErrors sums up the output errors reported by the metrics endpoint of the canary pod,
+// returns false if the metrics aren't enabled
+func (r *Reconciler) canaryOutputErrors(ctx context.Context, pod *corev1.Pod) (int64, bool, error) {
+	metrics := r.Logging.Spec.FluentdSpec.Metrics
+	if metrics == nil || pod.Status.PodIP == "" {
+		return 0, false, nil
+	}
+	url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, metrics.Port, r.Logging.GetFluentdMetricsPath())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, false, errors.WrapIf(err, "failed to create metrics request")
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return 0, false, errors.WrapIf(err, "metrics request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, errors.Errorf("metrics request failed with status %s", resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, false, errors.WrapIf(err, "failed to parse metrics")
+	}
+	var outputErrors float64
+	if family, ok := families[outputErrorsMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			outputErrors += metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+		}
+	}
+	return int64(outputErrors), true, nil
+}
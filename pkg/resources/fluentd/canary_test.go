@@ -0,0 +1,191 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestStartCanary(t *testing.T) {
+	now := time.Now()
+
+	status, started := startCanary(nil, "new", now)
+	require.True(t, started)
+	require.Equal(t, &v1beta1.ConfigCanaryStatus{
+		ConfigHash: "new",
+		Phase:      v1beta1.ConfigCanaryProgressing,
+		StartTime:  &metav1.Time{Time: now},
+	}, status)
+
+	// the rollout of the same config goes on, whatever its phase is
+	aborted := &v1beta1.ConfigCanaryStatus{ConfigHash: "new", Phase: v1beta1.ConfigCanaryAborted, Message: "failed"}
+	status, started = startCanary(aborted, "new", now)
+	require.False(t, started)
+	require.Equal(t, aborted, status)
+
+	// a changed config restarts the rollout
+	status, started = startCanary(aborted, "newer", now)
+	require.True(t, started)
+	require.Equal(t, "newer", status.ConfigHash)
+	require.Equal(t, v1beta1.ConfigCanaryProgressing, status.Phase)
+	require.Empty(t, status.Message)
+}
+
+func TestAnalyzeCanary(t *testing.T) {
+	start := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: start.Add(d)}
+	}
+	errorCount := func(n int64) *int64 {
+		return &n
+	}
+	canary := v1beta1.FluentdCanary{
+		AnalysisDuration: &metav1.Duration{Duration: 10 * time.Minute},
+		MaxOutputErrors:  3,
+	}
+
+	testCases := map[string]struct {
+		status       v1beta1.ConfigCanaryStatus
+		observed     canaryObservation
+		now          time.Duration
+		wantPhase    v1beta1.ConfigCanaryPhase
+		wantMessage  string
+		wantChanged  bool
+		wantRequeue  time.Duration
+		wantReadyAt  *metav1.Time
+		wantErrCount int64
+	}{
+		"hold while waiting for the canary": {
+			status:      v1beta1.ConfigCanaryStatus{StartTime: at(0)},
+			now:         time.Minute,
+			wantPhase:   v1beta1.ConfigCanaryProgressing,
+			wantRequeue: canaryCheckInterval,
+		},
+		"abort when not ready in time": {
+			status:      v1beta1.ConfigCanaryStatus{StartTime: at(0)},
+			now:         defaultCanaryReadyTimeout + time.Second,
+			wantPhase:   v1beta1.ConfigCanaryAborted,
+			wantMessage: "canary did not become ready in 5m0s",
+			wantChanged: true,
+		},
+		"start the analysis when ready": {
+			status:      v1beta1.ConfigCanaryStatus{StartTime: at(0)},
+			observed:    canaryObservation{ready: true},
+			now:         time.Minute,
+			wantPhase:   v1beta1.ConfigCanaryProgressing,
+			wantChanged: true,
+			wantRequeue: canaryCheckInterval,
+			wantReadyAt: at(time.Minute),
+		},
+		"hold during the analysis": {
+			status:       v1beta1.ConfigCanaryStatus{StartTime: at(0), ReadyTime: at(time.Minute), OutputErrors: 1},
+			observed:     canaryObservation{ready: true, outputErrors: errorCount(1)},
+			now:          10*time.Minute + 50*time.Second,
+			wantPhase:    v1beta1.ConfigCanaryProgressing,
+			wantRequeue:  10 * time.Second,
+			wantReadyAt:  at(time.Minute),
+			wantErrCount: 1,
+		},
+		"record new output errors": {
+			status:       v1beta1.ConfigCanaryStatus{StartTime: at(0), ReadyTime: at(time.Minute)},
+			observed:     canaryObservation{ready: true, outputErrors: errorCount(2)},
+			now:          2 * time.Minute,
+			wantPhase:    v1beta1.ConfigCanaryProgressing,
+			wantChanged:  true,
+			wantRequeue:  canaryCheckInterval,
+			wantReadyAt:  at(time.Minute),
+			wantErrCount: 2,
+		},
+		"abort on too many output errors": {
+			status:       v1beta1.ConfigCanaryStatus{StartTime: at(0), ReadyTime: at(time.Minute)},
+			observed:     canaryObservation{ready: true, outputErrors: errorCount(4)},
+			now:          2 * time.Minute,
+			wantPhase:    v1beta1.ConfigCanaryAborted,
+			wantMessage:  "canary had 4 output errors, 3 tolerated",
+			wantChanged:  true,
+			wantReadyAt:  at(time.Minute),
+			wantErrCount: 4,
+		},
+		"abort when unready during the analysis": {
+			status:      v1beta1.ConfigCanaryStatus{StartTime: at(0), ReadyTime: at(time.Minute)},
+			now:         2 * time.Minute,
+			wantPhase:   v1beta1.ConfigCanaryAborted,
+			wantMessage: "canary became unready during the analysis",
+			wantChanged: true,
+			wantReadyAt: at(time.Minute),
+		},
+		"promote after the analysis": {
+			status:      v1beta1.ConfigCanaryStatus{StartTime: at(0), ReadyTime: at(time.Minute)},
+			observed:    canaryObservation{ready: true},
+			now:         11 * time.Minute,
+			wantPhase:   v1beta1.ConfigCanaryPromoted,
+			wantMessage: "canary has been ready for 10m0s",
+			wantChanged: true,
+			wantReadyAt: at(time.Minute),
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			testCase.status.ConfigHash = "new"
+			testCase.status.Phase = v1beta1.ConfigCanaryProgressing
+			status, changed, requeue := analyzeCanary(canary, testCase.status, testCase.observed, start.Add(testCase.now))
+			require.Equal(t, "new", status.ConfigHash)
+			require.Equal(t, testCase.wantPhase, status.Phase)
+			require.Equal(t, testCase.wantMessage, status.Message)
+			require.Equal(t, testCase.wantChanged, changed)
+			require.Equal(t, testCase.wantRequeue, requeue)
+			require.Equal(t, testCase.wantReadyAt, status.ReadyTime)
+			require.Equal(t, testCase.wantErrCount, status.OutputErrors)
+		})
+	}
+}
+
+func TestBufferPodName(t *testing.T) {
+	r := &Reconciler{Logging: &v1beta1.Logging{ObjectMeta: metav1.ObjectMeta{Name: "logging"}}}
+	pvc := func(name string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	require.Equal(t, "fluentd-2", r.bufferPodName(pvc("logging-fluentd-buffer-logging-fluentd-2")))
+	require.Equal(t, "fluentd-canary-0", r.bufferPodName(pvc("logging-fluentd-buffer-logging-fluentd-canary-0")))
+}
+
+func TestStatefulsetSelectorExcludesCanary(t *testing.T) {
+	r := &Reconciler{Logging: &v1beta1.Logging{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging"},
+		Spec: v1beta1.LoggingSpec{
+			FluentdSpec: &v1beta1.FluentdSpec{Canary: &v1beta1.FluentdCanary{}},
+		},
+	}}
+	selector, err := metav1.LabelSelectorAsSelector(r.statefulsetSelector())
+	require.NoError(t, err)
+
+	podLabels := r.generatePodMeta().Labels
+	require.True(t, selector.Matches(labels.Set(podLabels)))
+	canaryPodLabels := labels.Merge(podLabels, labels.Set{canaryLabel: "true"})
+	require.False(t, selector.Matches(canaryPodLabels))
+
+	// the selector is unchanged without the canary
+	r.Logging.Spec.FluentdSpec.Canary = nil
+	require.Empty(t, r.statefulsetSelector().MatchExpressions)
+}
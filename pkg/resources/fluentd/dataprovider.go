@@ -25,22 +25,39 @@ import (
 )
 
 type DataProvider struct {
-	client  client.Client
-	logging *v1beta1.Logging
+	client          client.Client
+	logging         *v1beta1.Logging
+	shardNamespaces map[int32][]string
 }
 
 func NewDataProvider(client client.Client, logging *v1beta1.Logging) *DataProvider {
+	return NewShardedDataProvider(client, logging, nil)
+}
+
+// NewShardedDataProvider creates a DataProvider of a fluentd with the given namespaces assigned to its shards
+func NewShardedDataProvider(client client.Client, logging *v1beta1.Logging, shardNamespaces map[int32][]string) *DataProvider {
 	return &DataProvider{
-		client:  client,
-		logging: logging,
+		client:          client,
+		logging:         logging,
+		shardNamespaces: shardNamespaces,
 	}
 }
 
 func (p *DataProvider) GetReplicaCount(ctx context.Context) (*int32, error) {
+	return p.GetShardReplicaCount(ctx, 0)
+}
+
+// GetShardNamespaces returns the namespaces of the flows assigned to each shard
+func (p *DataProvider) GetShardNamespaces() map[int32][]string {
+	return p.shardNamespaces
+}
+
+// GetShardReplicaCount returns the replica count of the statefulset of the given shard
+func (p *DataProvider) GetShardReplicaCount(ctx context.Context, shard int32) (*int32, error) {
 	if p.logging.Spec.FluentdSpec != nil {
 		sts := &v1.StatefulSet{}
-		om := p.logging.FluentdObjectMeta(StatefulSetName, ComponentFluentd)
-		err := p.client.Get(ctx, types.NamespacedName{Namespace: om.Namespace, Name: om.Name}, sts)
+		name := types.NamespacedName{Namespace: p.logging.Spec.ControlNamespace, Name: p.logging.FluentdShardQualifiedName(shard, StatefulSetName)}
+		err := p.client.Get(ctx, name, sts)
 		if err != nil {
 			return nil, errors.WrapIf(client.IgnoreNotFound(err), "getting fluentd statefulset")
 		}
@@ -27,6 +27,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -43,16 +44,19 @@ import (
 )
 
 const (
-	SecretConfigName      = "fluentd"
-	AppSecretConfigName   = "fluentd-app"
-	ConfigCheckKey        = "generated.conf"
-	ConfigKey             = "fluent.conf"
-	AppConfigKey          = "fluentd.conf"
-	StatefulSetName       = "fluentd"
-	PodSecurityPolicyName = "fluentd"
-	ServiceName           = "fluentd"
-	OutputSecretName      = "fluentd-output"
-	OutputSecretPath      = "/fluentd/secret"
+	SecretConfigName     = "fluentd"
+	AppSecretConfigName  = "fluentd-app"
+	ConfigCheckKey       = "generated.conf"
+	ConfigKey            = "fluent.conf"
+	AppConfigKey         = "fluentd.conf"
+	RoutingConfigMapName = "fluentd-routing"
+	RoutingTableKey      = "routing.json"
+	StatefulSetName      = "fluentd"
+	NetworkPolicyName    = "fluentd"
+	ServiceName          = "fluentd"
+	OutputSecretName     = "fluentd-output"
+	OutputSecretPath     = "/fluentd/secret"
+	SecretBackendsPath   = "/fluentd/secret-backends"
 
 	bufferPath                     = "/buffers"
 	defaultServiceAccountName      = "fluentd"
@@ -69,8 +73,20 @@ const (
 type Reconciler struct {
 	Logging *v1beta1.Logging
 	*reconciler.GenericResourceReconciler
-	config  *string
-	secrets *secret.MountSecrets
+	shard        Shard
+	config       *string
+	routingTable []byte
+	secrets      *secret.MountSecrets
+	// servedConfigHash is the hash of the config served by the statefulset, the live one while the canary holds the new one
+	servedConfigHash string
+}
+
+// Shard identifies the fluentd shard reconciled by a Reconciler
+type Shard struct {
+	// Index of the shard, the first shard keeps the resource names of the unsharded fluentd
+	Index int32
+	// ConfigHashes of the configs of every shard, their config check results are kept in the status
+	ConfigHashes []string
 }
 
 type Desire struct {
@@ -85,15 +101,23 @@ func (r *Reconciler) getServiceAccount() string {
 	if r.Logging.Spec.FluentdSpec.Security.ServiceAccount != "" {
 		return r.Logging.Spec.FluentdSpec.Security.ServiceAccount
 	}
-	return r.Logging.QualifiedName(defaultServiceAccountName)
+	return r.qualifiedName(defaultServiceAccountName)
 }
 
 func New(client client.Client, log logr.Logger,
-	logging *v1beta1.Logging, config *string, secrets *secret.MountSecrets, opts reconciler.ReconcilerOpts) *Reconciler {
+	logging *v1beta1.Logging, config *string, routingTable []byte, secrets *secret.MountSecrets, opts reconciler.ReconcilerOpts) *Reconciler {
+	return NewShard(client, log, logging, Shard{}, config, routingTable, secrets, opts)
+}
+
+// NewShard creates a Reconciler of the given shard of a sharded fluentd
+func NewShard(client client.Client, log logr.Logger,
+	logging *v1beta1.Logging, shard Shard, config *string, routingTable []byte, secrets *secret.MountSecrets, opts reconciler.ReconcilerOpts) *Reconciler {
 	return &Reconciler{
 		Logging:                   logging,
 		GenericResourceReconciler: reconciler.NewGenericReconciler(client, log, opts),
+		shard:                     shard,
 		config:                    config,
+		routingTable:              routingTable,
 		secrets:                   secrets,
 	}
 }
@@ -110,10 +134,6 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 		r.clusterRoleBinding,
 	}
 
-	if resources.PSPEnabled {
-		objects = append(objects, r.clusterPodSecurityPolicy, r.pspRole, r.pspRoleBinding)
-	}
-
 	for _, res := range objects {
 		o, state, err := res()
 		if err != nil {
@@ -143,23 +163,27 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 				return nil, errors.WrapIf(err, "current config is invalid")
 			}
 			// clean the status so that we can rerun the check
-			return r.statusUpdate(ctx, patchBase, nil)
+			results := r.currentConfigCheckResults(hash)
+			delete(results, hash)
+			if len(results) == 0 {
+				results = nil
+			}
+			return r.statusUpdate(ctx, patchBase, results)
 		}
 
-		if result, ok := r.Logging.Status.ConfigCheckResults[hash]; ok {
+		if _, ok := r.Logging.Status.ConfigCheckResults[hash]; ok {
 			cleaner := configcheck.NewConfigCheckCleaner(r.Client, ComponentConfigCheck)
+			currentHashes := append([]string{hash}, r.shard.ConfigHashes...)
 
 			var cleanupErrs error
-			cleanupErrs = errors.Append(cleanupErrs, cleaner.SecretCleanup(ctx, hash))
-			cleanupErrs = errors.Append(cleanupErrs, cleaner.PodCleanup(ctx, hash))
+			cleanupErrs = errors.Append(cleanupErrs, cleaner.SecretCleanup(ctx, currentHashes...))
+			cleanupErrs = errors.Append(cleanupErrs, cleaner.PodCleanup(ctx, currentHashes...))
 
 			if cleanupErrs != nil {
 				// Errors with the cleanup should not block the reconciliation, we just note it
 				r.Log.Error(err, "issues during configcheck cleanup, moving on")
-			} else if len(r.Logging.Status.ConfigCheckResults) > 1 {
-				return r.statusUpdate(ctx, patchBase, map[string]bool{
-					hash: result,
-				})
+			} else if results := r.currentConfigCheckResults(hash); len(results) < len(r.Logging.Status.ConfigCheckResults) {
+				return r.statusUpdate(ctx, patchBase, results)
 			}
 		} else {
 			// We don't have an existing result
@@ -171,6 +195,20 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 			}
 			if result.Ready {
 				r.Logging.Status.ConfigCheckResults[hash] = result.Valid
+				record := v1beta1.ConfigCheckRecord{
+					Component: "fluentd",
+					Hash:      hash,
+					Valid:     result.Valid,
+					Time:      v1.Now(),
+					Output:    result.Output,
+				}
+				if r.Logging.Spec.FluentdSpec.Shards != nil {
+					record.Shard = utils.IntPointer(r.shard.Index)
+				}
+				r.Logging.RecordConfigCheck(record)
+				if !result.Valid {
+					r.Log.Info("config check failed", "hash", hash, "output", result.Output)
+				}
 				if err := r.Client.Status().Patch(ctx, r.Logging, patchBase); err != nil {
 					return nil, errors.WrapWithDetails(err, "failed to patch status", "logging", r.Logging)
 				} else {
@@ -187,17 +225,31 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 			}
 		}
 	}
-	// Prepare output secret
-	outputSecret, outputSecretDesiredState, err := r.outputSecret(r.secrets, OutputSecretPath)
+	canary, err := r.reconcileCanary(ctx)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to create output secret")
+		return nil, errors.WrapIf(err, "failed to reconcile canary")
+	}
+	if canary.hold {
+		r.servedConfigHash, err = r.liveConfigHash(ctx)
+	} else {
+		r.servedConfigHash, err = r.configHash()
 	}
-	result, err := r.ReconcileResource(outputSecret, outputSecretDesiredState)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to reconcile resource")
+		return nil, err
 	}
-	if result != nil {
-		return result, nil
+	// Prepare output secret, unless the live config is kept during the canary rollout
+	if !canary.hold {
+		outputSecret, outputSecretDesiredState, err := r.outputSecret(r.secrets, OutputSecretPath)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to create output secret")
+		}
+		result, err := r.ReconcileResource(outputSecret, outputSecretDesiredState)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to reconcile resource")
+		}
+		if result != nil {
+			return result, nil
+		}
 	}
 	// Mark watched secrets
 	secretList, state, err := r.markSecrets(r.secrets)
@@ -216,13 +268,19 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 
 	resourceObjects := []resources.Resource{
 		r.secretConfig,
-		r.appConfigSecret,
+	}
+	// the routing table has to describe the served config, so it's kept with the live config during the canary rollout
+	if !canary.hold {
+		resourceObjects = append(resourceObjects, r.appConfigSecret, r.routingTableConfigMap)
+	}
+	resourceObjects = append(resourceObjects,
 		r.statefulset,
 		r.service,
 		r.headlessService,
 		r.serviceMetrics,
 		r.serviceBufferMetrics,
-	}
+		r.networkPolicy,
+	)
 	if resources.IsSupported(ctx, resources.ServiceMonitorKey) {
 		resourceObjects = append(resourceObjects, r.monitorServiceMetrics, r.monitorBufferServiceMetrics)
 	}
@@ -246,11 +304,14 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 		}
 	}
 
-	if res, err := r.reconcileDrain(ctx); res != nil || err != nil {
+	if res, err := r.reconcileDrain(ctx, canary.running); res != nil || err != nil {
 		return res, err
 	}
 
-	return nil, nil
+	if canary.failure != nil {
+		return nil, canary.failure
+	}
+	return canary.result, nil
 }
 
 func (r *Reconciler) statusUpdate(ctx context.Context, patchBase client.Patch, result map[string]bool) (*reconcile.Result, error) {
@@ -263,14 +324,36 @@ func (r *Reconciler) statusUpdate(ctx context.Context, patchBase client.Patch, r
 	}
 }
 
-func (r *Reconciler) reconcileDrain(ctx context.Context) (*reconcile.Result, error) {
+// currentConfigCheckResults returns the config check results of the current config and of the configs of the other shards
+func (r *Reconciler) currentConfigCheckResults(hash string) map[string]bool {
+	results := make(map[string]bool)
+	for _, h := range append([]string{hash}, r.shard.ConfigHashes...) {
+		if result, ok := r.Logging.Status.ConfigCheckResults[h]; ok {
+			results[h] = result
+		}
+	}
+	return results
+}
+
+// qualifiedName is the name of a resource of the shard
+func (r *Reconciler) qualifiedName(name string) string {
+	return r.Logging.FluentdShardQualifiedName(r.shard.Index, name)
+}
+
+// fluentdLabels are the labels of the resources of the shard
+func (r *Reconciler) fluentdLabels(component string) map[string]string {
+	return r.Logging.GetFluentdShardLabels(r.shard.Index, component)
+}
+
+// reconcileDrain drains the buffer PVCs left behind by the scaled down replicas and the removed canary
+func (r *Reconciler) reconcileDrain(ctx context.Context, canaryRunning bool) (*reconcile.Result, error) {
 	if r.Logging.Spec.FluentdSpec.DisablePvc || !r.Logging.Spec.FluentdSpec.Scaling.Drain.Enabled {
 		r.Log.Info("fluentd buffer draining is disabled")
 		return nil, nil
 	}
 
 	nsOpt := client.InNamespace(r.Logging.Spec.ControlNamespace)
-	fluentdLabelSet := r.Logging.GetFluentdLabels(ComponentFluentd)
+	fluentdLabelSet := r.fluentdLabels(ComponentFluentd)
 
 	var pvcList corev1.PersistentVolumeClaimList
 	if err := r.Client.List(ctx, &pvcList, nsOpt,
@@ -285,7 +368,7 @@ func (r *Reconciler) reconcileDrain(ctx context.Context) (*reconcile.Result, err
 		return nil, errors.WrapIf(err, "listing StatefulSet pods")
 	}
 
-	bufVolName := r.Logging.QualifiedName(r.Logging.Spec.FluentdSpec.BufferStorageVolume.PersistentVolumeClaim.PersistentVolumeSource.ClaimName)
+	bufVolName := r.qualifiedName(r.Logging.Spec.FluentdSpec.BufferStorageVolume.PersistentVolumeClaim.PersistentVolumeSource.ClaimName)
 
 	pvcsInUse := make(map[string]bool)
 	for _, pod := range stsPods.Items {
@@ -294,18 +377,21 @@ func (r *Reconciler) reconcileDrain(ctx context.Context) (*reconcile.Result, err
 		}
 	}
 
-	replicaCount, err := NewDataProvider(r.Client, r.Logging).GetReplicaCount(ctx)
+	replicaCount, err := NewDataProvider(r.Client, r.Logging).GetShardReplicaCount(ctx, r.shard.Index)
 	if err != nil {
 		return nil, errors.WrapIf(err, "get replica count for fluentd")
 	}
 
 	// mark PVCs required for upscaling as in-use
 	for i := int32(0); i < utils.PointerToInt32(replicaCount); i++ {
-		pvcsInUse[fmt.Sprintf("%s-%s-%d", bufVolName, r.Logging.QualifiedName(StatefulSetName), i)] = true
+		pvcsInUse[fmt.Sprintf("%s-%s-%d", bufVolName, r.qualifiedName(StatefulSetName), i)] = true
+	}
+	if canaryRunning {
+		pvcsInUse[fmt.Sprintf("%s-%s-0", bufVolName, r.qualifiedName(CanaryStatefulSetName))] = true
 	}
 
 	var jobList batchv1.JobList
-	if err := r.Client.List(ctx, &jobList, nsOpt, client.MatchingLabels(r.Logging.GetFluentdLabels(ComponentDrainer))); err != nil {
+	if err := r.Client.List(ctx, &jobList, nsOpt, client.MatchingLabels(r.fluentdLabels(ComponentDrainer))); err != nil {
 		return nil, errors.WrapIf(err, "listing buffer drainer jobs")
 	}
 
@@ -420,7 +506,8 @@ func RegisterWatches(builder *builder.Builder) *builder.Builder {
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&batchv1.Job{}).
-		Owns(&corev1.PersistentVolumeClaim{})
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&networkingv1.NetworkPolicy{})
 }
 
 var drainableRequirement = requirementMust(labels.NewRequirement("logging.banzaicloud.io/drain", selection.NotEquals, []string{"no"}))
@@ -22,9 +22,9 @@ import (
 // FluentdObjectMeta creates an objectMeta for resource fluentd
 func (r *Reconciler) FluentdObjectMeta(name, component string) metav1.ObjectMeta {
 	o := metav1.ObjectMeta{
-		Name:      r.Logging.QualifiedName(name),
+		Name:      r.qualifiedName(name),
 		Namespace: r.Logging.Spec.ControlNamespace,
-		Labels:    r.Logging.GetFluentdLabels(component),
+		Labels:    r.fluentdLabels(component),
 		OwnerReferences: []metav1.OwnerReference{
 			{
 				APIVersion: r.Logging.APIVersion,
@@ -41,8 +41,8 @@ func (r *Reconciler) FluentdObjectMeta(name, component string) metav1.ObjectMeta
 // FluentdObjectMetaClusterScope creates an objectMeta for resource fluentd
 func (r *Reconciler) FluentdObjectMetaClusterScope(name, component string) metav1.ObjectMeta {
 	o := metav1.ObjectMeta{
-		Name:   r.Logging.QualifiedName(name),
-		Labels: r.Logging.GetFluentdLabels(component),
+		Name:   r.qualifiedName(name),
+		Labels: r.fluentdLabels(component),
 		OwnerReferences: []metav1.OwnerReference{
 			{
 				APIVersion: r.Logging.APIVersion,
@@ -0,0 +1,42 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentd
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+)
+
+func (r *Reconciler) networkPolicy() (runtime.Object, reconciler.DesiredState, error) {
+	desired := &networkingv1.NetworkPolicy{
+		ObjectMeta: r.FluentdObjectMeta(NetworkPolicyName, ComponentFluentd),
+	}
+	policy := r.Logging.Spec.FluentdSpec.NetworkPolicy
+	if policy == nil {
+		return desired, reconciler.StateAbsent, nil
+	}
+
+	spec := r.Logging.Spec.FluentdSpec
+	openPorts := generatePorts(spec)[1:]
+	if spec.BufferVolumeMetrics != nil {
+		openPorts = append(openPorts, generatePortsBufferVolumeMetrics(spec)...)
+	}
+	desired.Spec = podsecurity.AggregatorNetworkPolicySpec(r.fluentdLabels(ComponentFluentd), r.Logging.Name, spec.Port, openPorts, *policy)
+
+	return desired, reconciler.StatePresent, nil
+}
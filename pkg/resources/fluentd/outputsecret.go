@@ -25,6 +25,8 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 )
 
 func (r *Reconciler) markSecrets(secrets *secret.MountSecrets) ([]runtime.Object, reconciler.DesiredState, error) {
@@ -37,6 +39,10 @@ func (r *Reconciler) markSecrets(secrets *secret.MountSecrets) ([]runtime.Object
 	annotationKey := fmt.Sprintf("logging.banzaicloud.io/%s", loggingRef)
 	var markedSecrets []runtime.Object
 	for _, secret := range *secrets {
+		if _, _, ok := secretbackend.ParseRef(secret.Name); ok {
+			// values read from secret backends have no kubernetes secret to mark
+			continue
+		}
 		secretItem := &corev1.Secret{}
 		err := r.Client.Get(context.TODO(), types.NamespacedName{
 			Name:      secret.Name,
@@ -58,7 +64,7 @@ func (r *Reconciler) outputSecret(secrets *secret.MountSecrets, mountPath string
 	// Initialise output secret
 	fluentOutputSecret := &corev1.Secret{
 		ObjectMeta: v1.ObjectMeta{
-			Name:      r.Logging.QualifiedName(OutputSecretName),
+			Name:      r.qualifiedName(OutputSecretName),
 			Namespace: r.Logging.Spec.ControlNamespace,
 		},
 	}
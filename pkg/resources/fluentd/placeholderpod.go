@@ -23,7 +23,7 @@ import (
 
 func (r *Reconciler) placeholderPodFor(pvc corev1.PersistentVolumeClaim) *corev1.Pod {
 	return &corev1.Pod{
-		ObjectMeta: r.FluentdObjectMeta(StatefulSetName+pvc.Name[strings.LastIndex(pvc.Name, "-"):], ComponentPlaceholder),
+		ObjectMeta: r.FluentdObjectMeta(r.bufferPodName(pvc), ComponentPlaceholder),
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
@@ -41,3 +41,13 @@ func (r *Reconciler) placeholderPodFor(pvc corev1.PersistentVolumeClaim) *corev1
 		},
 	}
 }
+
+// bufferPodName returns the name of the statefulset pod the buffer PVC has been created for,
+// a replica of the fluentd statefulset or the canary
+func (r *Reconciler) bufferPodName(pvc corev1.PersistentVolumeClaim) string {
+	ordinal := pvc.Name[strings.LastIndex(pvc.Name, "-"):]
+	if strings.HasSuffix(strings.TrimSuffix(pvc.Name, ordinal), "-"+r.qualifiedName(CanaryStatefulSetName)) {
+		return CanaryStatefulSetName + ordinal
+	}
+	return StatefulSetName + ordinal
+}
@@ -28,7 +28,7 @@ func (r *Reconciler) service() (runtime.Object, reconciler.DesiredState, error)
 	desired := &corev1.Service{
 		ObjectMeta: r.FluentdObjectMeta(ServiceName, ComponentFluentd),
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
+			Ports: r.extraInputServicePorts([]corev1.ServicePort{
 				{
 					Name:       "tcp-fluentd",
 					Protocol:   corev1.ProtocolTCP,
@@ -41,8 +41,8 @@ func (r *Reconciler) service() (runtime.Object, reconciler.DesiredState, error)
 					Port:       24240,
 					TargetPort: intstr.IntOrString{IntVal: 24240},
 				},
-			},
-			Selector: r.Logging.GetFluentdLabels(ComponentFluentd),
+			}),
+			Selector: r.fluentdLabels(ComponentFluentd),
 			Type:     corev1.ServiceTypeClusterIP,
 		},
 	}
@@ -72,7 +72,7 @@ func (r *Reconciler) serviceMetrics() (runtime.Object, reconciler.DesiredState,
 						TargetPort: intstr.IntOrString{IntVal: r.Logging.Spec.FluentdSpec.Metrics.Port},
 					},
 				},
-				Selector:  r.Logging.GetFluentdLabels(ComponentFluentd),
+				Selector:  r.fluentdLabels(ComponentFluentd),
 				Type:      corev1.ServiceTypeClusterIP,
 				ClusterIP: "None",
 			},
@@ -109,7 +109,7 @@ func (r *Reconciler) monitorServiceMetrics() (runtime.Object, reconciler.Desired
 					Scheme:               r.Logging.Spec.FluentdSpec.Metrics.ServiceMonitorConfig.Scheme,
 					TLSConfig:            r.Logging.Spec.FluentdSpec.Metrics.ServiceMonitorConfig.TLSConfig,
 				}},
-				Selector:          v12.LabelSelector{MatchLabels: r.Logging.GetFluentdLabels(ComponentFluentd)},
+				Selector:          v12.LabelSelector{MatchLabels: r.fluentdLabels(ComponentFluentd)},
 				NamespaceSelector: v1.NamespaceSelector{MatchNames: []string{r.Logging.Spec.ControlNamespace}},
 				SampleLimit:       0,
 			},
@@ -139,7 +139,7 @@ func (r *Reconciler) serviceBufferMetrics() (runtime.Object, reconciler.DesiredS
 						TargetPort: intstr.IntOrString{IntVal: port},
 					},
 				},
-				Selector:  r.Logging.GetFluentdLabels(ComponentFluentd),
+				Selector:  r.fluentdLabels(ComponentFluentd),
 				Type:      corev1.ServiceTypeClusterIP,
 				ClusterIP: "None",
 			},
@@ -173,7 +173,7 @@ func (r *Reconciler) monitorBufferServiceMetrics() (runtime.Object, reconciler.D
 					RelabelConfigs:       r.Logging.Spec.FluentdSpec.BufferVolumeMetrics.ServiceMonitorConfig.Relabelings,
 					MetricRelabelConfigs: r.Logging.Spec.FluentdSpec.BufferVolumeMetrics.ServiceMonitorConfig.MetricsRelabelings,
 				}},
-				Selector:          v12.LabelSelector{MatchLabels: r.Logging.GetFluentdLabels(ComponentFluentd)},
+				Selector:          v12.LabelSelector{MatchLabels: r.fluentdLabels(ComponentFluentd)},
 				NamespaceSelector: v1.NamespaceSelector{MatchNames: []string{r.Logging.Spec.ControlNamespace}},
 				SampleLimit:       0,
 			},
@@ -189,7 +189,7 @@ func (r *Reconciler) headlessService() (runtime.Object, reconciler.DesiredState,
 	desired := &corev1.Service{
 		ObjectMeta: r.FluentdObjectMeta(ServiceName+"-headless", ComponentFluentd),
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
+			Ports: r.extraInputServicePorts([]corev1.ServicePort{
 				{
 					Name:       "tcp-fluentd",
 					Protocol:   corev1.ProtocolTCP,
@@ -202,11 +202,24 @@ func (r *Reconciler) headlessService() (runtime.Object, reconciler.DesiredState,
 					Port:       24240,
 					TargetPort: intstr.IntOrString{IntVal: 24240},
 				},
-			},
-			Selector:  r.Logging.GetFluentdLabels(ComponentFluentd),
+			}),
+			Selector:  r.fluentdLabels(ComponentFluentd),
 			Type:      corev1.ServiceTypeClusterIP,
 			ClusterIP: corev1.ClusterIPNone,
 		},
 	}
 	return desired, reconciler.StatePresent, nil
 }
+
+// extraInputServicePorts appends the ports of the extra inputs
+func (r *Reconciler) extraInputServicePorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	for _, in := range r.Logging.Spec.FluentdSpec.ExtraInputs {
+		ports = append(ports, corev1.ServicePort{
+			Name:       in.Name,
+			Protocol:   in.Protocol(),
+			Port:       in.Port,
+			TargetPort: intstr.IntOrString{IntVal: in.Port},
+		})
+	}
+	return ports
+}
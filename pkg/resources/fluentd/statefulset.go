@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	util "github.com/cisco-open/operator-tools/pkg/utils"
 	"github.com/spf13/cast"
@@ -26,24 +27,29 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 )
 
 func (r *Reconciler) statefulset() (runtime.Object, reconciler.DesiredState, error) {
 	spec := r.statefulsetSpec()
 
-	r.Logging.Spec.FluentdSpec.BufferStorageVolume.WithDefaultHostPath(
-		fmt.Sprintf(v1beta1.HostPath, r.Logging.Name, r.Logging.QualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName)),
+	// the spec is shared by the shards, each of them defaults the host path to its own directory
+	bufferStorageVolume := r.Logging.Spec.FluentdSpec.BufferStorageVolume.DeepCopy()
+	bufferStorageVolume.WithDefaultHostPath(
+		fmt.Sprintf(v1beta1.HostPath, r.Logging.Name, r.qualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName)),
 	)
 	if !r.Logging.Spec.FluentdSpec.DisablePvc {
-		err := r.Logging.Spec.FluentdSpec.BufferStorageVolume.ApplyPVCForStatefulSet(containerName, bufferPath, spec, func(name string) metav1.ObjectMeta {
+		err := bufferStorageVolume.ApplyPVCForStatefulSet(containerName, bufferPath, spec, func(name string) metav1.ObjectMeta {
 			return r.FluentdObjectMeta(name, ComponentFluentd)
 		})
 		if err != nil {
 			return nil, reconciler.StatePresent, err
 		}
 	} else {
-		err := r.Logging.Spec.FluentdSpec.BufferStorageVolume.ApplyVolumeForPodSpec(r.Logging.QualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName), containerName, bufferPath, &spec.Template.Spec)
+		err := bufferStorageVolume.ApplyVolumeForPodSpec(r.qualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName), containerName, bufferPath, &spec.Template.Spec)
 		if err != nil {
 			return nil, reconciler.StatePresent, err
 		}
@@ -61,6 +67,7 @@ func (r *Reconciler) statefulset() (runtime.Object, reconciler.DesiredState, err
 			}
 		}
 	}
+	secretbackend.ApplyVolumes(r.Logging.Spec.SecretBackends, containerName, SecretBackendsPath, &spec.Template.Spec)
 
 	desired := &appsv1.StatefulSet{
 		ObjectMeta: r.FluentdObjectMeta(StatefulSetName, ComponentFluentd),
@@ -68,6 +75,14 @@ func (r *Reconciler) statefulset() (runtime.Object, reconciler.DesiredState, err
 	}
 
 	desired.Annotations = util.MergeLabels(desired.Annotations, r.Logging.Spec.FluentdSpec.StatefulSetAnnotations)
+	if r.servedConfigHash != "" {
+		// the health status reports the config of the statefulset as live once it has been observed
+		desired.Annotations = util.MergeLabels(desired.Annotations, map[string]string{configcheck.ConfigHashAnnotation: r.servedConfigHash})
+	}
+
+	if err := podsecurity.Validate(r.Logging.Spec.FluentdSpec.Security.PodSecurityStandard, desired.Spec.Template.Spec); err != nil {
+		return nil, reconciler.StatePresent, errors.WrapIf(err, "fluentd statefulset")
+	}
 
 	return desired, reconciler.StatePresent, nil
 }
@@ -91,9 +106,7 @@ func (r *Reconciler) statefulsetSpec() *appsv1.StatefulSetSpec {
 
 	sts := &appsv1.StatefulSetSpec{
 		PodManagementPolicy: appsv1.PodManagementPolicyType(r.Logging.Spec.FluentdSpec.Scaling.PodManagementPolicy),
-		Selector: &metav1.LabelSelector{
-			MatchLabels: r.Logging.GetFluentdLabels(ComponentFluentd),
-		},
+		Selector:            r.statefulsetSelector(),
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: r.generatePodMeta(),
 			Spec: corev1.PodSpec{
@@ -110,13 +123,14 @@ func (r *Reconciler) statefulsetSpec() *appsv1.StatefulSetSpec {
 				DNSPolicy:                 r.Logging.Spec.FluentdSpec.DNSPolicy,
 				DNSConfig:                 r.Logging.Spec.FluentdSpec.DNSConfig,
 				SecurityContext: &corev1.PodSecurityContext{
-					RunAsNonRoot: r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsNonRoot,
-					FSGroup:      r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.FSGroup,
-					RunAsUser:    r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsUser,
-					RunAsGroup:   r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsGroup},
+					RunAsNonRoot:   r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsNonRoot,
+					FSGroup:        r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.FSGroup,
+					RunAsUser:      r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsUser,
+					RunAsGroup:     r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.RunAsGroup,
+					SeccompProfile: r.Logging.Spec.FluentdSpec.Security.PodSecurityContext.SeccompProfile},
 			},
 		},
-		ServiceName: r.Logging.QualifiedName(ServiceName + "-headless"),
+		ServiceName: r.qualifiedName(ServiceName + "-headless"),
 	}
 
 	if r.Logging.Spec.FluentdSpec.Scaling.Replicas > 0 {
@@ -146,6 +160,8 @@ func fluentContainer(spec *v1beta1.FluentdSpec) corev1.Container {
 			Privileged:               spec.Security.SecurityContext.Privileged,
 			RunAsNonRoot:             spec.Security.SecurityContext.RunAsNonRoot,
 			SELinuxOptions:           spec.Security.SecurityContext.SELinuxOptions,
+			Capabilities:             spec.Security.SecurityContext.Capabilities,
+			SeccompProfile:           spec.Security.SecurityContext.SeccompProfile,
 		},
 		Env:            envVars,
 		LivenessProbe:  spec.LivenessProbe,
@@ -166,9 +182,26 @@ func fluentContainer(spec *v1beta1.FluentdSpec) corev1.Container {
 	return container
 }
 
+// statefulsetSelector selects the fluentd pods, without the pods of the canary statefulset if the canary is enabled
+func (r *Reconciler) statefulsetSelector() *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{
+		MatchLabels: r.fluentdLabels(ComponentFluentd),
+	}
+	if r.Logging.Spec.FluentdSpec.Canary != nil {
+		selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{
+				Key:      canaryLabel,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"true"},
+			},
+		}
+	}
+	return selector
+}
+
 func (r *Reconciler) generatePodMeta() metav1.ObjectMeta {
 	meta := metav1.ObjectMeta{
-		Labels: r.Logging.GetFluentdLabels(ComponentFluentd),
+		Labels: r.fluentdLabels(ComponentFluentd),
 	}
 	if r.Logging.Spec.FluentdSpec.Annotations != nil {
 		meta.Annotations = r.Logging.Spec.FluentdSpec.Annotations
@@ -207,6 +240,13 @@ func newConfigMapReloader(spec *v1beta1.FluentdSpec) *corev1.Container {
 		})
 	}
 
+	// reload on rotation of the mounted output secrets as well
+	args = append(args, "--volume-dir="+OutputSecretPath)
+	vm = append(vm, corev1.VolumeMount{
+		Name:      "output-secret",
+		MountPath: OutputSecretPath,
+	})
+
 	c := &corev1.Container{
 		Name:            "config-reloader",
 		ImagePullPolicy: corev1.PullPolicy(spec.ConfigReloaderImage.PullPolicy),
@@ -225,6 +265,8 @@ func newConfigMapReloader(spec *v1beta1.FluentdSpec) *corev1.Container {
 			Privileged:               spec.Security.SecurityContext.Privileged,
 			RunAsNonRoot:             spec.Security.SecurityContext.RunAsNonRoot,
 			SELinuxOptions:           spec.Security.SecurityContext.SELinuxOptions,
+			Capabilities:             spec.Security.SecurityContext.Capabilities,
+			SeccompProfile:           spec.Security.SecurityContext.SeccompProfile,
 		}
 	}
 
@@ -260,6 +302,13 @@ func generatePorts(spec *v1beta1.FluentdSpec) []corev1.ContainerPort {
 			Protocol:      "TCP",
 		})
 	}
+	for _, in := range spec.ExtraInputs {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          in.Name,
+			ContainerPort: in.Port,
+			Protocol:      in.Protocol(),
+		})
+	}
 	return ports
 }
 
@@ -293,7 +342,7 @@ func (r *Reconciler) generateVolume() (v []corev1.Volume) {
 			Name: "config",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(SecretConfigName),
+					SecretName: r.qualifiedName(SecretConfigName),
 				},
 			},
 		},
@@ -301,7 +350,7 @@ func (r *Reconciler) generateVolume() (v []corev1.Volume) {
 			Name: "output-secret",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(OutputSecretName),
+					SecretName: r.qualifiedName(OutputSecretName),
 				},
 			},
 		},
@@ -318,7 +367,7 @@ func (r *Reconciler) generateVolume() (v []corev1.Volume) {
 			Name: "app-config-compress",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(AppSecretConfigName),
+					SecretName: r.qualifiedName(AppSecretConfigName),
 				},
 			},
 		})
@@ -327,7 +376,7 @@ func (r *Reconciler) generateVolume() (v []corev1.Volume) {
 			Name: "app-config",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: r.Logging.QualifiedName(AppSecretConfigName),
+					SecretName: r.qualifiedName(AppSecretConfigName),
 				},
 			},
 		})
@@ -356,7 +405,7 @@ func (r *Reconciler) volumeMountHackContainer() *corev1.Container {
 			Command:         []string{"sh", "-c", "chmod -R 777 " + bufferPath},
 			VolumeMounts: []corev1.VolumeMount{
 				{
-					Name:      r.Logging.QualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName),
+					Name:      r.qualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName),
 					MountPath: bufferPath,
 				},
 			},
@@ -387,7 +436,7 @@ func (r *Reconciler) bufferMetricsSidecarContainer() *corev1.Container {
 			Ports:           generatePortsBufferVolumeMetrics(r.Logging.Spec.FluentdSpec),
 			VolumeMounts: []corev1.VolumeMount{
 				{
-					Name:      r.Logging.QualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName),
+					Name:      r.qualifiedName(v1beta1.DefaultFluentdBufferStorageVolumeName),
 					MountPath: bufferPath,
 				},
 			},
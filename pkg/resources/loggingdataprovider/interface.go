@@ -20,4 +20,6 @@ import (
 
 type LoggingDataProvider interface {
 	GetReplicaCount(ctx context.Context) (*int32, error)
+	// GetShardNamespaces returns the namespaces assigned to each shard of the aggregator, nil if it isn't sharded
+	GetShardNamespaces() map[int32][]string
 }
@@ -0,0 +1,171 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/go-logr/logr"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+const flowTapSampleKey = "__flowtap_sample"
+
+// tapsFor returns the unexpired taps attached to the given flow
+func tapsFor(taps []v1beta1.FlowTap, kind, namespace, name string, now time.Time) (res []v1beta1.FlowTap) {
+	for _, tap := range taps {
+		if tap.Namespace == namespace && tap.Spec.FlowRef.Kind == kind && tap.Spec.FlowRef.Name == name && !tap.Expired(now) {
+			res = append(res, tap)
+		}
+	}
+	return
+}
+
+func flowTapTargetExists(tap v1beta1.FlowTap, resources FluentdLoggingResources) bool {
+	switch tap.Spec.FlowRef.Kind {
+	case v1beta1.FlowTapKindFlow:
+		for _, flow := range resources.Flows {
+			if flow.Namespace == tap.Namespace && flow.Name == tap.Spec.FlowRef.Name {
+				return true
+			}
+		}
+	case v1beta1.FlowTapKindClusterFlow:
+		for _, flow := range resources.ClusterFlows {
+			if flow.Namespace == tap.Namespace && flow.Name == tap.Spec.FlowRef.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// registerFlowTaps registers a label for each tap of the flow and copies the records of the flow to them.
+// Taps are meant for debugging, so a broken tap is skipped instead of failing the whole configuration.
+func registerFlowTaps(builder *types.SystemBuilder, flow *types.Flow, taps []v1beta1.FlowTap, resources FluentdLoggingResources, secrets SecretLoaderFactory, logger logr.Logger) error {
+	for _, tap := range taps {
+		tapFlow, err := FlowForFlowTap(tap, resources, secrets)
+		if err != nil {
+			logger.Error(err, "FlowTap contains errors, skipping.", "flowtap", fmt.Sprintf("%s/%s", tap.Namespace, tap.Name))
+			continue
+		}
+		if err := builder.RegisterFlow(tapFlow); err != nil {
+			return err
+		}
+		relabel, err := (&output.RelabelOutputConfig{
+			Label: tapFlow.FlowLabel,
+		}).ToDirective(nil, tapFlow.FlowID)
+		if err != nil {
+			return err
+		}
+		flow.WithOutputs(relabel)
+	}
+	return nil
+}
+
+// FlowForFlowTap creates the label receiving the copy of the records of the tapped flow
+func FlowForFlowTap(tap v1beta1.FlowTap, resources FluentdLoggingResources, secrets SecretLoaderFactory) (*types.Flow, error) {
+	flowID := fmt.Sprintf("flowtap:%s:%s", tap.Namespace, tap.Name)
+	result, err := types.NewFlow(nil, flowID, "flowtap:"+tap.Name, tap.Namespace, "", utils.BoolPointer(false))
+	if err != nil {
+		return nil, err
+	}
+
+	if tap.Spec.SamplingPercentage > 0 && tap.Spec.SamplingPercentage < 100 {
+		filters, err := flowTapSamplingFilters(flowID, tap.Spec.SamplingPercentage)
+		if err != nil {
+			return nil, err
+		}
+		result.WithFilters(filters...)
+	}
+
+	var kinds []string
+	if tap.Spec.Stdout != nil {
+		kinds = append(kinds, "stdout")
+	}
+	if tap.Spec.File != nil {
+		kinds = append(kinds, "file")
+	}
+	if tap.Spec.LocalOutputRef != "" {
+		kinds = append(kinds, "localOutputRef")
+	}
+	if len(kinds) != 1 {
+		return nil, errors.Errorf("flowtap %s/%s must have exactly one target, got %v", tap.Namespace, tap.Name, kinds)
+	}
+
+	var plugin types.Directive
+	switch {
+	case tap.Spec.Stdout != nil:
+		stdout, err := tap.Spec.Stdout.ToDirective(nil, flowID+":stdout")
+		if err != nil {
+			return nil, err
+		}
+		result.WithFilters(stdout)
+		plugin, err = output.NewNullOutputConfig().ToDirective(nil, flowID+":null")
+		if err != nil {
+			return nil, err
+		}
+	case tap.Spec.File != nil:
+		plugin, err = tap.Spec.File.ToDirective(secrets.OutputSecretLoaderForNamespace(tap.Namespace), flowID+":file")
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to create file output for flowtap %s/%s", tap.Namespace, tap.Name)
+		}
+	default:
+		out := resources.Outputs.FindByNamespacedName(tap.Namespace, tap.Spec.LocalOutputRef)
+		if out == nil {
+			return nil, errors.Errorf("referenced output %s not found for flowtap %s/%s", tap.Spec.LocalOutputRef, tap.Namespace, tap.Name)
+		}
+		outputID := fmt.Sprintf("%s:output:%s:%s", flowID, out.Namespace, out.Name)
+		plugin, err = createOutput(out.Spec, outputID, out.Namespace, resources.Outputs, resources.ClusterOutputs, secrets)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to create configured output %s/%s", out.Namespace, out.Name)
+		}
+	}
+	return result.WithOutputs(plugin), nil
+}
+
+// flowTapSamplingFilters keep the given percentage of the records using the built-in filters of fluentd
+func flowTapSamplingFilters(flowID string, percentage int32) ([]types.Filter, error) {
+	mark, err := (&filter.RecordTransformer{
+		EnableRuby: true,
+		Records: []filter.Record{
+			{flowTapSampleKey: fmt.Sprintf("${rand(100) < %d}", percentage)},
+		},
+	}).ToDirective(nil, flowID+":sample")
+	if err != nil {
+		return nil, err
+	}
+	grep, err := (&filter.GrepConfig{
+		Regexp: []filter.RegexpSection{
+			{Key: flowTapSampleKey, Pattern: "/^true$/"},
+		},
+	}).ToDirective(nil, flowID+":grep")
+	if err != nil {
+		return nil, err
+	}
+	unmark, err := (&filter.RecordTransformer{
+		RemoveKeys: flowTapSampleKey,
+	}).ToDirective(nil, flowID+":unmark")
+	if err != nil {
+		return nil, err
+	}
+	return []types.Filter{mark, grep, unmark}, nil
+}
@@ -0,0 +1,169 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/render"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+func TestFlowTap(t *testing.T) {
+	resources := FluentdLoggingResources{
+		Flows: []v1beta1.Flow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "app"},
+				Spec: v1beta1.FlowSpec{
+					LocalOutputRefs: []string{"null"},
+				},
+			},
+		},
+		Outputs: Outputs{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "null"},
+				Spec:       v1beta1.OutputSpec{NullOutputConfig: output.NewNullOutputConfig()},
+			},
+		},
+		FlowTaps: []v1beta1.FlowTap{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "debug"},
+				Spec: v1beta1.FlowTapSpec{
+					FlowRef:            v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindFlow, Name: "app"},
+					SamplingPercentage: 10,
+					Stdout:             &filter.StdOutFilterConfig{},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "apps",
+					Name:              "expired",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				},
+				Spec: v1beta1.FlowTapSpec{
+					FlowRef: v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindFlow, Name: "app"},
+					TTL:     &metav1.Duration{Duration: time.Hour},
+					Stdout:  &filter.StdOutFilterConfig{},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "other"},
+				Spec: v1beta1.FlowTapSpec{
+					FlowRef: v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindClusterFlow, Name: "app"},
+					Stdout:  &filter.StdOutFilterConfig{},
+				},
+			},
+		},
+	}
+
+	builder := types.NewSystemBuilder(nil, nil, types.NewRouter("main", nil))
+	flow, err := FlowForFlow(resources.Flows[0], resources.ClusterOutputs, resources.Outputs, nil, testSecretLoaderFactory{})
+	require.NoError(t, err)
+	taps := tapsFor(resources.FlowTaps, v1beta1.FlowTapKindFlow, "apps", "app", time.Now())
+	require.Len(t, taps, 1)
+	require.NoError(t, registerFlowTaps(builder, flow, taps, resources, testSecretLoaderFactory{}, logr.Discard()))
+	require.NoError(t, builder.RegisterFlow(flow))
+	system, err := builder.Build()
+	require.NoError(t, err)
+
+	b := &bytes.Buffer{}
+	renderer := render.FluentRender{Out: b, Indent: 2}
+	require.NoError(t, renderer.RenderDirectives(system.Flows[0].GetSections(), 0))
+	require.NoError(t, renderer.RenderDirectives(system.Flows[1].GetSections(), 0))
+
+	expected := `
+		<filter **>
+		  @type record_transformer
+		  @id flowtap:apps:debug:sample
+		  enable_ruby true
+		  <record>
+		    __flowtap_sample ${rand(100) < 10}
+		  </record>
+		</filter>
+		<filter **>
+		  @type grep
+		  @id flowtap:apps:debug:grep
+		  <regexp>
+		    key __flowtap_sample
+		    pattern /^true$/
+		  </regexp>
+		</filter>
+		<filter **>
+		  @type record_transformer
+		  @id flowtap:apps:debug:unmark
+		  remove_keys __flowtap_sample
+		</filter>
+		<filter **>
+		  @type stdout
+		  @id flowtap:apps:debug:stdout
+		</filter>
+		<match **>
+		  @type null
+		  @id flowtap:apps:debug:null
+		</match>
+		<match **>
+		  @type copy
+		  <store>
+		    @type null
+		    @id flow:apps:app:output:apps:null
+		  </store>
+		  <store>
+		    @type relabel
+		    @id flowtap:apps:debug
+		    @label ` + system.Flows[0].FlowLabel + `
+		  </store>
+		</match>`
+	if a, e := diff.TrimLinesInString(b.String()), diff.TrimLinesInString(expected); a != e {
+		t.Errorf("Result does not match (-actual vs +expected):\n%v\nActual: %s", diff.LineDiff(a, e), b.String())
+	}
+}
+
+func TestFlowTapInvalid(t *testing.T) {
+	testCases := map[string]v1beta1.FlowTapSpec{
+		"no target": {
+			FlowRef: v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindFlow, Name: "app"},
+		},
+		"multiple targets": {
+			FlowRef:        v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindFlow, Name: "app"},
+			Stdout:         &filter.StdOutFilterConfig{},
+			LocalOutputRef: "debug",
+		},
+		"missing output": {
+			FlowRef:        v1beta1.FlowTapFlowRef{Kind: v1beta1.FlowTapKindFlow, Name: "app"},
+			LocalOutputRef: "debug",
+		},
+	}
+	for name, spec := range testCases {
+		spec := spec
+		t.Run(name, func(t *testing.T) {
+			tap := v1beta1.FlowTap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "debug"},
+				Spec:       spec,
+			}
+			_, err := FlowForFlowTap(tap, FluentdLoggingResources{}, testSecretLoaderFactory{})
+			require.Error(t, err)
+		})
+	}
+}
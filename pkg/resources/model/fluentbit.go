@@ -0,0 +1,186 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/merge"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// InheritFluentbitBase returns the spec of the agent merged onto the spec of its base agent, following the chain of base references.
+// Fields set in the agent take precedence using strategic merge, the loggingRef and the baseRef always belong to the agent.
+func InheritFluentbitBase(agent v1beta1.FluentbitAgent, agents []v1beta1.FluentbitAgent) (v1beta1.FluentbitSpec, error) {
+	chain := []v1beta1.FluentbitAgent{agent}
+	visited := map[string]bool{agent.Name: true}
+	for current := agent; current.Spec.BaseRef != ""; {
+		base := findFluentbitAgent(agents, current.Spec.BaseRef)
+		if base == nil {
+			return agent.Spec, errors.Errorf("base FluentbitAgent %s of %s not found", current.Spec.BaseRef, current.Name)
+		}
+		if visited[base.Name] {
+			return agent.Spec, errors.Errorf("circular base reference of FluentbitAgent %s through %s", agent.Name, current.Name)
+		}
+		visited[base.Name] = true
+		chain = append(chain, *base)
+		current = *base
+	}
+
+	spec := *chain[len(chain)-1].Spec.DeepCopy()
+	for i := len(chain) - 2; i >= 0; i-- {
+		if err := merge.Merge(&spec, chain[i].Spec); err != nil {
+			return agent.Spec, errors.WrapIff(err, "merging FluentbitAgent %s onto its base %s", chain[i].Name, chain[i].Spec.BaseRef)
+		}
+	}
+	spec.LoggingRef = agent.Spec.LoggingRef
+	spec.BaseRef = agent.Spec.BaseRef
+	return spec, nil
+}
+
+func findFluentbitAgent(agents []v1beta1.FluentbitAgent, name string) *v1beta1.FluentbitAgent {
+	for i := range agents {
+		if agents[i].Name == name {
+			return &agents[i]
+		}
+	}
+	return nil
+}
+
+// FluentbitAgentOverlaps returns the problems of the agents that would be scheduled to the same nodes,
+// collecting the logs of those nodes twice. Agents are expected to have their bases resolved already.
+func FluentbitAgentOverlaps(agents []v1beta1.FluentbitAgent, nodes []corev1.Node) map[string][]string {
+	scheduledTo := make([][]string, len(agents))
+	for i, agent := range agents {
+		for _, node := range nodes {
+			if FluentbitAgentSchedulesOn(agent.Spec, node) {
+				scheduledTo[i] = append(scheduledTo[i], node.Name)
+			}
+		}
+	}
+
+	problems := make(map[string][]string)
+	for i := range agents {
+		for j := i + 1; j < len(agents); j++ {
+			shared := intersect(scheduledTo[i], scheduledTo[j])
+			if len(shared) == 0 {
+				continue
+			}
+			sort.Strings(shared)
+			nodesText := strings.Join(shared, ", ")
+			if len(shared) > 3 {
+				nodesText = fmt.Sprintf("%s and %d more", strings.Join(shared[:3], ", "), len(shared)-3)
+			}
+			problems[agents[i].Name] = append(problems[agents[i].Name],
+				fmt.Sprintf("overlaps with FluentbitAgent %s, both collect the logs of nodes: %s", agents[j].Name, nodesText))
+			problems[agents[j].Name] = append(problems[agents[j].Name],
+				fmt.Sprintf("overlaps with FluentbitAgent %s, both collect the logs of nodes: %s", agents[i].Name, nodesText))
+		}
+	}
+	return problems
+}
+
+func intersect(a, b []string) (res []string) {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if set[s] {
+			res = append(res, s)
+		}
+	}
+	return
+}
+
+// FluentbitAgentSchedulesOn tells whether the daemonset of the agent has a pod on the node,
+// based on the node selector, the required node affinity and the tolerations of the agent
+func FluentbitAgentSchedulesOn(spec v1beta1.FluentbitSpec, node corev1.Node) bool {
+	if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		if required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && !nodeSelectorMatches(*required, node) {
+			return false
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		// the daemonset controller tolerates the node condition taints automatically
+		if strings.HasPrefix(taint.Key, "node.kubernetes.io/") {
+			continue
+		}
+		if !tolerates(spec.EffectiveTolerations(), taint) {
+			return false
+		}
+	}
+	return true
+}
+
+func tolerates(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorMatches evaluates the terms of a node selector, which are ORed, while the requirements of a term are ANDed
+func nodeSelectorMatches(nodeSelector corev1.NodeSelector, node corev1.Node) bool {
+	for _, term := range nodeSelector.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if requirementsMatch(term.MatchExpressions, labels.Set(node.Labels)) &&
+			requirementsMatch(term.MatchFields, labels.Set{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+	return false
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func requirementsMatch(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+	for _, req := range requirements {
+		op, ok := nodeSelectorOperators[req.Operator]
+		if !ok {
+			return false
+		}
+		requirement, err := labels.NewRequirement(req.Key, op, req.Values)
+		if err != nil || !requirement.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
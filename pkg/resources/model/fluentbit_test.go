@@ -0,0 +1,152 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestInheritFluentbitBase(t *testing.T) {
+	agents := []v1beta1.FluentbitAgent{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: v1beta1.FluentbitSpec{
+				LoggingRef:   "infra",
+				LogLevel:     "info",
+				Flush:        5,
+				NodeSelector: map[string]string{"pool": "default"},
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpExists},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+			Spec: v1beta1.FluentbitSpec{
+				BaseRef:      "default",
+				LogLevel:     "debug",
+				NodeSelector: map[string]string{"pool": "gpu"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-spot"},
+			Spec: v1beta1.FluentbitSpec{
+				BaseRef: "gpu",
+				Grace:   1,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "missing"},
+			Spec:       v1beta1.FluentbitSpec{BaseRef: "nonexistent"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a"},
+			Spec:       v1beta1.FluentbitSpec{BaseRef: "b"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b"},
+			Spec:       v1beta1.FluentbitSpec{BaseRef: "a"},
+		},
+	}
+
+	spec, err := InheritFluentbitBase(agents[2], agents)
+	require.NoError(t, err)
+	require.Equal(t, "debug", spec.LogLevel)
+	require.Equal(t, int32(5), spec.Flush)
+	require.Equal(t, int32(1), spec.Grace)
+	require.Equal(t, map[string]string{"pool": "gpu"}, spec.NodeSelector)
+	require.Len(t, spec.Tolerations, 1)
+	require.Equal(t, "gpu", spec.BaseRef)
+	require.Empty(t, spec.LoggingRef)
+
+	_, err = InheritFluentbitBase(agents[3], agents)
+	require.EqualError(t, err, "base FluentbitAgent nonexistent of missing not found")
+
+	_, err = InheritFluentbitBase(agents[4], agents)
+	require.EqualError(t, err, "circular base reference of FluentbitAgent a through b")
+}
+
+func TestFluentbitAgentOverlaps(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-default", Labels: map[string]string{"pool": "default"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-gpu", Labels: map[string]string{"pool": "gpu"}}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-windows", Labels: map[string]string{"pool": "windows"}},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		},
+	}
+	notGPU := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	agents := []v1beta1.FluentbitAgent{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       v1beta1.FluentbitSpec{Affinity: notGPU},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+			Spec:       v1beta1.FluentbitSpec{NodeSelector: map[string]string{"pool": "gpu"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "windows"},
+			Spec: v1beta1.FluentbitSpec{
+				NodeSelector: map[string]string{"pool": "windows"},
+				Tolerations:  []corev1.Toleration{{Key: "os", Operator: corev1.TolerationOpEqual, Value: "windows"}},
+			},
+		},
+	}
+	require.Empty(t, FluentbitAgentOverlaps(agents, nodes))
+
+	agents[0].Spec.Affinity = nil
+	require.Equal(t, map[string][]string{
+		"default": {"overlaps with FluentbitAgent gpu, both collect the logs of nodes: node-gpu"},
+		"gpu":     {"overlaps with FluentbitAgent default, both collect the logs of nodes: node-gpu"},
+	}, FluentbitAgentOverlaps(agents, nodes))
+}
+
+func TestFluentbitAgentSchedulesOnControlPlaneWithAuditLogs(t *testing.T) {
+	controlPlane := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "control-plane"},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	spec := v1beta1.FluentbitSpec{}
+	require.False(t, FluentbitAgentSchedulesOn(spec, controlPlane))
+
+	spec.AuditLogs = &v1beta1.FluentbitAuditLogs{}
+	require.True(t, FluentbitAgentSchedulesOn(spec, controlPlane))
+
+	spec.AuditLogs.DisableControlPlaneTolerations = true
+	require.False(t, FluentbitAgentSchedulesOn(spec, controlPlane))
+}
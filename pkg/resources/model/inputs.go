@@ -0,0 +1,192 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/common"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// InputLabelKey is the kubernetes label added to the records of the extra fluentd inputs, flows can select on it
+const InputLabelKey = "logging.kube-logging.io/input"
+
+const forwardInputPort = 24240
+
+// registerExtraInputs adds the sources of the extra fluentd inputs with a label each, that attributes the records
+// to a namespace and passes them back to the label router
+func registerExtraInputs(builder *types.SystemBuilder, logging v1beta1.Logging, secrets SecretLoaderFactory) error {
+	if logging.Spec.FluentdSpec == nil {
+		return nil
+	}
+	spec := logging.Spec.FluentdSpec
+	if err := validateExtraInputs(spec.ExtraInputs); err != nil {
+		return err
+	}
+	for _, in := range spec.ExtraInputs {
+		source, err := extraInputSource(in, spec.TLS.Enabled, secrets.OutputSecretLoaderForNamespace(logging.Spec.ControlNamespace))
+		if err != nil {
+			return errors.WrapIff(err, "creating extra input %s", in.Name)
+		}
+		builder.RegisterInput(source)
+
+		namespace := in.Namespace
+		if namespace == "" {
+			namespace = logging.Spec.ControlNamespace
+		}
+		flow, err := extraInputFlow(in.Name, namespace)
+		if err != nil {
+			return errors.WrapIff(err, "creating label of extra input %s", in.Name)
+		}
+		if err := builder.RegisterFlow(flow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateExtraInputs(inputs []v1beta1.FluentdInput) error {
+	var errs error
+	names := make(map[string]struct{}, len(inputs))
+	ports := map[string]string{fmt.Sprintf("TCP/%d", forwardInputPort): "forward"}
+	for _, in := range inputs {
+		if _, ok := names[in.Name]; ok {
+			errs = errors.Append(errs, errors.Errorf("duplicate extra input name %s", in.Name))
+		}
+		names[in.Name] = struct{}{}
+
+		var kinds []string
+		if in.HTTP != nil {
+			kinds = append(kinds, "http")
+		}
+		if in.Syslog != nil {
+			kinds = append(kinds, "syslog")
+		}
+		if in.TCP != nil {
+			kinds = append(kinds, "tcp")
+		}
+		if in.UDP != nil {
+			kinds = append(kinds, "udp")
+		}
+		if len(kinds) != 1 {
+			errs = errors.Append(errs, errors.Errorf("extra input %s must have exactly one input type, got %v", in.Name, kinds))
+		}
+		if in.TLS && in.UDP != nil {
+			errs = errors.Append(errs, errors.Errorf("extra input %s: TLS is not supported over UDP", in.Name))
+		}
+
+		port := fmt.Sprintf("%s/%d", in.Protocol(), in.Port)
+		if other, ok := ports[port]; ok {
+			errs = errors.Append(errs, errors.Errorf("extra input %s listens on the same port as %s: %s", in.Name, other, port))
+		}
+		ports[port] = in.Name
+	}
+	return errs
+}
+
+func extraInputLabel(name string) string {
+	return "@input-" + name
+}
+
+func extraInputSource(in v1beta1.FluentdInput, tlsEnabled bool, secretLoader secret.SecretLoader) (types.Input, error) {
+	in = *in.DeepCopy()
+	if in.TLS && !tlsEnabled {
+		return nil, errors.New("TLS is requested, but fluentd TLS is not enabled")
+	}
+
+	var transport **common.Transport
+	var source types.Directive
+	var err error
+	id := "input_" + in.Name
+	switch {
+	case in.HTTP != nil:
+		transport = &in.HTTP.Transport
+	case in.Syslog != nil:
+		transport = &in.Syslog.Transport
+	case in.TCP != nil:
+		transport = &in.TCP.Transport
+	}
+	if in.TLS && transport != nil && *transport == nil {
+		*transport = &common.Transport{
+			Version:        "TLSv1_2",
+			CaPath:         "/fluentd/tls/ca.crt",
+			CertPath:       "/fluentd/tls/tls.crt",
+			PrivateKeyPath: "/fluentd/tls/tls.key",
+		}
+	}
+
+	switch {
+	case in.HTTP != nil:
+		source, err = in.HTTP.ToDirective(secretLoader, id)
+	case in.Syslog != nil:
+		source, err = in.Syslog.ToDirective(secretLoader, id)
+	case in.TCP != nil:
+		source, err = in.TCP.ToDirective(secretLoader, id)
+	case in.UDP != nil:
+		source, err = in.UDP.ToDirective(secretLoader, id)
+	default:
+		return nil, errors.New("no input type specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source.GetPluginMeta().Label = extraInputLabel(in.Name)
+	params := source.GetParams()
+	params["port"] = strconv.Itoa(int(in.Port))
+	if in.HTTP == nil {
+		params["tag"] = "input." + in.Name
+	}
+	return source, nil
+}
+
+// extraInputFlow attributes the records to the namespace the same way the kubernetes metadata of the log forwarder does,
+// so the label router can route them to the flows of the namespace
+func extraInputFlow(name, namespace string) (*types.Flow, error) {
+	label := extraInputLabel(name)
+	flow := &types.Flow{
+		PluginMeta: types.PluginMeta{
+			Directive: "label",
+			Tag:       label,
+		},
+		FlowLabel: label,
+	}
+	metadata, err := (&filter.RecordTransformer{
+		EnableRuby:   true,
+		AutoTypecast: true,
+		Records: []filter.Record{
+			{
+				"kubernetes": fmt.Sprintf(`${{"namespace_name" => %q, "container_name" => %q, "labels" => {%q => %q}}}`, namespace, name, InputLabelKey, name),
+			},
+		},
+	}).ToDirective(nil, "input_"+name+"_metadata")
+	if err != nil {
+		return nil, err
+	}
+	relabel, err := (&output.RelabelOutputConfig{
+		Label: "@ROOT",
+	}).ToDirective(nil, "input_"+name+"_relabel")
+	if err != nil {
+		return nil, err
+	}
+	return flow.WithFilters(metadata).WithOutputs(relabel), nil
+}
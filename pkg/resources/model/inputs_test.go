@@ -0,0 +1,218 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/input"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/render"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+type testSecretLoaderFactory struct{}
+
+func (testSecretLoaderFactory) OutputSecretLoaderForNamespace(string) secret.SecretLoader {
+	return nil
+}
+
+func TestExtraInputs(t *testing.T) {
+	forward, err := input.NewForwardInputConfig().ToDirective(nil, "main")
+	require.NoError(t, err)
+	builder := types.NewSystemBuilder(forward, nil, types.NewRouter("main", nil))
+
+	logging := v1beta1.Logging{
+		Spec: v1beta1.LoggingSpec{
+			ControlNamespace: "logging",
+			FluentdSpec: &v1beta1.FluentdSpec{
+				TLS: v1beta1.FluentdTLS{Enabled: true},
+				ExtraInputs: []v1beta1.FluentdInput{
+					{
+						Name:      "webhooks",
+						Port:      9880,
+						Namespace: "apps",
+						TLS:       true,
+						HTTP:      &input.HTTPInputConfig{},
+					},
+					{
+						Name:   "appliances",
+						Port:   5140,
+						Syslog: &input.SyslogInputConfig{MessageFormat: "rfc5424"},
+					},
+					{
+						Name: "legacy",
+						Port: 5170,
+						TCP: &input.TCPInputConfig{
+							Parse: &filter.ParseSection{Type: "json"},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, registerExtraInputs(builder, logging, testSecretLoaderFactory{}))
+	system, err := builder.Build()
+	require.NoError(t, err)
+
+	b := &bytes.Buffer{}
+	renderer := render.FluentRender{Out: b, Indent: 2}
+	require.NoError(t, renderer.Render(system))
+
+	expected := `
+		<source>
+		  @type forward
+		  @id main_forward
+		  bind 0.0.0.0
+		  port 24240
+		</source>
+		<source>
+		  @type http
+		  @id input_webhooks_http
+		  @label @input-webhooks
+		  bind 0.0.0.0
+		  port 9880
+		  <transport tls>
+		    ca_path /fluentd/tls/ca.crt
+		    cert_path /fluentd/tls/tls.crt
+		    private_key_path /fluentd/tls/tls.key
+		    version TLSv1_2
+		  </transport>
+		</source>
+		<source>
+		  @type syslog
+		  @id input_appliances_syslog
+		  @label @input-appliances
+		  bind 0.0.0.0
+		  message_format rfc5424
+		  port 5140
+		  tag input.appliances
+		</source>
+		<source>
+		  @type tcp
+		  @id input_legacy_tcp
+		  @label @input-legacy
+		  bind 0.0.0.0
+		  port 5170
+		  tag input.legacy
+		  <parse>
+		    @type json
+		  </parse>
+		</source>
+		<match **>
+		  @type label_router
+		  @id main
+		</match>
+		<label @input-webhooks>
+		  <filter **>
+		    @type record_transformer
+		    @id input_webhooks_metadata
+		    auto_typecast true
+		    enable_ruby true
+		    <record>
+		      kubernetes ${{"namespace_name" => "apps", "container_name" => "webhooks", "labels" => {"logging.kube-logging.io/input" => "webhooks"}}}
+		    </record>
+		  </filter>
+		  <match **>
+		    @type relabel
+		    @id input_webhooks_relabel
+		    @label @ROOT
+		  </match>
+		</label>
+		<label @input-appliances>
+		  <filter **>
+		    @type record_transformer
+		    @id input_appliances_metadata
+		    auto_typecast true
+		    enable_ruby true
+		    <record>
+		      kubernetes ${{"namespace_name" => "logging", "container_name" => "appliances", "labels" => {"logging.kube-logging.io/input" => "appliances"}}}
+		    </record>
+		  </filter>
+		  <match **>
+		    @type relabel
+		    @id input_appliances_relabel
+		    @label @ROOT
+		  </match>
+		</label>
+		<label @input-legacy>
+		  <filter **>
+		    @type record_transformer
+		    @id input_legacy_metadata
+		    auto_typecast true
+		    enable_ruby true
+		    <record>
+		      kubernetes ${{"namespace_name" => "logging", "container_name" => "legacy", "labels" => {"logging.kube-logging.io/input" => "legacy"}}}
+		    </record>
+		  </filter>
+		  <match **>
+		    @type relabel
+		    @id input_legacy_relabel
+		    @label @ROOT
+		  </match>
+		</label>`
+	if a, e := diff.TrimLinesInString(b.String()), diff.TrimLinesInString(expected); a != e {
+		t.Errorf("Result does not match (-actual vs +expected):\n%v\nActual: %s", diff.LineDiff(a, e), b.String())
+	}
+}
+
+func TestExtraInputsInvalid(t *testing.T) {
+	testCases := map[string][]v1beta1.FluentdInput{
+		"no input type": {
+			{Name: "a", Port: 5140},
+		},
+		"multiple input types": {
+			{Name: "a", Port: 5140, TCP: &input.TCPInputConfig{}, UDP: &input.UDPInputConfig{}},
+		},
+		"duplicate names": {
+			{Name: "a", Port: 5140, TCP: &input.TCPInputConfig{}},
+			{Name: "a", Port: 5141, TCP: &input.TCPInputConfig{}},
+		},
+		"forward port": {
+			{Name: "a", Port: 24240, HTTP: &input.HTTPInputConfig{}},
+		},
+		"tls without fluentd tls": {
+			{Name: "a", Port: 5140, TLS: true, TCP: &input.TCPInputConfig{}},
+		},
+	}
+	for name, inputs := range testCases {
+		inputs := inputs
+		t.Run(name, func(t *testing.T) {
+			logging := v1beta1.Logging{
+				Spec: v1beta1.LoggingSpec{
+					FluentdSpec: &v1beta1.FluentdSpec{ExtraInputs: inputs},
+				},
+			}
+			builder := types.NewSystemBuilder(nil, nil, types.NewRouter("main", nil))
+			require.Error(t, registerExtraInputs(builder, logging, testSecretLoaderFactory{}))
+		})
+	}
+	// UDP and TCP inputs may share the same port number
+	logging := v1beta1.Logging{
+		Spec: v1beta1.LoggingSpec{
+			FluentdSpec: &v1beta1.FluentdSpec{ExtraInputs: []v1beta1.FluentdInput{
+				{Name: "a", Port: 5140, UDP: &input.UDPInputConfig{}},
+				{Name: "b", Port: 5140, TCP: &input.TCPInputConfig{}},
+			}},
+		},
+	}
+	require.NoError(t, registerExtraInputs(types.NewSystemBuilder(nil, nil, types.NewRouter("main", nil)), logging, testSecretLoaderFactory{}))
+}
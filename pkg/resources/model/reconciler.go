@@ -19,11 +19,15 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/secret"
 	"github.com/cisco-open/operator-tools/pkg/utils"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -36,6 +40,7 @@ func NewValidationReconciler(
 	repo client.StatusClient,
 	resources LoggingResources,
 	secrets SecretLoaderFactory,
+	renderErrors FlowRenderErrors,
 	logger logr.Logger,
 ) func(ctx context.Context) (*reconcile.Result, error) {
 	return func(ctx context.Context) (*reconcile.Result, error) {
@@ -53,13 +58,29 @@ func NewValidationReconciler(
 
 			output.Status.Active = utils.BoolPointer(false)
 			output.Status.Problems = nil
+			output.Status.ReferencedBy = nil
 
 			if output.Name == resources.Logging.Spec.ErrorOutputRef {
 				output.Status.Active = utils.BoolPointer(true)
+				output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Logging", "", resources.Logging.Name)
+			}
+			if defaultFlow := resources.Logging.Spec.DefaultFlowSpec; defaultFlow != nil && resources.Logging.Spec.FluentdSpec != nil {
+				for _, ref := range defaultFlow.GlobalOutputRefs {
+					if output.Name == ref {
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Logging", "", resources.Logging.Name)
+					}
+				}
 			}
 
-			output.Status.Problems = append(output.Status.Problems,
-				validateOutputSpec(output.Spec.OutputSpec, secrets.OutputSecretLoaderForNamespace(output.Namespace))...)
+			problems, secretProblems := validateOutputSpec(output.Spec.OutputSpec, secrets.OutputSecretLoaderForNamespace(output.Namespace))
+			output.Status.Problems = append(output.Status.Problems, problems...)
+			setSecretsCondition(&output.Status.Conditions, output.Generation, secretProblems)
+			if ref := output.Spec.SecondaryOutputRef; ref != "" {
+				if _, _, err := resolveSecondaryOutput(ref, output.Namespace, nil, resources.Fluentd.ClusterOutputs); err != nil {
+					output.Status.Problems = append(output.Status.Problems, err.Error())
+				}
+			}
 			output.Status.ProblemsCount = len(output.Status.Problems)
 		}
 
@@ -69,9 +90,21 @@ func NewValidationReconciler(
 
 			output.Status.Active = utils.BoolPointer(false)
 			output.Status.Problems = nil
-
-			output.Status.Problems = append(output.Status.Problems,
-				validateOutputSpec(output.Spec, secrets.OutputSecretLoaderForNamespace(output.Namespace))...)
+			output.Status.ReferencedBy = nil
+
+			problems, secretProblems := validateOutputSpec(output.Spec, secrets.OutputSecretLoaderForNamespace(output.Namespace))
+			output.Status.Problems = append(output.Status.Problems, problems...)
+			setSecretsCondition(&output.Status.Conditions, output.Generation, secretProblems)
+			if ref := output.Spec.SecondaryOutputRef; ref != "" {
+				if _, _, err := resolveSecondaryOutput(ref, output.Namespace, resources.Fluentd.Outputs, resources.Fluentd.ClusterOutputs); err != nil {
+					output.Status.Problems = append(output.Status.Problems, err.Error())
+				}
+			}
+			if resources.Logging.Spec.StrictTenancy {
+				for _, violation := range OutputTenancyViolations(*output) {
+					output.Status.Problems = append(output.Status.Problems, fmt.Sprintf("strict tenancy: %s", violation))
+				}
+			}
 			output.Status.ProblemsCount = len(output.Status.Problems)
 		}
 
@@ -81,13 +114,30 @@ func NewValidationReconciler(
 
 			output.Status.Active = utils.BoolPointer(false)
 			output.Status.Problems = nil
+			output.Status.ReferencedBy = nil
 
 			if output.Name == resources.Logging.Spec.ErrorOutputRef {
 				output.Status.Active = utils.BoolPointer(true)
+				output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Logging", "", resources.Logging.Name)
+			}
+			if syslogNGSpec := resources.Logging.Spec.SyslogNGSpec; syslogNGSpec != nil {
+				if output.Name == syslogNGSpec.ParseErrorOutputRef {
+					output.Status.Active = utils.BoolPointer(true)
+					output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Logging", "", resources.Logging.Name)
+				}
+				if syslogNGSpec.DefaultFlow != nil {
+					for _, ref := range syslogNGSpec.DefaultFlow.GlobalOutputRefs {
+						if output.Name == ref {
+							output.Status.Active = utils.BoolPointer(true)
+							output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Logging", "", resources.Logging.Name)
+						}
+					}
+				}
 			}
 
-			output.Status.Problems = append(output.Status.Problems,
-				validateOutputSpec(output.Spec.SyslogNGOutputSpec, secrets.OutputSecretLoaderForNamespace(output.Namespace))...)
+			problems, secretProblems := validateOutputSpec(output.Spec.SyslogNGOutputSpec, secrets.OutputSecretLoaderForNamespace(output.Namespace))
+			output.Status.Problems = append(output.Status.Problems, problems...)
+			setSecretsCondition(&output.Status.Conditions, output.Generation, secretProblems)
 			output.Status.ProblemsCount = len(output.Status.Problems)
 		}
 
@@ -97,9 +147,16 @@ func NewValidationReconciler(
 
 			output.Status.Active = utils.BoolPointer(false)
 			output.Status.Problems = nil
-
-			output.Status.Problems = append(output.Status.Problems,
-				validateOutputSpec(output.Spec, secrets.OutputSecretLoaderForNamespace(output.Namespace))...)
+			output.Status.ReferencedBy = nil
+
+			problems, secretProblems := validateOutputSpec(output.Spec, secrets.OutputSecretLoaderForNamespace(output.Namespace))
+			output.Status.Problems = append(output.Status.Problems, problems...)
+			setSecretsCondition(&output.Status.Conditions, output.Generation, secretProblems)
+			if resources.Logging.Spec.StrictTenancy {
+				for _, violation := range renderErrors.TenancyViolations(output) {
+					output.Status.Problems = append(output.Status.Problems, fmt.Sprintf("strict tenancy: %s", violation))
+				}
+			}
 			output.Status.ProblemsCount = len(output.Status.Problems)
 		}
 
@@ -114,10 +171,18 @@ func NewValidationReconciler(
 				flow.Status.Problems = append(flow.Status.Problems, "\"outputRefs\" field is deprecated, use \"globalOutputRefs\" instead")
 			}
 
+			skipped := false
+			if rendered, renderErr := renderErrors.Lookup(flow); rendered {
+				skipped = setRenderCondition(&flow.Status.Conditions, flow.Generation, renderErr, resources.Logging.Spec.SkipInvalidResources)
+			}
+
 			for _, ref := range flow.Spec.GlobalOutputRefs {
 				if output := resources.Fluentd.ClusterOutputs.FindByName(ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "ClusterFlow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling global output reference: %s", ref))
 				}
@@ -136,10 +201,23 @@ func NewValidationReconciler(
 				flow.Status.Problems = append(flow.Status.Problems, "\"outputRefs\" field is deprecated, use \"globalOutputRefs\" and \"localOutputRefs\" instead")
 			}
 
+			if resources.Logging.Spec.StrictTenancy {
+				for _, violation := range append(FlowTenancyViolations(*flow, resources.Fluentd), renderErrors.TenancyViolations(flow)...) {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("strict tenancy: %s", violation))
+				}
+			}
+			skipped := false
+			if rendered, renderErr := renderErrors.Lookup(flow); rendered {
+				skipped = setRenderCondition(&flow.Status.Conditions, flow.Generation, renderErr, resources.Logging.Spec.SkipInvalidResources)
+			}
+
 			for _, ref := range flow.Spec.GlobalOutputRefs {
 				if output := resources.Fluentd.ClusterOutputs.FindByName(ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Flow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling global output reference: %s", ref))
 				}
@@ -147,12 +225,29 @@ func NewValidationReconciler(
 
 			for _, ref := range flow.Spec.LocalOutputRefs {
 				if output := resources.Fluentd.Outputs.FindByNamespacedName(flow.Namespace, ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Flow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling local output reference: %s", ref))
 				}
 			}
+
+			for _, ref := range flow.Spec.SharedOutputRefs {
+				if !loggingv1beta1.OutputGrantAllows(resources.OutputGrants, loggingv1beta1.OutputGrantKindFlow, flow.Namespace, loggingv1beta1.OutputGrantKindOutput, ref) {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("shared output reference not granted: %s/%s", ref.Namespace, ref.Name))
+				} else if output := resources.Fluentd.Outputs.FindByNamespacedName(ref.Namespace, ref.Name); output != nil {
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Flow", flow.Namespace, flow.Name)
+					}
+				} else {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling shared output reference: %s/%s", ref.Namespace, ref.Name))
+				}
+			}
 			flow.Status.ProblemsCount = len(flow.Status.Problems)
 		}
 
@@ -163,10 +258,18 @@ func NewValidationReconciler(
 			flow.Status.Active = utils.BoolPointer(false)
 			flow.Status.Problems = nil
 
+			skipped := false
+			if rendered, renderErr := renderErrors.Lookup(flow); rendered {
+				skipped = setRenderCondition(&flow.Status.Conditions, flow.Generation, renderErr, resources.Logging.Spec.SkipInvalidResources)
+			}
+
 			for _, ref := range flow.Spec.GlobalOutputRefs {
 				if output := resources.SyslogNG.ClusterOutputs.FindByName(ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "SyslogNGClusterFlow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling global output reference: %s", ref))
 				}
@@ -181,10 +284,23 @@ func NewValidationReconciler(
 			flow.Status.Active = utils.BoolPointer(false)
 			flow.Status.Problems = nil
 
+			if resources.Logging.Spec.StrictTenancy {
+				for _, violation := range append(SyslogNGFlowTenancyViolations(*flow, resources.SyslogNG), renderErrors.TenancyViolations(flow)...) {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("strict tenancy: %s", violation))
+				}
+			}
+			skipped := false
+			if rendered, renderErr := renderErrors.Lookup(flow); rendered {
+				skipped = setRenderCondition(&flow.Status.Conditions, flow.Generation, renderErr, resources.Logging.Spec.SkipInvalidResources)
+			}
+
 			for _, ref := range flow.Spec.GlobalOutputRefs {
 				if output := resources.SyslogNG.ClusterOutputs.FindByName(ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "SyslogNGFlow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling global output reference: %s", ref))
 				}
@@ -192,18 +308,90 @@ func NewValidationReconciler(
 
 			for _, ref := range flow.Spec.LocalOutputRefs {
 				if output := resources.SyslogNG.Outputs.FindByNamespacedName(flow.Namespace, ref); output != nil {
-					flow.Status.Active = utils.BoolPointer(true)
-					output.Status.Active = utils.BoolPointer(true)
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "SyslogNGFlow", flow.Namespace, flow.Name)
+					}
 				} else {
 					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling local output reference: %s", ref))
 				}
 			}
+
+			for _, ref := range flow.Spec.SharedOutputRefs {
+				if !loggingv1beta1.OutputGrantAllows(resources.OutputGrants, loggingv1beta1.OutputGrantKindSyslogNGFlow, flow.Namespace, loggingv1beta1.OutputGrantKindSyslogNGOutput, ref) {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("shared output reference not granted: %s/%s", ref.Namespace, ref.Name))
+				} else if output := resources.SyslogNG.Outputs.FindByNamespacedName(ref.Namespace, ref.Name); output != nil {
+					if !skipped {
+						flow.Status.Active = utils.BoolPointer(true)
+						output.Status.Active = utils.BoolPointer(true)
+						output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "SyslogNGFlow", flow.Namespace, flow.Name)
+					}
+				} else {
+					flow.Status.Problems = append(flow.Status.Problems, fmt.Sprintf("dangling shared output reference: %s/%s", ref.Namespace, ref.Name))
+				}
+			}
 			flow.Status.ProblemsCount = len(flow.Status.Problems)
 		}
 
+		activateSecondaryOutputs(resources.Fluentd)
+
+		now := time.Now()
+		for i := range resources.Fluentd.FlowTaps {
+			tap := &resources.Fluentd.FlowTaps[i]
+			registerForPatching(tap)
+
+			tap.Status.Active = utils.BoolPointer(false)
+			tap.Status.Problems = nil
+			tap.Status.ExpiresAt = tap.ExpiresAt()
+
+			if !flowTapTargetExists(*tap, resources.Fluentd) {
+				tap.Status.Problems = append(tap.Status.Problems, fmt.Sprintf("dangling flow reference: %s %s", tap.Spec.FlowRef.Kind, tap.Spec.FlowRef.Name))
+			}
+			if _, err := FlowForFlowTap(*tap, resources.Fluentd, secrets); err != nil {
+				tap.Status.Problems = append(tap.Status.Problems, err.Error())
+			}
+			if tap.Expired(now) {
+				tap.Status.Problems = append(tap.Status.Problems, "expired")
+			}
+			tap.Status.Active = utils.BoolPointer(len(tap.Status.Problems) == 0)
+			if ref := tap.Spec.LocalOutputRef; ref != "" && utils.PointerToBool(tap.Status.Active) {
+				if output := resources.Fluentd.Outputs.FindByNamespacedName(tap.Namespace, ref); output != nil {
+					output.Status.Active = utils.BoolPointer(true)
+					output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "FlowTap", tap.Namespace, tap.Name)
+				}
+			}
+			tap.Status.ProblemsCount = len(tap.Status.Problems)
+		}
+
+		var resolvedFluentbits []loggingv1beta1.FluentbitAgent
+		for i := range resources.Fluentbits {
+			agent := &resources.Fluentbits[i]
+			registerForPatching(agent)
+
+			agent.Status.Problems = nil
+			spec, err := InheritFluentbitBase(*agent, resources.Fluentbits)
+			if err != nil {
+				agent.Status.Problems = append(agent.Status.Problems, err.Error())
+				continue
+			}
+			resolved := *agent
+			resolved.Spec = spec
+			resolvedFluentbits = append(resolvedFluentbits, resolved)
+		}
+		overlaps := FluentbitAgentOverlaps(resolvedFluentbits, resources.Nodes)
+		for i := range resources.Fluentbits {
+			agent := &resources.Fluentbits[i]
+			agent.Status.Problems = append(agent.Status.Problems, overlaps[agent.Name]...)
+			agent.Status.ProblemsCount = len(agent.Status.Problems)
+		}
+
 		registerForPatching(&resources.Logging)
 
 		resources.Logging.Status.Problems = nil
+		if _, err := renderErrors.Lookup(&resources.Logging); err != nil {
+			resources.Logging.Status.Problems = append(resources.Logging.Status.Problems, fmt.Sprintf("invalid configuration: %s", err))
+		}
 
 		loggingsForTheSameRef := make([]string, 0)
 		for _, l := range resources.AllLoggings {
@@ -222,6 +410,19 @@ func NewValidationReconciler(
 			resources.Logging.Status.Problems = append(resources.Logging.Status.Problems, problem)
 		}
 
+		if syslogNGSpec := resources.Logging.Spec.SyslogNGSpec; syslogNGSpec != nil {
+			if ref := syslogNGSpec.ParseErrorOutputRef; ref != "" && resources.SyslogNG.ClusterOutputs.FindByName(ref) == nil {
+				resources.Logging.Status.Problems = append(resources.Logging.Status.Problems, fmt.Sprintf("dangling syslog-ng parse error output reference: %s", ref))
+			}
+			if syslogNGSpec.DefaultFlow != nil {
+				for _, ref := range syslogNGSpec.DefaultFlow.GlobalOutputRefs {
+					if resources.SyslogNG.ClusterOutputs.FindByName(ref) == nil {
+						resources.Logging.Status.Problems = append(resources.Logging.Status.Problems, fmt.Sprintf("dangling syslog-ng default flow output reference: %s", ref))
+					}
+				}
+			}
+		}
+
 		if len(resources.Logging.Spec.NodeAgents) > 0 || len(resources.NodeAgents) > 0 {
 			// load agents from standalone NodeAgent resources and additionally with inline nodeAgents from the logging resource
 			// for compatibility reasons
@@ -241,6 +442,8 @@ func NewValidationReconciler(
 			}
 		}
 
+		resources.Logging.Status.ProblemsCount = len(resources.Logging.Status.Problems)
+
 		var errs error
 		for _, req := range patchRequests {
 			if req.IsEmptyPatch() {
@@ -256,15 +459,17 @@ func NewValidationReconciler(
 	}
 }
 
-func validateOutputSpec(spec interface{}, secrets secret.SecretLoader) (problems []string) {
+// validateOutputSpec returns the problems of the output, the ones caused by unresolvable secrets are returned separately
+func validateOutputSpec(spec interface{}, secrets secret.SecretLoader) (problems []string, secretProblems []string) {
 	var configuredFields []string
 	it := mirror.StructRange(spec)
 	for it.Next() {
 		if it.Field().Type.Kind() == reflect.Ptr && !it.Value().IsNil() {
 			configuredFields = append(configuredFields, jsonFieldName(it.Field()))
-			problems = append(problems, checkSecrets(it.Value().Elem(), secrets)...)
+			secretProblems = append(secretProblems, checkSecrets(it.Value().Elem(), secrets)...)
 		}
 	}
+	problems = append(problems, secretProblems...)
 
 	switch len(configuredFields) {
 	case 0:
@@ -298,6 +503,128 @@ func checkSecrets(v reflect.Value, secrets secret.SecretLoader) (problems []stri
 	return
 }
 
+// setSecretsCondition records whether the secrets referenced by an output can be loaded
+func setSecretsCondition(conditions *[]metav1.Condition, generation int64, secretProblems []string) {
+	condition := metav1.Condition{
+		Type:               loggingv1beta1.OutputConditionSecretsResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             loggingv1beta1.OutputReasonSecretsLoaded,
+	}
+	if len(secretProblems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = loggingv1beta1.OutputReasonSecretResolutionFailed
+		condition.Message = strings.Join(secretProblems, "; ")
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// setRenderCondition records the render error of a flow and whether it is skipped because of it.
+// Returns true if the flow is left out of the config.
+func setRenderCondition(conditions *[]metav1.Condition, generation int64, renderErr error, skipInvalidResources bool) bool {
+	condition := metav1.Condition{
+		Type:               loggingv1beta1.FlowConditionSkipped,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             loggingv1beta1.FlowReasonRendered,
+	}
+	if renderErr != nil {
+		condition.Reason = loggingv1beta1.FlowReasonRenderFailed
+		condition.Message = renderErr.Error()
+		if skipInvalidResources {
+			condition.Status = metav1.ConditionTrue
+		}
+	}
+	meta.SetStatusCondition(conditions, condition)
+	return condition.Status == metav1.ConditionTrue
+}
+
+// FlowRenderErrors collects the render results of the flows by kind and namespaced name, so that the validation
+// reconciler reports the errors of the actual render instead of rendering the flows again.
+// The errors of the whole configuration are recorded for the logging.
+type FlowRenderErrors map[flowRenderKey]*flowRenderResult
+
+type flowRenderKey struct {
+	kind string
+	types.NamespacedName
+}
+
+type flowRenderResult struct {
+	rendered          bool
+	err               error
+	tenancyViolations []string
+}
+
+func flowRenderKeyOf(flow client.Object) flowRenderKey {
+	return flowRenderKey{
+		kind:           reflect.TypeOf(flow).Elem().Name(),
+		NamespacedName: client.ObjectKeyFromObject(flow),
+	}
+}
+
+func (e FlowRenderErrors) resultOf(flow client.Object) *flowRenderResult {
+	key := flowRenderKeyOf(flow)
+	result := e[key]
+	if result == nil {
+		result = &flowRenderResult{}
+		e[key] = result
+	}
+	return result
+}
+
+// Record stores the render result of a flow, a nil error means that the flow is part of the config
+func (e FlowRenderErrors) Record(flow client.Object, err error) {
+	if e != nil {
+		result := e.resultOf(flow)
+		result.rendered = true
+		result.err = err
+	}
+}
+
+// Lookup returns whether the flow has been rendered and the error of the render
+func (e FlowRenderErrors) Lookup(flow client.Object) (bool, error) {
+	if result := e[flowRenderKeyOf(flow)]; result != nil {
+		return result.rendered, result.err
+	}
+	return false, nil
+}
+
+// RecordTenancyViolation stores a strict tenancy violation found while rendering a resource, duplicates are dropped
+func (e FlowRenderErrors) RecordTenancyViolation(resource client.Object, violation string) {
+	if e == nil {
+		return
+	}
+	result := e.resultOf(resource)
+	for _, v := range result.tenancyViolations {
+		if v == violation {
+			return
+		}
+	}
+	result.tenancyViolations = append(result.tenancyViolations, violation)
+}
+
+// TenancyViolations returns the strict tenancy violations found while rendering a resource
+func (e FlowRenderErrors) TenancyViolations(resource client.Object) []string {
+	if result := e[flowRenderKeyOf(resource)]; result != nil {
+		return result.tenancyViolations
+	}
+	return nil
+}
+
+// appendReference adds a resource to the referencedBy list of an output, in Kind/namespace/name form
+func appendReference(references []string, kind, namespace, name string) []string {
+	reference := kind + "/" + name
+	if namespace != "" {
+		reference = fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	}
+	for _, r := range references {
+		if r == reference {
+			return references
+		}
+	}
+	return append(references, reference)
+}
+
 type patchRequest struct {
 	Obj   client.Object
 	Patch client.Patch
@@ -0,0 +1,105 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestSetRenderCondition(t *testing.T) {
+	var conditions []metav1.Condition
+
+	require.False(t, setRenderCondition(&conditions, 1, nil, true))
+	condition := meta.FindStatusCondition(conditions, v1beta1.FlowConditionSkipped)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, v1beta1.FlowReasonRendered, condition.Reason)
+
+	require.False(t, setRenderCondition(&conditions, 2, errors.New("referenced output not found: es"), false))
+	condition = meta.FindStatusCondition(conditions, v1beta1.FlowConditionSkipped)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, v1beta1.FlowReasonRenderFailed, condition.Reason)
+	require.Equal(t, "referenced output not found: es", condition.Message)
+	require.Equal(t, int64(2), condition.ObservedGeneration)
+
+	require.True(t, setRenderCondition(&conditions, 2, errors.New("referenced output not found: es"), true))
+	condition = meta.FindStatusCondition(conditions, v1beta1.FlowConditionSkipped)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Len(t, conditions, 1)
+}
+
+func TestFlowRenderErrors(t *testing.T) {
+	renderErrors := FlowRenderErrors{}
+	flow := &v1beta1.Flow{ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "flow"}}
+	clusterFlow := &v1beta1.ClusterFlow{ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "flow"}}
+	syslogNGFlow := &v1beta1.SyslogNGFlow{ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "flow"}}
+
+	renderErrors.Record(flow, nil)
+	renderErrors.Record(clusterFlow, errors.New("referenced clusteroutput not found: es"))
+
+	rendered, err := renderErrors.Lookup(flow)
+	require.True(t, rendered)
+	require.NoError(t, err)
+
+	rendered, err = renderErrors.Lookup(clusterFlow)
+	require.True(t, rendered)
+	require.EqualError(t, err, "referenced clusteroutput not found: es")
+
+	// the flows of the other kinds with the same name are independent
+	rendered, err = renderErrors.Lookup(syslogNGFlow)
+	require.False(t, rendered)
+	require.NoError(t, err)
+
+	// the errors of the whole configuration are recorded for the logging
+	logging := &v1beta1.Logging{ObjectMeta: metav1.ObjectMeta{Name: "flow"}}
+	renderErrors.Record(logging, errors.New("duplicate @id es"))
+	_, err = renderErrors.Lookup(logging)
+	require.EqualError(t, err, "duplicate @id es")
+
+	// recording without a collector is a no-op
+	FlowRenderErrors(nil).Record(flow, nil)
+}
+
+func TestSetSecretsCondition(t *testing.T) {
+	var conditions []metav1.Condition
+
+	setSecretsCondition(&conditions, 1, []string{"secret a not found", "secret b not found"})
+	condition := meta.FindStatusCondition(conditions, v1beta1.OutputConditionSecretsResolved)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, v1beta1.OutputReasonSecretResolutionFailed, condition.Reason)
+	require.Equal(t, "secret a not found; secret b not found", condition.Message)
+
+	setSecretsCondition(&conditions, 1, nil)
+	condition = meta.FindStatusCondition(conditions, v1beta1.OutputConditionSecretsResolved)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, v1beta1.OutputReasonSecretsLoaded, condition.Reason)
+	require.Empty(t, condition.Message)
+}
+
+func TestAppendReference(t *testing.T) {
+	var references []string
+	references = appendReference(references, "Flow", "apps", "web")
+	references = appendReference(references, "Logging", "", "all")
+	references = appendReference(references, "Flow", "apps", "web")
+	require.Equal(t, []string{"Flow/apps/web", "Logging/all"}, references)
+}
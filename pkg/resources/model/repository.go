@@ -69,6 +69,14 @@ func (r LoggingResourceRepository) LoggingResourcesFor(ctx context.Context, logg
 	res.Fluentbits, err = r.FluentbitsFor(ctx, logging)
 	errs = errors.Append(errs, err)
 
+	if len(res.Fluentbits) > 1 {
+		res.Nodes, err = r.Nodes(ctx)
+		errs = errors.Append(errs, err)
+	}
+
+	res.Fluentd.FlowTaps, err = r.FlowTapsFor(ctx, logging)
+	errs = errors.Append(errs, err)
+
 	uniqueWatchNamespaces, err := r.UniqueWatchNamespaces(ctx, &logging)
 	if err != nil {
 		errs = errors.Append(errs, err)
@@ -101,6 +109,62 @@ func (r LoggingResourceRepository) LoggingResourcesFor(ctx context.Context, logg
 		}
 	}
 
+	// grants are only relevant in the namespaces of the shared outputs the flows reference
+	for _, ns := range sharedOutputNamespaces(res.Fluentd.Flows, res.SyslogNG.Flows) {
+		grants, err := r.OutputGrantsInNamespace(ctx, ns)
+		res.OutputGrants = append(res.OutputGrants, grants...)
+		errs = errors.Append(errs, err)
+	}
+
+	// outputs shared through an OutputGrant can live outside of the watched namespaces
+	for _, ns := range grantNamespacesOutside(res.OutputGrants, uniqueWatchNamespaces) {
+		{
+			outputs, err := r.OutputsInNamespaceFor(ctx, ns, logging)
+			res.Fluentd.Outputs = append(res.Fluentd.Outputs, outputs...)
+			errs = errors.Append(errs, err)
+		}
+
+		{
+			outputs, err := r.SyslogNGOutputsInNamespaceFor(ctx, ns, logging)
+			res.SyslogNG.Outputs = append(res.SyslogNG.Outputs, outputs...)
+			errs = errors.Append(errs, err)
+		}
+	}
+
+	return
+}
+
+func sharedOutputNamespaces(flows []v1beta1.Flow, syslogNGFlows []v1beta1.SyslogNGFlow) (res []string) {
+	seen := make(map[string]bool)
+	add := func(refs []v1beta1.OutputReference) {
+		for _, ref := range refs {
+			if !seen[ref.Namespace] {
+				seen[ref.Namespace] = true
+				res = append(res, ref.Namespace)
+			}
+		}
+	}
+	for _, f := range flows {
+		add(f.Spec.SharedOutputRefs)
+	}
+	for _, f := range syslogNGFlows {
+		add(f.Spec.SharedOutputRefs)
+	}
+	sort.Strings(res)
+	return
+}
+
+func grantNamespacesOutside(grants []v1beta1.OutputGrant, namespaces []string) (res []string) {
+	seen := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		seen[ns] = true
+	}
+	for _, g := range grants {
+		if !seen[g.Namespace] {
+			seen[g.Namespace] = true
+			res = append(res, g.Namespace)
+		}
+	}
 	return
 }
 
@@ -334,6 +398,52 @@ func (r LoggingResourceRepository) FluentbitsFor(ctx context.Context, logging v1
 	return res, nil
 }
 
+func (r LoggingResourceRepository) Nodes(ctx context.Context) ([]corev1.Node, error) {
+	var list corev1.NodeList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	return list.Items, nil
+}
+
+func (r LoggingResourceRepository) OutputGrantsInNamespace(ctx context.Context, namespace string) ([]v1beta1.OutputGrant, error) {
+	var list v1beta1.OutputGrantList
+	if err := r.Client.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return lessByNamespacedName(&list.Items[i], &list.Items[j])
+	})
+
+	return list.Items, nil
+}
+
+// FlowTapsFor lists the taps of all namespaces, a tap only takes effect if its flow is loaded
+func (r LoggingResourceRepository) FlowTapsFor(ctx context.Context, logging v1beta1.Logging) ([]v1beta1.FlowTap, error) {
+	var list v1beta1.FlowTapList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return lessByNamespacedName(&list.Items[i], &list.Items[j])
+	})
+
+	var res []v1beta1.FlowTap
+	for _, i := range list.Items {
+		if i.Spec.LoggingRef == logging.Spec.LoggingRef {
+			res = append(res, i)
+		}
+	}
+	return res, nil
+}
+
 func clusterResourceListOpts(logging v1beta1.Logging) []client.ListOption {
 	var opts []client.ListOption
 	if !logging.Spec.AllowClusterResourcesFromAllNamespaces {
@@ -0,0 +1,51 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestSharedOutputNamespaces(t *testing.T) {
+	flows := []v1beta1.Flow{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "local"},
+			Spec:       v1beta1.FlowSpec{LocalOutputRefs: []string{"es"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "shared"},
+			Spec: v1beta1.FlowSpec{SharedOutputRefs: []v1beta1.OutputReference{
+				{Namespace: "shared", Name: "archive"},
+				{Namespace: "audit", Name: "archive"},
+			}},
+		},
+	}
+	syslogNGFlows := []v1beta1.SyslogNGFlow{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "shared"},
+			Spec: v1beta1.SyslogNGFlowSpec{SharedOutputRefs: []v1beta1.OutputReference{
+				{Namespace: "shared", Name: "syslog"},
+			}},
+		},
+	}
+
+	assert.Equal(t, []string{"audit", "shared"}, sharedOutputNamespaces(flows, syslogNGFlows))
+	assert.Empty(t, sharedOutputNamespaces(flows[:1], nil))
+}
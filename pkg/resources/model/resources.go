@@ -15,16 +15,21 @@
 package model
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 )
 
 type LoggingResources struct {
-	AllLoggings []v1beta1.Logging
-	Logging     v1beta1.Logging
-	Fluentd     FluentdLoggingResources
-	SyslogNG    SyslogNGLoggingResources
-	NodeAgents  []v1beta1.NodeAgent
-	Fluentbits  []v1beta1.FluentbitAgent
+	AllLoggings  []v1beta1.Logging
+	Logging      v1beta1.Logging
+	Fluentd      FluentdLoggingResources
+	SyslogNG     SyslogNGLoggingResources
+	NodeAgents   []v1beta1.NodeAgent
+	Fluentbits   []v1beta1.FluentbitAgent
+	OutputGrants []v1beta1.OutputGrant
+	// Nodes of the cluster, only listed if there are multiple FluentbitAgents to detect the ones collecting the same nodes
+	Nodes []corev1.Node
 }
 
 type FluentdLoggingResources struct {
@@ -32,6 +37,7 @@ type FluentdLoggingResources struct {
 	ClusterOutputs ClusterOutputs
 	Flows          []v1beta1.Flow
 	Outputs        Outputs
+	FlowTaps       []v1beta1.FlowTap
 }
 
 type SyslogNGLoggingResources struct {
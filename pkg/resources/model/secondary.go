@@ -0,0 +1,125 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/utils"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/plugins"
+)
+
+// createOutput creates the output plugin with its secondary output, if any.
+// Outputs is nil for cluster outputs, as they can only reference cluster outputs as secondary.
+func createOutput(spec v1beta1.OutputSpec, outputID string, namespace string, outputs Outputs, clusterOutputs ClusterOutputs, secrets SecretLoaderFactory) (types.Directive, error) {
+	plugin, err := plugins.CreateOutput(spec, outputID, secrets.OutputSecretLoaderForNamespace(namespace))
+	if err != nil || spec.SecondaryOutputRef == "" {
+		return plugin, err
+	}
+
+	secondarySpec, secondaryNamespace, err := resolveSecondaryOutput(spec.SecondaryOutputRef, namespace, outputs, clusterOutputs)
+	if err != nil {
+		return nil, err
+	}
+	if secondaryNamespace != namespace {
+		// only cluster outputs are resolved from another namespace
+		secrets = unaudited(secrets)
+	}
+	secondaryPlugin, err := plugins.CreateOutput(secondarySpec, outputID, secrets.OutputSecretLoaderForNamespace(secondaryNamespace))
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to create secondary output %s", spec.SecondaryOutputRef)
+	}
+
+	primary, ok := plugin.(*types.GenericDirective)
+	if !ok || !hasSection(primary, "buffer") {
+		return nil, errors.Errorf("secondary output %s requires a buffered output", spec.SecondaryOutputRef)
+	}
+	secondary := &types.GenericDirective{
+		PluginMeta: types.PluginMeta{
+			Type:      secondaryPlugin.GetPluginMeta().Type,
+			Directive: "secondary",
+			LogLevel:  secondaryPlugin.GetPluginMeta().LogLevel,
+		},
+		Params: secondaryPlugin.GetParams(),
+	}
+	// chunks are flushed to the secondary output from the buffer of the primary one, a buffer section is not allowed here
+	for _, section := range secondaryPlugin.GetSections() {
+		if section.GetPluginMeta().Directive != "buffer" {
+			secondary.SubDirectives = append(secondary.SubDirectives, section)
+		}
+	}
+	primary.SubDirectives = append(primary.SubDirectives, secondary)
+	return primary, nil
+}
+
+// resolveSecondaryOutput returns the spec and namespace of the referenced secondary output,
+// an output in the same namespace takes precedence over a cluster output with the same name
+func resolveSecondaryOutput(ref string, namespace string, outputs Outputs, clusterOutputs ClusterOutputs) (v1beta1.OutputSpec, string, error) {
+	var spec v1beta1.OutputSpec
+	if output := outputs.FindByNamespacedName(namespace, ref); output != nil {
+		spec, namespace = output.Spec, output.Namespace
+	} else if clusterOutput := clusterOutputs.FindByName(ref); clusterOutput != nil {
+		spec, namespace = clusterOutput.Spec.OutputSpec, clusterOutput.Namespace
+	} else {
+		return spec, "", errors.Errorf("referenced secondary output not found: %s", ref)
+	}
+	if spec.SecondaryOutputRef != "" {
+		return spec, "", errors.Errorf("secondary output %s cannot have a secondary output itself", ref)
+	}
+	if spec.RelabelOutputConfig != nil {
+		return spec, "", errors.Errorf("relabel output %s cannot be used as a secondary output", ref)
+	}
+	return spec, namespace, nil
+}
+
+func hasSection(directive types.Directive, name string) bool {
+	for _, section := range directive.GetSections() {
+		if section.GetPluginMeta().Directive == name {
+			return true
+		}
+	}
+	return false
+}
+
+// activateSecondaryOutputs marks the outputs receiving the failed chunks of active outputs active as well
+func activateSecondaryOutputs(resources FluentdLoggingResources) {
+	activate := func(ref string, namespace string, outputs Outputs, referrer string) {
+		if output := outputs.FindByNamespacedName(namespace, ref); output != nil {
+			output.Status.Active = utils.BoolPointer(true)
+			output.Status.ReferencedBy = appendReference(output.Status.ReferencedBy, "Output", namespace, referrer)
+		} else if clusterOutput := resources.ClusterOutputs.FindByName(ref); clusterOutput != nil {
+			clusterOutput.Status.Active = utils.BoolPointer(true)
+			if outputs == nil {
+				clusterOutput.Status.ReferencedBy = appendReference(clusterOutput.Status.ReferencedBy, "ClusterOutput", namespace, referrer)
+			} else {
+				clusterOutput.Status.ReferencedBy = appendReference(clusterOutput.Status.ReferencedBy, "Output", namespace, referrer)
+			}
+		}
+	}
+	for i := range resources.Outputs {
+		output := &resources.Outputs[i]
+		if output.Spec.SecondaryOutputRef != "" && utils.PointerToBool(output.Status.Active) {
+			activate(output.Spec.SecondaryOutputRef, output.Namespace, resources.Outputs, output.Name)
+		}
+	}
+	for i := range resources.ClusterOutputs {
+		output := &resources.ClusterOutputs[i]
+		if output.Spec.SecondaryOutputRef != "" && utils.PointerToBool(output.Status.Active) {
+			activate(output.Spec.SecondaryOutputRef, output.Namespace, nil, output.Name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/render"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+func TestSecondaryOutput(t *testing.T) {
+	outputs := Outputs{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "es"},
+			Spec: v1beta1.OutputSpec{
+				ElasticsearchOutput: &output.ElasticsearchOutput{Host: "elasticsearch"},
+				SecondaryOutputRef:  "dlq",
+			},
+		},
+	}
+	clusterOutputs := ClusterOutputs{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "dlq"},
+			Spec: v1beta1.ClusterOutputSpec{
+				OutputSpec: v1beta1.OutputSpec{
+					FileOutput: &output.FileOutputConfig{Path: "/tmp/dlq/${tag}"},
+				},
+			},
+		},
+	}
+
+	plugin, err := createOutput(outputs[0].Spec, "apps-es", "apps", outputs, clusterOutputs, testSecretLoaderFactory{})
+	require.NoError(t, err)
+
+	b := &bytes.Buffer{}
+	renderer := render.FluentRender{Out: b, Indent: 2}
+	require.NoError(t, renderer.RenderDirectives([]types.Directive{plugin}, 0))
+
+	expected := `
+		<match **>
+		  @type elasticsearch
+		  @id apps-es
+		  exception_backup true
+		  fail_on_detecting_es_version_retry_exceed true
+		  fail_on_putting_template_retry_exceed true
+		  host elasticsearch
+		  reload_connections true
+		  ssl_verify true
+		  utc_index true
+		  verify_es_version_at_startup true
+		  <buffer tag,time>
+		    @type file
+		    chunk_limit_size 8MB
+		    path /buffers/apps-es.*.buffer
+		    retry_forever true
+		    timekey 10m
+		    timekey_wait 1m
+		  </buffer>
+		  <secondary>
+		    @type file
+		    add_path_suffix true
+		    path /tmp/dlq/${tag}
+		  </secondary>
+		</match>`
+	if a, e := diff.TrimLinesInString(b.String()), diff.TrimLinesInString(expected); a != e {
+		t.Errorf("Result does not match (-actual vs +expected):\n%v\nActual: %s", diff.LineDiff(a, e), b.String())
+	}
+}
+
+func TestSecondaryOutputInvalid(t *testing.T) {
+	es := &output.ElasticsearchOutput{Host: "elasticsearch"}
+	testCases := map[string]struct {
+		spec           v1beta1.OutputSpec
+		cluster        bool
+		outputs        Outputs
+		clusterOutputs ClusterOutputs
+	}{
+		"missing reference": {
+			spec:    v1beta1.OutputSpec{ElasticsearchOutput: es, SecondaryOutputRef: "dlq"},
+			outputs: Outputs{},
+		},
+		"secondary with secondary": {
+			spec: v1beta1.OutputSpec{ElasticsearchOutput: es, SecondaryOutputRef: "dlq"},
+			outputs: Outputs{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "dlq"},
+					Spec:       v1beta1.OutputSpec{ElasticsearchOutput: es, SecondaryOutputRef: "other"},
+				},
+			},
+		},
+		"cluster output referencing output": {
+			spec:    v1beta1.OutputSpec{ElasticsearchOutput: es, SecondaryOutputRef: "dlq"},
+			cluster: true,
+			outputs: Outputs{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "dlq"},
+					Spec:       v1beta1.OutputSpec{NullOutputConfig: output.NewNullOutputConfig()},
+				},
+			},
+		},
+		"unbuffered primary": {
+			spec: v1beta1.OutputSpec{RelabelOutputConfig: &output.RelabelOutputConfig{Label: "@other"}, SecondaryOutputRef: "dlq"},
+			clusterOutputs: ClusterOutputs{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "dlq"},
+					Spec:       v1beta1.ClusterOutputSpec{OutputSpec: v1beta1.OutputSpec{NullOutputConfig: output.NewNullOutputConfig()}},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			outputs := testCase.outputs
+			if testCase.cluster {
+				outputs = nil
+			}
+			_, err := createOutput(testCase.spec, "test", "apps", outputs, testCase.clusterOutputs, testSecretLoaderFactory{})
+			require.Error(t, err)
+		})
+	}
+}
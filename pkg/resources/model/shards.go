@@ -0,0 +1,88 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// FluentdShardNamespaces assigns the namespaces of the Flows to the fluentd shards.
+// Returns nil if the flows aren't sharded.
+func FluentdShardNamespaces(resources LoggingResources) (map[int32][]string, error) {
+	shards := resources.Logging.Spec.FluentdSpec.Shards
+	if shards == nil {
+		return nil, nil
+	}
+	if resources.Logging.Spec.FlowConfigOverride != "" {
+		return nil, errors.New("flowConfigOverride can't be used with fluentd shards")
+	}
+	for namespace, shard := range shards.Namespaces {
+		if shard < 0 || shard >= shards.ShardCount() {
+			return nil, errors.Errorf("namespace %q is assigned to shard %d, but there are only %d shards", namespace, shard, shards.ShardCount())
+		}
+	}
+
+	seen := make(map[string]bool)
+	result := make(map[int32][]string)
+	for _, flow := range resources.Fluentd.Flows {
+		if seen[flow.Namespace] {
+			continue
+		}
+		seen[flow.Namespace] = true
+		shard := shards.ShardOf(flow.Namespace)
+		result[shard] = append(result[shard], flow.Namespace)
+	}
+	for _, namespaces := range result {
+		sort.Strings(namespaces)
+	}
+	return result, nil
+}
+
+// FluentdShardResources returns the resources rendered into the config of the given shard:
+// the Flows of the namespaces assigned to the shard, the Outputs of these namespaces and the ones shared with the Flows
+// through an OutputGrant, even if they live in a namespace of another shard, and every ClusterFlow and ClusterOutput
+func FluentdShardResources(resources LoggingResources, shard int32) LoggingResources {
+	shards := resources.Logging.Spec.FluentdSpec.Shards
+	if shards == nil {
+		return resources
+	}
+
+	result := resources
+	result.Fluentd.Flows = nil
+	shared := make(map[v1beta1.OutputReference]bool)
+	for _, flow := range resources.Fluentd.Flows {
+		if shards.ShardOf(flow.Namespace) != shard {
+			continue
+		}
+		result.Fluentd.Flows = append(result.Fluentd.Flows, flow)
+		for _, ref := range flow.Spec.SharedOutputRefs {
+			if v1beta1.OutputGrantAllows(resources.OutputGrants, v1beta1.OutputGrantKindFlow, flow.Namespace, v1beta1.OutputGrantKindOutput, ref) {
+				shared[ref] = true
+			}
+		}
+	}
+	result.Fluentd.Outputs = nil
+	for _, output := range resources.Fluentd.Outputs {
+		ref := v1beta1.OutputReference{Namespace: output.Namespace, Name: output.Name}
+		if shards.ShardOf(output.Namespace) == shard || shared[ref] {
+			result.Fluentd.Outputs = append(result.Fluentd.Outputs, output)
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+)
+
+func shardedResources(shards *v1beta1.FluentdShards, namespaces ...string) LoggingResources {
+	resources := LoggingResources{
+		Logging: v1beta1.Logging{
+			Spec: v1beta1.LoggingSpec{
+				FluentdSpec: &v1beta1.FluentdSpec{Shards: shards},
+			},
+		},
+		Fluentd: FluentdLoggingResources{
+			ClusterFlows:   []v1beta1.ClusterFlow{{ObjectMeta: metav1.ObjectMeta{Name: "all"}}},
+			ClusterOutputs: ClusterOutputs{{ObjectMeta: metav1.ObjectMeta{Name: "archive"}}},
+		},
+	}
+	for _, namespace := range namespaces {
+		meta := metav1.ObjectMeta{Namespace: namespace, Name: "app"}
+		resources.Fluentd.Flows = append(resources.Fluentd.Flows, v1beta1.Flow{ObjectMeta: meta})
+		resources.Fluentd.Outputs = append(resources.Fluentd.Outputs, v1beta1.Output{ObjectMeta: meta})
+	}
+	return resources
+}
+
+func TestFluentdShardOf(t *testing.T) {
+	var disabled *v1beta1.FluentdShards
+	require.Equal(t, int32(1), disabled.ShardCount())
+	require.Equal(t, int32(0), disabled.ShardOf("team-a"))
+
+	single := &v1beta1.FluentdShards{Count: 1}
+	for _, namespace := range []string{"team-a", "team-b", "kube-system"} {
+		require.Equal(t, int32(0), single.ShardOf(namespace))
+	}
+
+	shards := &v1beta1.FluentdShards{Count: 4, Namespaces: map[string]int32{"team-a": 3}}
+	require.Equal(t, int32(3), shards.ShardOf("team-a"))
+	for _, namespace := range []string{"team-b", "team-c", "kube-system"} {
+		shard := shards.ShardOf(namespace)
+		require.True(t, shard >= 0 && shard < 4, "shard %d of %s out of range", shard, namespace)
+		require.Equal(t, shard, shards.ShardOf(namespace), "shard of %s isn't stable", namespace)
+	}
+}
+
+func TestFluentdShardNamespaces(t *testing.T) {
+	namespaces, err := FluentdShardNamespaces(shardedResources(nil, "team-a"))
+	require.NoError(t, err)
+	require.Nil(t, namespaces)
+
+	shards := &v1beta1.FluentdShards{
+		Count:      3,
+		Namespaces: map[string]int32{"team-a": 1, "team-b": 2, "team-c": 1, "unused": 2},
+	}
+	resources := shardedResources(shards, "team-c", "team-a", "team-b", "team-a")
+	namespaces, err = FluentdShardNamespaces(resources)
+	require.NoError(t, err)
+	require.Equal(t, map[int32][]string{
+		1: {"team-a", "team-c"},
+		2: {"team-b"},
+	}, namespaces)
+
+	resources.Logging.Spec.FluentdSpec.Shards = &v1beta1.FluentdShards{Count: 2, Namespaces: map[string]int32{"team-a": 2}}
+	_, err = FluentdShardNamespaces(resources)
+	require.EqualError(t, err, `namespace "team-a" is assigned to shard 2, but there are only 2 shards`)
+
+	resources.Logging.Spec.FluentdSpec.Shards = shards
+	resources.Logging.Spec.FlowConfigOverride = "<match **>\n</match>"
+	_, err = FluentdShardNamespaces(resources)
+	require.Error(t, err)
+}
+
+func TestFluentdShardResources(t *testing.T) {
+	unsharded := shardedResources(nil, "team-a", "team-b")
+	require.Equal(t, unsharded, FluentdShardResources(unsharded, 0))
+
+	shards := &v1beta1.FluentdShards{Count: 2, Namespaces: map[string]int32{"team-a": 0, "team-b": 1}}
+	resources := shardedResources(shards, "team-a", "team-b")
+
+	for shard, namespace := range []string{"team-a", "team-b"} {
+		result := FluentdShardResources(resources, int32(shard))
+		require.Len(t, result.Fluentd.Flows, 1)
+		require.Equal(t, namespace, result.Fluentd.Flows[0].Namespace)
+		require.Len(t, result.Fluentd.Outputs, 1)
+		require.Equal(t, namespace, result.Fluentd.Outputs[0].Namespace)
+		require.Equal(t, resources.Fluentd.ClusterFlows, result.Fluentd.ClusterFlows)
+		require.Equal(t, resources.Fluentd.ClusterOutputs, result.Fluentd.ClusterOutputs)
+	}
+	require.Len(t, resources.Fluentd.Flows, 2, "the resources of the logging must not be modified")
+}
+
+func TestFluentdShardResourcesSharedOutputs(t *testing.T) {
+	shards := &v1beta1.FluentdShards{Count: 2, Namespaces: map[string]int32{"team-a": 0, "team-b": 1, "shared": 1}}
+	resources := shardedResources(shards, "team-a", "team-b", "shared")
+	for i := range resources.Fluentd.Outputs {
+		resources.Fluentd.Outputs[i].Spec.NullOutputConfig = output.NewNullOutputConfig()
+	}
+	resources.Fluentd.Outputs = append(resources.Fluentd.Outputs, v1beta1.Output{ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "private"}})
+	resources.Fluentd.Flows[0].Spec.SharedOutputRefs = []v1beta1.OutputReference{
+		{Namespace: "shared", Name: "app"},
+		{Namespace: "shared", Name: "private"},
+	}
+	resources.OutputGrants = []v1beta1.OutputGrant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "app"},
+			Spec: v1beta1.OutputGrantSpec{
+				From: []v1beta1.OutputGrantFrom{{Kind: v1beta1.OutputGrantKindFlow, Namespace: "team-a"}},
+				To:   []v1beta1.OutputGrantTo{{Kind: v1beta1.OutputGrantKindOutput, Name: "app"}},
+			},
+		},
+	}
+
+	outputsOf := func(result LoggingResources) (refs []string) {
+		for _, out := range result.Fluentd.Outputs {
+			refs = append(refs, out.Namespace+"/"+out.Name)
+		}
+		return
+	}
+
+	// the granted output of the other shard is kept for the flow of team-a, the one without a grant is not
+	shardA := FluentdShardResources(resources, 0)
+	require.Equal(t, []string{"team-a/app", "shared/app"}, outputsOf(shardA))
+	_, err := FlowForFlow(shardA.Fluentd.Flows[0], shardA.Fluentd.ClusterOutputs, shardA.Fluentd.Outputs, shardA.OutputGrants, testSecretLoaderFactory{})
+	require.EqualError(t, err, "referenced output shared/private is not granted to flow team-a/app")
+
+	require.Equal(t, []string{"team-b/app", "shared/app", "shared/private"}, outputsOf(FluentdShardResources(resources, 1)))
+}
@@ -17,6 +17,7 @@ package model
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/secret"
@@ -30,7 +31,8 @@ import (
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/plugins"
 )
 
-func CreateSystem(resources LoggingResources, secrets SecretLoaderFactory, logger logr.Logger) (*types.System, error) {
+// CreateSystem builds the fluentd config of the resources, the result of rendering each flow is recorded in renderErrors
+func CreateSystem(resources LoggingResources, secrets SecretLoaderFactory, renderErrors FlowRenderErrors, logger logr.Logger) (*types.System, error) {
 	logging := resources.Logging
 
 	var forwardInput *input.ForwardInputConfig
@@ -76,8 +78,20 @@ func CreateSystem(resources LoggingResources, secrets SecretLoaderFactory, logge
 
 	builder := types.NewSystemBuilder(rootInput, globalFilters, router)
 
+	if err := registerExtraInputs(builder, logging, secrets); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
 	for _, flowCr := range resources.Fluentd.Flows {
-		flow, err := FlowForFlow(flowCr, resources.Fluentd.ClusterOutputs, resources.Fluentd.Outputs, secrets)
+		var flow *types.Flow
+		var err error
+		if logging.Spec.StrictTenancy {
+			flow, err = flowForTenantFlow(flowCr, resources.Fluentd, resources.OutputGrants, secrets, renderErrors)
+		} else {
+			flow, err = FlowForFlow(flowCr, resources.Fluentd.ClusterOutputs, resources.Fluentd.Outputs, resources.OutputGrants, secrets)
+		}
+		renderErrors.Record(&flowCr, err)
 		if err != nil {
 			if logging.Spec.SkipInvalidResources {
 				logger.Error(err, "Flow contains errors, skipping.")
@@ -86,13 +100,24 @@ func CreateSystem(resources LoggingResources, secrets SecretLoaderFactory, logge
 				return nil, err
 			}
 		}
+		taps := tapsFor(resources.Fluentd.FlowTaps, v1beta1.FlowTapKindFlow, flowCr.Namespace, flowCr.Name, now)
+		if err := registerFlowTaps(builder, flow, taps, resources.Fluentd, secrets, logger); err != nil {
+			return nil, err
+		}
 		err = builder.RegisterFlow(flow)
 		if err != nil {
 			return nil, err
 		}
 	}
 	for _, flowCr := range resources.Fluentd.ClusterFlows {
-		flow, err := FlowForClusterFlow(flowCr, resources.Fluentd.ClusterOutputs, secrets)
+		var flow *types.Flow
+		var err error
+		if logging.Spec.StrictTenancy && flowCr.Namespace != logging.Spec.ControlNamespace {
+			flow, err = clusterFlowForTenantNamespace(flowCr, resources.Fluentd.ClusterOutputs, secrets)
+		} else {
+			flow, err = FlowForClusterFlow(flowCr, resources.Fluentd.ClusterOutputs, secrets)
+		}
+		renderErrors.Record(&flowCr, err)
 		if err != nil {
 			if logging.Spec.SkipInvalidResources {
 				logger.Error(err, "ClusterFlow contains errors, skipping.")
@@ -101,6 +126,10 @@ func CreateSystem(resources LoggingResources, secrets SecretLoaderFactory, logge
 				return nil, err
 			}
 		}
+		taps := tapsFor(resources.Fluentd.FlowTaps, v1beta1.FlowTapKindClusterFlow, flowCr.Namespace, flowCr.Name, now)
+		if err := registerFlowTaps(builder, flow, taps, resources.Fluentd, secrets, logger); err != nil {
+			return nil, err
+		}
 		err = builder.RegisterFlow(flow)
 		if err != nil {
 			return nil, err
@@ -203,7 +232,7 @@ func FlowForError(outputRef string, clusterOutputs ClusterOutputs, secrets Secre
 	}
 
 	if clusterOutput := clusterOutputs.FindByName(outputRef); clusterOutput != nil {
-		plugin, err := plugins.CreateOutput(clusterOutput.Spec.OutputSpec, "main-fluentd-error", secrets.OutputSecretLoaderForNamespace(clusterOutput.Namespace))
+		plugin, err := createOutput(clusterOutput.Spec.OutputSpec, "main-fluentd-error", clusterOutput.Namespace, nil, clusterOutputs, secrets)
 		if err != nil {
 			return nil, errors.WrapIff(err, "failed to create configured output %q", outputRef)
 		}
@@ -213,7 +242,7 @@ func FlowForError(outputRef string, clusterOutputs ClusterOutputs, secrets Secre
 	return nil, errors.Errorf("there is no ClusterOutput named %s", outputRef)
 }
 
-func FlowForFlow(flow v1beta1.Flow, clusterOutputs ClusterOutputs, outputs Outputs, secrets SecretLoaderFactory) (*types.Flow, error) {
+func FlowForFlow(flow v1beta1.Flow, clusterOutputs ClusterOutputs, outputs Outputs, grants []v1beta1.OutputGrant, secrets SecretLoaderFactory) (*types.Flow, error) {
 	if flow.Spec.Match != nil && flow.Spec.Selectors != nil {
 		return nil, errors.Errorf("match and selectors cannot be defined simultaneously for flow %s",
 			utils.ObjectKeyFromObjectMeta(&flow).String())
@@ -269,7 +298,7 @@ func FlowForFlow(flow v1beta1.Flow, clusterOutputs ClusterOutputs, outputs Outpu
 	for _, outputRef := range flow.Spec.GlobalOutputRefs {
 		if clusterOutput := clusterOutputs.FindByName(outputRef); clusterOutput != nil {
 			outputID := fmt.Sprintf("%s:clusteroutput:%s:%s", flowID, clusterOutput.Namespace, clusterOutput.Name)
-			plugin, err := plugins.CreateOutput(clusterOutput.Spec.OutputSpec, outputID, secrets.OutputSecretLoaderForNamespace(clusterOutput.Namespace))
+			plugin, err := createOutput(clusterOutput.Spec.OutputSpec, outputID, clusterOutput.Namespace, nil, clusterOutputs, unaudited(secrets))
 			if err != nil {
 				errs = errors.Append(errs, errors.WrapIff(err, "failed to create configured output %s", outputRef))
 				continue
@@ -282,7 +311,7 @@ func FlowForFlow(flow v1beta1.Flow, clusterOutputs ClusterOutputs, outputs Outpu
 	for _, outputRef := range flow.Spec.LocalOutputRefs {
 		if output := outputs.FindByNamespacedName(flow.Namespace, outputRef); output != nil {
 			outputID := fmt.Sprintf("%s:output:%s:%s", flowID, output.Namespace, output.Name)
-			plugin, err := plugins.CreateOutput(output.Spec, outputID, secrets.OutputSecretLoaderForNamespace(output.Namespace))
+			plugin, err := createOutput(output.Spec, outputID, output.Namespace, outputs, clusterOutputs, secrets)
 			if err != nil {
 				errs = errors.Append(errs, errors.WrapIff(err, "failed to create configured output %s/%s", output.Namespace, output.Name))
 				continue
@@ -292,6 +321,23 @@ func FlowForFlow(flow v1beta1.Flow, clusterOutputs ClusterOutputs, outputs Outpu
 			errs = errors.Append(errs, errors.Errorf("referenced output %s not found for flow %s/%s", outputRef, flow.Namespace, flow.Name))
 		}
 	}
+	for _, outputRef := range flow.Spec.SharedOutputRefs {
+		if !v1beta1.OutputGrantAllows(grants, v1beta1.OutputGrantKindFlow, flow.Namespace, v1beta1.OutputGrantKindOutput, outputRef) {
+			errs = errors.Append(errs, errors.Errorf("referenced output %s/%s is not granted to flow %s/%s", outputRef.Namespace, outputRef.Name, flow.Namespace, flow.Name))
+			continue
+		}
+		if output := outputs.FindByNamespacedName(outputRef.Namespace, outputRef.Name); output != nil {
+			outputID := fmt.Sprintf("%s:output:%s:%s", flowID, output.Namespace, output.Name)
+			plugin, err := createOutput(output.Spec, outputID, output.Namespace, outputs, clusterOutputs, secrets)
+			if err != nil {
+				errs = errors.Append(errs, errors.WrapIff(err, "failed to create configured output %s/%s", output.Namespace, output.Name))
+				continue
+			}
+			allOutputs = append(allOutputs, plugin)
+		} else {
+			errs = errors.Append(errs, errors.Errorf("referenced output %s/%s not found for flow %s/%s", outputRef.Namespace, outputRef.Name, flow.Namespace, flow.Name))
+		}
+	}
 	result.WithOutputs(allOutputs...)
 
 	filters, err := filtersForFilters(flowID, flow.Name, secrets.OutputSecretLoaderForNamespace(flow.Namespace), flow.Spec.Filters)
@@ -357,7 +403,7 @@ func FlowForClusterFlow(flow v1beta1.ClusterFlow, clusterOutputs ClusterOutputs,
 	for _, outputRef := range flow.Spec.GlobalOutputRefs {
 		if clusterOutput := clusterOutputs.FindByName(outputRef); clusterOutput != nil {
 			outputID := fmt.Sprintf("%s:clusteroutput:%s:%s", flowID, clusterOutput.Namespace, clusterOutput.Name)
-			plugin, err := plugins.CreateOutput(clusterOutput.Spec.OutputSpec, outputID, secrets.OutputSecretLoaderForNamespace(clusterOutput.Namespace))
+			plugin, err := createOutput(clusterOutput.Spec.OutputSpec, outputID, clusterOutput.Namespace, nil, clusterOutputs, unaudited(secrets))
 			if err != nil {
 				errs = errors.Append(errs, errors.WrapIff(err, "failed to create configured output %q", outputRef))
 				continue
@@ -394,7 +440,7 @@ func FlowForDefaultFlow(logging v1beta1.Logging, clusterOutputs ClusterOutputs,
 	for _, outputRef := range logging.Spec.DefaultFlowSpec.GlobalOutputRefs {
 		if clusterOutput := clusterOutputs.FindByName(outputRef); clusterOutput != nil {
 			outputID := fmt.Sprintf("%s:clusteroutput:%s:%s", flowID, clusterOutput.Namespace, clusterOutput.Name)
-			plugin, err := plugins.CreateOutput(clusterOutput.Spec.OutputSpec, outputID, secrets.OutputSecretLoaderForNamespace(clusterOutput.Namespace))
+			plugin, err := createOutput(clusterOutput.Spec.OutputSpec, outputID, clusterOutput.Namespace, nil, clusterOutputs, unaudited(secrets))
 			if err != nil {
 				errs = errors.Append(errs, errors.WrapIff(err, "failed to create configured output %q", outputRef))
 				continue
@@ -0,0 +1,97 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+)
+
+func TestFlowForFlowSharedOutputRefs(t *testing.T) {
+	outputs := Outputs{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "archive"},
+			Spec:       v1beta1.OutputSpec{FileOutput: &output.FileOutputConfig{Path: "/tmp/archive/${tag}"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "audit"},
+			Spec:       v1beta1.OutputSpec{FileOutput: &output.FileOutputConfig{Path: "/tmp/audit/${tag}"}},
+		},
+	}
+	grants := []v1beta1.OutputGrant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "archive"},
+			Spec: v1beta1.OutputGrantSpec{
+				From: []v1beta1.OutputGrantFrom{{Kind: v1beta1.OutputGrantKindFlow, Namespace: "apps"}},
+				To: []v1beta1.OutputGrantTo{
+					{Kind: v1beta1.OutputGrantKindOutput, Name: "archive"},
+					{Kind: v1beta1.OutputGrantKindOutput, Name: "gone"},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		namespace string
+		ref       v1beta1.OutputReference
+		wantErr   string
+	}{
+		"granted": {
+			namespace: "apps",
+			ref:       v1beta1.OutputReference{Namespace: "shared", Name: "archive"},
+		},
+		"output not granted": {
+			namespace: "apps",
+			ref:       v1beta1.OutputReference{Namespace: "shared", Name: "audit"},
+			wantErr:   "referenced output shared/audit is not granted to flow apps/flow",
+		},
+		"namespace not granted": {
+			namespace: "other",
+			ref:       v1beta1.OutputReference{Namespace: "shared", Name: "archive"},
+			wantErr:   "referenced output shared/archive is not granted to flow other/flow",
+		},
+		"grant of another namespace": {
+			namespace: "apps",
+			ref:       v1beta1.OutputReference{Namespace: "elsewhere", Name: "archive"},
+			wantErr:   "referenced output elsewhere/archive is not granted to flow apps/flow",
+		},
+		"granted but missing": {
+			namespace: "apps",
+			ref:       v1beta1.OutputReference{Namespace: "shared", Name: "gone"},
+			wantErr:   "referenced output shared/gone not found for flow apps/flow",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			flow := v1beta1.Flow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCase.namespace, Name: "flow"},
+				Spec:       v1beta1.FlowSpec{SharedOutputRefs: []v1beta1.OutputReference{testCase.ref}},
+			}
+			result, err := FlowForFlow(flow, nil, outputs, grants, testSecretLoaderFactory{})
+			if testCase.wantErr != "" {
+				require.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Outputs, 1)
+		})
+	}
+}
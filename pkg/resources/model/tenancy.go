@@ -0,0 +1,210 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// FlowTenancyViolations lists the strict tenancy rules broken by a namespaced Flow.
+// Secret lookups are audited while rendering the flow, see SecretAudit.
+func FlowTenancyViolations(flow v1beta1.Flow, resources FluentdLoggingResources) (violations []string) {
+	if flow.Spec.FlowLabel != "" {
+		violations = append(violations, fmt.Sprintf("custom flowLabel %q is not allowed", flow.Spec.FlowLabel))
+	}
+
+	for _, ref := range flow.Spec.LocalOutputRefs {
+		if output := resources.Outputs.FindByNamespacedName(flow.Namespace, ref); output != nil && output.Spec.RelabelOutputConfig != nil {
+			violations = append(violations, fmt.Sprintf("relabel output %s is not allowed", ref))
+		}
+	}
+	for _, ref := range flow.Spec.SharedOutputRefs {
+		if output := resources.Outputs.FindByNamespacedName(ref.Namespace, ref.Name); output != nil && output.Spec.RelabelOutputConfig != nil {
+			violations = append(violations, fmt.Sprintf("relabel output %s/%s is not allowed", ref.Namespace, ref.Name))
+		}
+	}
+
+	return
+}
+
+// SyslogNGFlowTenancyViolations lists the strict tenancy rules broken by a namespaced SyslogNGFlow
+func SyslogNGFlowTenancyViolations(flow v1beta1.SyslogNGFlow, resources SyslogNGLoggingResources) (violations []string) {
+	for _, ref := range flow.Spec.LocalOutputRefs {
+		if resources.Outputs.FindByNamespacedName(flow.Namespace, ref) == nil {
+			violations = append(violations, fmt.Sprintf("output %s cannot be found in the flow's namespace", ref))
+		}
+	}
+	return
+}
+
+// OutputTenancyViolations lists the strict tenancy rules broken by a namespaced Output
+func OutputTenancyViolations(output v1beta1.Output) (violations []string) {
+	if output.Spec.RelabelOutputConfig != nil {
+		violations = append(violations, "relabel output is not allowed in a tenant namespace")
+	}
+	return
+}
+
+func flowForTenantFlow(flow v1beta1.Flow, resources FluentdLoggingResources, grants []v1beta1.OutputGrant, secrets SecretLoaderFactory, renderErrors FlowRenderErrors) (*types.Flow, error) {
+	// the secrets of the granted shared outputs are looked up in the namespace of the output
+	var namespaces []string
+	for _, ref := range flow.Spec.SharedOutputRefs {
+		if v1beta1.OutputGrantAllows(grants, v1beta1.OutputGrantKindFlow, flow.Namespace, v1beta1.OutputGrantKindOutput, ref) {
+			namespaces = append(namespaces, ref.Namespace)
+		}
+	}
+	audit := renderErrors.AuditSecrets(&flow, secrets.OutputSecretLoaderForNamespace, namespaces...)
+	result, err := FlowForFlow(flow, resources.ClusterOutputs, resources.Outputs, grants, audit)
+	if violations := FlowTenancyViolations(flow, resources); len(violations) > 0 {
+		err = errors.Append(err, errors.Errorf("flow %s/%s violates strict tenancy: %s", flow.Namespace, flow.Name, strings.Join(violations, "; ")))
+	}
+	return result, err
+}
+
+// SecretAudit is a secret loader factory for rendering a tenant resource.
+// Every secret looked up outside of the namespace of the resource and the namespaces it has been granted access to
+// is recorded as a strict tenancy violation of the resource, including the secret backend references.
+type SecretAudit struct {
+	resource     client.Object
+	loaderFor    func(namespace string) secret.SecretLoader
+	namespaces   []string
+	renderErrors FlowRenderErrors
+}
+
+// AuditSecrets returns a SecretAudit of the resource that records its violations in the render errors
+func (e FlowRenderErrors) AuditSecrets(resource client.Object, loaderFor func(namespace string) secret.SecretLoader, namespaces ...string) *SecretAudit {
+	return &SecretAudit{
+		resource:     resource,
+		loaderFor:    loaderFor,
+		namespaces:   append([]string{resource.GetNamespace()}, namespaces...),
+		renderErrors: e,
+	}
+}
+
+// Deprecated: use SecretLoaderForNamespace instead
+func (a *SecretAudit) OutputSecretLoaderForNamespace(namespace string) secret.SecretLoader {
+	return a.SecretLoaderForNamespace(namespace)
+}
+
+func (a *SecretAudit) SecretLoaderForNamespace(namespace string) secret.SecretLoader {
+	for _, ns := range a.namespaces {
+		if ns == namespace {
+			return a.loaderFor(namespace)
+		}
+	}
+	return &auditedSecretLoader{
+		SecretLoader: a.loaderFor(namespace),
+		audit:        a,
+		namespace:    namespace,
+	}
+}
+
+// unaudited returns the secret loader factory without the audit, for the outputs managed by the cluster administrator
+func unaudited(secrets SecretLoaderFactory) SecretLoaderFactory {
+	if audit, ok := secrets.(*SecretAudit); ok {
+		return secretLoaderFactoryFunc(audit.loaderFor)
+	}
+	return secrets
+}
+
+type secretLoaderFactoryFunc func(namespace string) secret.SecretLoader
+
+func (f secretLoaderFactoryFunc) OutputSecretLoaderForNamespace(namespace string) secret.SecretLoader {
+	return f(namespace)
+}
+
+// auditedSecretLoader records the lookups of a secret loader bound to a namespace the audited resource cannot access
+type auditedSecretLoader struct {
+	secret.SecretLoader
+	audit     *SecretAudit
+	namespace string
+}
+
+func (l *auditedSecretLoader) Load(s *secret.Secret) (string, error) {
+	if s != nil && s.Value == "" {
+		for _, from := range []*secret.ValueFrom{s.ValueFrom, s.MountFrom} {
+			if from != nil && from.SecretKeyRef != nil {
+				l.audit.renderErrors.RecordTenancyViolation(l.audit.resource, secretLookupViolation(l.namespace, l.audit.resource.GetNamespace(), from.SecretKeyRef.Name))
+			}
+		}
+	}
+	return l.SecretLoader.Load(s)
+}
+
+func secretLookupViolation(namespace string, resourceNamespace string, name string) string {
+	if scheme, ref, ok := secretbackend.ParseRef(name); ok {
+		if scheme == secretbackend.SchemeKV {
+			return fmt.Sprintf("kv secret %s is looked up outside of namespace %s", path.Join(namespace, ref), resourceNamespace)
+		}
+		return fmt.Sprintf("%s secret backend %s is used in namespace %s outside of namespace %s", scheme, ref, namespace, resourceNamespace)
+	}
+	return fmt.Sprintf("secret %s/%s is looked up outside of namespace %s", namespace, name, resourceNamespace)
+}
+
+// constrainMatchesToNamespace narrows the select statements of a flow to the given namespace.
+// Exclude statements are kept as is since they can only narrow the selection further.
+// Returns no matches if none of the select statements cover the namespace.
+func constrainMatchesToNamespace(matches []types.FlowMatch, namespace string) []types.FlowMatch {
+	var result []types.FlowMatch
+	var selects, kept int
+	for _, match := range matches {
+		if !match.Negate {
+			selects++
+			if !selectsNamespace(match, namespace) {
+				continue
+			}
+			kept++
+			match.Namespaces = []string{namespace}
+		}
+		result = append(result, match)
+	}
+	if selects == 0 {
+		result = append(result, types.FlowMatch{Namespaces: []string{namespace}})
+	} else if kept == 0 {
+		return nil
+	}
+	return result
+}
+
+func selectsNamespace(match types.FlowMatch, namespace string) bool {
+	for _, ns := range match.Namespaces {
+		if ns == "" || ns == namespace {
+			return true
+		}
+	}
+	return len(match.Namespaces) == 0
+}
+
+func clusterFlowForTenantNamespace(flow v1beta1.ClusterFlow, clusterOutputs ClusterOutputs, secrets SecretLoaderFactory) (*types.Flow, error) {
+	result, err := FlowForClusterFlow(flow, clusterOutputs, secrets)
+	if result == nil {
+		return result, err
+	}
+	result.Matches = constrainMatchesToNamespace(result.Matches, flow.Namespace)
+	if len(result.Matches) == 0 {
+		err = errors.Append(err, errors.Errorf("clusterflow %s/%s violates strict tenancy: it selects no logs from its own namespace", flow.Namespace, flow.Name))
+	}
+	return result, err
+}
@@ -0,0 +1,213 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+)
+
+func TestFlowTenancyViolations(t *testing.T) {
+	resources := FluentdLoggingResources{
+		ClusterOutputs: ClusterOutputs{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "dlq"},
+				Spec: v1beta1.ClusterOutputSpec{
+					OutputSpec: v1beta1.OutputSpec{FileOutput: &output.FileOutputConfig{Path: "/tmp/dlq/${tag}"}},
+				},
+			},
+		},
+		Outputs: Outputs{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "es"},
+				Spec: v1beta1.OutputSpec{
+					ElasticsearchOutput: &output.ElasticsearchOutput{Host: "elasticsearch"},
+					SecondaryOutputRef:  "dlq",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "relabel"},
+				Spec:       v1beta1.OutputSpec{RelabelOutputConfig: &output.RelabelOutputConfig{Label: "@other"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "archive"},
+				Spec:       v1beta1.OutputSpec{FileOutput: &output.FileOutputConfig{Path: "/tmp/archive/${tag}"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "relabel"},
+				Spec:       v1beta1.OutputSpec{RelabelOutputConfig: &output.RelabelOutputConfig{Label: "@other"}},
+			},
+		},
+	}
+	grants := []v1beta1.OutputGrant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "apps"},
+			Spec: v1beta1.OutputGrantSpec{
+				From: []v1beta1.OutputGrantFrom{{Kind: v1beta1.OutputGrantKindFlow, Namespace: "apps"}},
+				To:   []v1beta1.OutputGrantTo{{Kind: v1beta1.OutputGrantKindOutput}},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		spec       v1beta1.FlowSpec
+		violations []string
+		renderErr  bool
+	}{
+		"local output": {
+			spec: v1beta1.FlowSpec{LocalOutputRefs: []string{"es"}},
+		},
+		"cluster output": {
+			spec: v1beta1.FlowSpec{GlobalOutputRefs: []string{"dlq"}},
+		},
+		"granted shared output": {
+			spec: v1beta1.FlowSpec{SharedOutputRefs: []v1beta1.OutputReference{{Namespace: "shared", Name: "archive"}}},
+		},
+		"local output with a secondary cluster output": {
+			spec: v1beta1.FlowSpec{LocalOutputRefs: []string{"es"}, GlobalOutputRefs: []string{"dlq"}},
+		},
+		"custom flow label": {
+			spec:       v1beta1.FlowSpec{FlowLabel: "@custom", LocalOutputRefs: []string{"es"}},
+			violations: []string{`custom flowLabel "@custom" is not allowed`},
+			renderErr:  true,
+		},
+		"local relabel output": {
+			spec:       v1beta1.FlowSpec{LocalOutputRefs: []string{"relabel"}},
+			violations: []string{"relabel output relabel is not allowed"},
+			renderErr:  true,
+		},
+		"shared relabel output": {
+			spec:       v1beta1.FlowSpec{SharedOutputRefs: []v1beta1.OutputReference{{Namespace: "shared", Name: "relabel"}}},
+			violations: []string{"relabel output shared/relabel is not allowed"},
+			renderErr:  true,
+		},
+		"shared output without grant": {
+			spec:      v1beta1.FlowSpec{SharedOutputRefs: []v1beta1.OutputReference{{Namespace: "other", Name: "archive"}}},
+			renderErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			flow := v1beta1.Flow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "flow"},
+				Spec:       testCase.spec,
+			}
+			assert.Equal(t, testCase.violations, FlowTenancyViolations(flow, resources))
+
+			renderErrors := FlowRenderErrors{}
+			_, err := flowForTenantFlow(flow, resources, grants, testSecretLoaderFactory{}, renderErrors)
+			if testCase.renderErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			// the secrets of cluster outputs and granted shared outputs are not tenancy violations of the flow
+			assert.Empty(t, renderErrors.TenancyViolations(&flow))
+		})
+	}
+}
+
+type valueSecretLoader struct{}
+
+func (valueSecretLoader) Load(*secret.Secret) (string, error) {
+	return "value", nil
+}
+
+func TestSecretAudit(t *testing.T) {
+	renderErrors := FlowRenderErrors{}
+	flow := &v1beta1.Flow{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "flow"}}
+	loaderFor := func(string) secret.SecretLoader { return valueSecretLoader{} }
+	audit := renderErrors.AuditSecrets(flow, loaderFor, "shared")
+
+	secretRef := func(name string) *secret.Secret {
+		return &secret.Secret{ValueFrom: &secret.ValueFrom{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  "password",
+		}}}
+	}
+	load := func(secrets SecretLoaderFactory, namespace string, s *secret.Secret) {
+		value, err := secrets.OutputSecretLoaderForNamespace(namespace).Load(s)
+		require.NoError(t, err)
+		require.Equal(t, "value", value)
+	}
+
+	load(audit, "apps", secretRef("es"))
+	load(audit, "shared", secretRef("archive"))
+	load(audit, "logging", &secret.Secret{Value: "inline"})
+	load(unaudited(audit), "logging", secretRef("dlq"))
+	assert.Empty(t, renderErrors.TenancyViolations(flow))
+
+	load(audit, "logging", secretRef("es"))
+	load(audit, "logging", secretRef("es"))
+	load(audit, "logging", secretRef("kv:es"))
+	load(audit, "logging", &secret.Secret{MountFrom: secretRef("csi:vault").ValueFrom})
+	assert.Equal(t, []string{
+		"secret logging/es is looked up outside of namespace apps",
+		"kv secret logging/es is looked up outside of namespace apps",
+		"csi secret backend vault is used in namespace logging outside of namespace apps",
+	}, renderErrors.TenancyViolations(flow))
+
+	// the audit does not count as a render of the flow
+	rendered, err := renderErrors.Lookup(flow)
+	require.False(t, rendered)
+	require.NoError(t, err)
+}
+
+func TestSyslogNGFlowTenancyViolations(t *testing.T) {
+	resources := SyslogNGLoggingResources{
+		Outputs: SyslogNGOutputs{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "local"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "archive"}},
+		},
+	}
+
+	testCases := map[string]struct {
+		spec       v1beta1.SyslogNGFlowSpec
+		violations []string
+	}{
+		"local output": {
+			spec: v1beta1.SyslogNGFlowSpec{LocalOutputRefs: []string{"local"}},
+		},
+		"cluster output": {
+			spec: v1beta1.SyslogNGFlowSpec{GlobalOutputRefs: []string{"archive"}},
+		},
+		"shared output": {
+			spec: v1beta1.SyslogNGFlowSpec{SharedOutputRefs: []v1beta1.OutputReference{{Namespace: "shared", Name: "archive"}}},
+		},
+		"local output of another namespace": {
+			spec:       v1beta1.SyslogNGFlowSpec{LocalOutputRefs: []string{"local", "archive"}},
+			violations: []string{"output archive cannot be found in the flow's namespace"},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			flow := v1beta1.SyslogNGFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "flow"},
+				Spec:       testCase.spec,
+			}
+			assert.Equal(t, testCase.violations, SyslogNGFlowTenancyViolations(flow, resources))
+		})
+	}
+}
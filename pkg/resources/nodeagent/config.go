@@ -47,6 +47,24 @@ var fluentBitConfigTemplate = `
     multiline.parser {{- range $i, $v := .Input.MultilineParser }}{{ if $i }},{{ end}} {{ $v }}{{ end }}
     {{- end }}
 
+{{- with .SystemdInput }}
+
+[INPUT]
+    Name         systemd
+    Tag          {{ .Tag }}
+    {{- range $unit := .Units }}
+    Systemd_Filter  _SYSTEMD_UNIT={{ $unit }}
+    {{- end }}
+    {{- if gt (len .Units) 1 }}
+    Systemd_Filter_Type  Or
+    {{- end }}
+    {{- range $key, $value := .Values }}
+    {{- if $value }}
+    {{ $key }}  {{$value}}
+    {{- end }}
+    {{- end }}
+{{- end }}
+
 {{- if not .DisableKubernetesFilter }}
 [FILTER]
     Name        kubernetes
@@ -57,6 +75,23 @@ var fluentBitConfigTemplate = `
     {{- end }}
 {{- end}}
 
+{{- with .SystemdInput }}
+
+[FILTER]
+    Name        modify
+    Match       {{ .Match }}
+    Copy        {{ .UnitKey }} container_name
+    Copy        {{ .HostnameKey }} host
+
+[FILTER]
+    Name        nest
+    Match       {{ .Match }}
+    Operation   nest
+    Wildcard    container_name
+    Wildcard    host
+    Nest_under  kubernetes
+{{- end }}
+
 {{- if .AwsFilter }}
 
 [FILTER]
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"emperror.dev/errors"
@@ -37,6 +38,15 @@ type fluentbitInputConfig struct {
 	MultilineParser []string
 }
 
+type systemdInputConfig struct {
+	Tag         string
+	Match       string
+	Units       []string
+	Values      map[string]string
+	UnitKey     string
+	HostnameKey string
+}
+
 type upstreamNode struct {
 	Name string
 	Host string
@@ -67,6 +77,7 @@ type fluentBitConfig struct {
 	TargetHost              string
 	TargetPort              int32
 	Input                   fluentbitInputConfig
+	SystemdInput            *systemdInputConfig
 	DisableKubernetesFilter bool
 	KubernetesFilter        map[string]string
 	AwsFilter               map[string]string
@@ -204,6 +215,28 @@ func (n *nodeAgentInstance) configSecret() (runtime.Object, reconciler.DesiredSt
 		}
 		input.AwsFilter = awsFilter
 	}
+	if systemd := n.nodeAgent.FluentbitSpec.InputSystemd; systemd != nil {
+		values, err := mapper.StringsMap(systemd)
+		if err != nil {
+			return nil, reconciler.StatePresent, errors.WrapIf(err, "failed to map systemd input for fluentbit")
+		}
+		tagPrefix := systemd.TagPrefix
+		if tagPrefix == "" {
+			tagPrefix = "systemd"
+		}
+		input.SystemdInput = &systemdInputConfig{
+			Tag:         tagPrefix + ".*",
+			Match:       tagPrefix + ".*",
+			Units:       systemd.Units,
+			Values:      values,
+			UnitKey:     "_SYSTEMD_UNIT",
+			HostnameKey: "_HOSTNAME",
+		}
+		if strings.EqualFold(systemd.StripUnderscores, "on") || strings.EqualFold(systemd.StripUnderscores, "true") {
+			input.SystemdInput.UnitKey = "SYSTEMD_UNIT"
+			input.SystemdInput.HostnameKey = "HOSTNAME"
+		}
+	}
 	if n.nodeAgent.FluentbitSpec.TargetHost != "" {
 		input.TargetHost = n.nodeAgent.FluentbitSpec.TargetHost
 	}
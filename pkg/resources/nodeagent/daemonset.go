@@ -23,6 +23,7 @@ import (
 	"github.com/cisco-open/operator-tools/pkg/merge"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	util "github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
 	"github.com/kube-logging/logging-operator/pkg/resources/templates"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -70,10 +71,11 @@ func (n *nodeAgentInstance) daemonSet() (runtime.Object, reconciler.DesiredState
 					ServiceAccountName: n.getServiceAccount(),
 					Volumes:            n.generateVolume(),
 					SecurityContext: &corev1.PodSecurityContext{
-						FSGroup:      n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.FSGroup,
-						RunAsNonRoot: n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsNonRoot,
-						RunAsUser:    n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsUser,
-						RunAsGroup:   n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsGroup,
+						FSGroup:        n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.FSGroup,
+						RunAsNonRoot:   n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsNonRoot,
+						RunAsUser:      n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsUser,
+						RunAsGroup:     n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.RunAsGroup,
+						SeccompProfile: n.nodeAgent.FluentbitSpec.Security.PodSecurityContext.SeccompProfile,
 					},
 					Containers: []corev1.Container{
 						{
@@ -87,6 +89,8 @@ func (n *nodeAgentInstance) daemonSet() (runtime.Object, reconciler.DesiredState
 								AllowPrivilegeEscalation: n.nodeAgent.FluentbitSpec.Security.SecurityContext.AllowPrivilegeEscalation,
 								Privileged:               n.nodeAgent.FluentbitSpec.Security.SecurityContext.Privileged,
 								SELinuxOptions:           n.nodeAgent.FluentbitSpec.Security.SecurityContext.SELinuxOptions,
+								Capabilities:             n.nodeAgent.FluentbitSpec.Security.SecurityContext.Capabilities,
+								SeccompProfile:           n.nodeAgent.FluentbitSpec.Security.SecurityContext.SeccompProfile,
 							},
 						},
 					},
@@ -112,6 +116,10 @@ func (n *nodeAgentInstance) daemonSet() (runtime.Object, reconciler.DesiredState
 		return desired, reconciler.StatePresent, errors.WrapIf(err, "unable to merge overrides to base object")
 	}
 
+	if err := podsecurity.Validate(n.nodeAgent.FluentbitSpec.Security.PodSecurityStandard, desired.Spec.Template.Spec); err != nil {
+		return desired, reconciler.StatePresent, errors.WrapIf(err, "node agent daemonset")
+	}
+
 	return desired, reconciler.StatePresent, nil
 }
 
@@ -40,14 +40,13 @@ import (
 )
 
 const (
-	defaultServiceAccountName      = "fluentbit"
-	clusterRoleBindingName         = "fluentbit"
-	clusterRoleName                = "fluentbit"
-	fluentBitSecretConfigName      = "fluentbit"
-	fluentbitDaemonSetName         = "fluentbit"
-	fluentbitPodSecurityPolicyName = "fluentbit"
-	fluentbitServiceName           = "fluentbit"
-	containerName                  = "fluent-bit"
+	defaultServiceAccountName = "fluentbit"
+	clusterRoleBindingName    = "fluentbit"
+	clusterRoleName           = "fluentbit"
+	fluentBitSecretConfigName = "fluentbit"
+	fluentbitDaemonSetName    = "fluentbit"
+	fluentbitServiceName      = "fluentbit"
+	containerName             = "fluent-bit"
 )
 
 func NodeAgentFluentbitDefaults(userDefined v1beta1.NodeAgentConfig) (*v1beta1.NodeAgentConfig, error) {
@@ -356,9 +355,6 @@ func (n *nodeAgentInstance) Reconcile(ctx context.Context) (*reconcile.Result, e
 		n.daemonSet,
 		n.serviceMetrics,
 	}
-	if resources.PSPEnabled {
-		objects = append(objects, n.clusterPodSecurityPolicy, n.pspClusterRole, n.pspClusterRoleBinding)
-	}
 	if resources.IsSupported(ctx, resources.ServiceMonitorKey) {
 		objects = append(objects, n.monitorServiceMetrics)
 	}
@@ -0,0 +1,93 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ManagedAnnotation marks a namespace whose Pod Security Admission labels are set by the operator,
+// so they can be removed once the pod security standard is cleared from the logging resource.
+const ManagedAnnotation = "logging.banzaicloud.io/pod-security-labels"
+
+var labelKeys = []string{EnforceLabel, EnforceVersionLabel, AuditLabel, AuditVersionLabel, WarnLabel, WarnVersionLabel}
+
+// NewNamespaceReconciler puts the Pod Security Admission labels on the namespace.
+// Without labels the previously set ones are removed, labels not set by the operator are left untouched.
+// The namespace itself is never created or deleted.
+func NewNamespaceReconciler(c client.Client, namespace string, labels map[string]string) func(ctx context.Context) (*reconcile.Result, error) {
+	return func(ctx context.Context) (*reconcile.Result, error) {
+		var ns corev1.Namespace
+		if err := c.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "getting namespace", "namespace", namespace)
+		}
+
+		patchBase := client.MergeFrom(ns.DeepCopy())
+		if !applyLabels(&ns, labels) {
+			return nil, nil
+		}
+		if err := c.Patch(ctx, &ns, patchBase); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "patching pod security labels of namespace", "namespace", namespace)
+		}
+		return nil, nil
+	}
+}
+
+// applyLabels sets the labels on the namespace, or removes the managed ones if there are no labels, and reports whether it changed.
+func applyLabels(ns *corev1.Namespace, labels map[string]string) bool {
+	_, managed := ns.Annotations[ManagedAnnotation]
+	if len(labels) == 0 {
+		if !managed {
+			return false
+		}
+		for _, key := range labelKeys {
+			delete(ns.Labels, key)
+		}
+		delete(ns.Annotations, ManagedAnnotation)
+		return true
+	}
+
+	changed := false
+	if !managed {
+		if ns.Annotations == nil {
+			ns.Annotations = make(map[string]string)
+		}
+		ns.Annotations[ManagedAnnotation] = "true"
+		changed = true
+	}
+	for _, key := range labelKeys {
+		if _, ok := labels[key]; !ok && managed {
+			if _, ok := ns.Labels[key]; ok {
+				delete(ns.Labels, key)
+				changed = true
+			}
+		}
+	}
+	for key, value := range labels {
+		if ns.Labels[key] != value {
+			if ns.Labels == nil {
+				ns.Labels = make(map[string]string)
+			}
+			ns.Labels[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,85 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity
+
+import (
+	util "github.com/cisco-open/operator-tools/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// AgentLabels selects the fluent-bit pods of the logging, both the FluentbitAgent and the NodeAgent ones
+func AgentLabels(loggingName string) map[string]string {
+	return util.MergeLabels(
+		map[string]string{"app.kubernetes.io/name": "fluentbit"},
+		v1beta1.GenerateLoggingRefLabels(loggingName),
+	)
+}
+
+// AggregatorNetworkPolicySpec restricts the traffic of the aggregator pods.
+// The records port only accepts connections from the fluent-bit agents of the logging and the additional peers,
+// the open ports (extra inputs, metrics) accept connections from anywhere.
+// Egress is limited to DNS lookups and the endpoints listed in the policy.
+func AggregatorNetworkPolicySpec(podLabels map[string]string, loggingName string, recordsPort int32, openPorts []corev1.ContainerPort, policy v1beta1.AggregatorNetworkPolicy) networkingv1.NetworkPolicySpec {
+	tcp := corev1.ProtocolTCP
+	udp := corev1.ProtocolUDP
+	dns := intstr.FromInt(53)
+
+	from := append([]networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{MatchLabels: AgentLabels(loggingName)}},
+	}, policy.AdditionalIngressFrom...)
+	records := intstr.FromInt(int(recordsPort))
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &tcp, Port: &records},
+				{Protocol: &udp, Port: &records},
+			},
+			From: from,
+		},
+	}
+	if len(openPorts) > 0 {
+		var ports []networkingv1.NetworkPolicyPort
+		for _, p := range openPorts {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			port := intstr.FromInt(int(p.ContainerPort))
+			ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+		}
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{Ports: ports})
+	}
+
+	egress := append([]networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dns},
+				{Protocol: &tcp, Port: &dns},
+			},
+		},
+	}, policy.Egress...)
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: podLabels},
+		Ingress:     ingress,
+		Egress:      egress,
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity
+
+import (
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// Label keys of Pod Security Admission, see https://kubernetes.io/docs/concepts/security/pod-security-admission/
+const (
+	EnforceLabel        = "pod-security.kubernetes.io/enforce"
+	EnforceVersionLabel = "pod-security.kubernetes.io/enforce-version"
+	AuditLabel          = "pod-security.kubernetes.io/audit"
+	AuditVersionLabel   = "pod-security.kubernetes.io/audit-version"
+	WarnLabel           = "pod-security.kubernetes.io/warn"
+	WarnVersionLabel    = "pod-security.kubernetes.io/warn-version"
+
+	LatestVersion = "latest"
+)
+
+var levelOrder = map[string]int{
+	v1beta1.PodSecurityStandardPrivileged: 0,
+	v1beta1.PodSecurityStandardBaseline:   1,
+	v1beta1.PodSecurityStandardRestricted: 2,
+}
+
+// capabilities that can be added to the containers at the baseline level
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+var baselineSELinuxTypes = map[string]bool{
+	"":                 true,
+	"container_t":      true,
+	"container_init_t": true,
+	"container_kvm_t":  true,
+}
+
+var baselineSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+}
+
+// NamespaceLabels returns the Pod Security Admission labels of a namespace running workloads of the given levels,
+// where an empty level stands for a workload without a declared level.
+// The namespace enforces the least restrictive level of the workloads, so none of them is rejected,
+// while audit and warn use the most restrictive declared level to surface the pods not complying with it.
+// Returns nil if none of the workloads declare a level, leaving the labels of the namespace to the user.
+func NamespaceLabels(levels []string) map[string]string {
+	enforce, warn := "", ""
+	for _, level := range levels {
+		if level == "" {
+			level = v1beta1.PodSecurityStandardPrivileged
+		} else if warn == "" || levelOrder[level] > levelOrder[warn] {
+			warn = level
+		}
+		if enforce == "" || levelOrder[level] < levelOrder[enforce] {
+			enforce = level
+		}
+	}
+	if warn == "" {
+		return nil
+	}
+	return map[string]string{
+		EnforceLabel:        enforce,
+		EnforceVersionLabel: LatestVersion,
+		AuditLabel:          warn,
+		AuditVersionLabel:   LatestVersion,
+		WarnLabel:           warn,
+		WarnVersionLabel:    LatestVersion,
+	}
+}
+
+// Validate checks the pod spec against the given Pod Security Standards level
+func Validate(level string, spec corev1.PodSpec) error {
+	if level == "" || level == v1beta1.PodSecurityStandardPrivileged {
+		return nil
+	}
+	if _, ok := levelOrder[level]; !ok {
+		return errors.Errorf("unknown pod security standard %q", level)
+	}
+	violations := baselineViolations(spec)
+	if level == v1beta1.PodSecurityStandardRestricted {
+		violations = append(violations, restrictedViolations(spec)...)
+	}
+	if len(violations) > 0 {
+		return errors.Errorf("pod violates the %s pod security standard: %s", level, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+func containers(spec corev1.PodSpec) []corev1.Container {
+	return append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+}
+
+func baselineViolations(spec corev1.PodSpec) (violations []string) {
+	if spec.HostNetwork {
+		violations = append(violations, "hostNetwork is set")
+	}
+	if spec.HostPID {
+		violations = append(violations, "hostPID is set")
+	}
+	if spec.HostIPC {
+		violations = append(violations, "hostIPC is set")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			violations = append(violations, fmt.Sprintf("volume %s uses a hostPath", volume.Name))
+		}
+	}
+	if psc := spec.SecurityContext; psc != nil {
+		violations = append(violations, seLinuxViolations("pod", psc.SELinuxOptions)...)
+		if psc.SeccompProfile != nil && psc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			violations = append(violations, "pod seccomp profile is Unconfined")
+		}
+		for _, sysctl := range psc.Sysctls {
+			if !baselineSysctls[sysctl.Name] {
+				violations = append(violations, fmt.Sprintf("sysctl %s is not allowed", sysctl.Name))
+			}
+		}
+	}
+	for _, c := range containers(spec) {
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				violations = append(violations, fmt.Sprintf("container %s uses host port %d", c.Name, port.HostPort))
+			}
+		}
+		sc := c.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			violations = append(violations, fmt.Sprintf("container %s is privileged", c.Name))
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					violations = append(violations, fmt.Sprintf("container %s adds capability %s", c.Name, capability))
+				}
+			}
+		}
+		violations = append(violations, seLinuxViolations("container "+c.Name, sc.SELinuxOptions)...)
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			violations = append(violations, fmt.Sprintf("container %s uses the %s proc mount", c.Name, *sc.ProcMount))
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			violations = append(violations, fmt.Sprintf("container %s seccomp profile is Unconfined", c.Name))
+		}
+	}
+	return
+}
+
+func seLinuxViolations(subject string, options *corev1.SELinuxOptions) []string {
+	if options == nil {
+		return nil
+	}
+	var violations []string
+	if !baselineSELinuxTypes[options.Type] {
+		violations = append(violations, fmt.Sprintf("%s uses SELinux type %s", subject, options.Type))
+	}
+	if options.User != "" || options.Role != "" {
+		violations = append(violations, fmt.Sprintf("%s sets a custom SELinux user or role", subject))
+	}
+	return violations
+}
+
+func restrictedViolations(spec corev1.PodSpec) (violations []string) {
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil, volume.CSI != nil, volume.DownwardAPI != nil, volume.EmptyDir != nil,
+			volume.Ephemeral != nil, volume.PersistentVolumeClaim != nil, volume.Projected != nil, volume.Secret != nil:
+		case volume.HostPath != nil:
+			// reported at the baseline level
+		default:
+			violations = append(violations, fmt.Sprintf("volume %s has a restricted volume type", volume.Name))
+		}
+	}
+
+	psc := spec.SecurityContext
+	if psc == nil {
+		psc = &corev1.PodSecurityContext{}
+	}
+	if psc.RunAsUser != nil && *psc.RunAsUser == 0 {
+		violations = append(violations, "pod runs as root user")
+	}
+	for _, c := range containers(spec) {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, fmt.Sprintf("container %s has to set allowPrivilegeEscalation to false", c.Name))
+		}
+		runAsNonRoot := psc.RunAsNonRoot
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			violations = append(violations, fmt.Sprintf("container %s has to set runAsNonRoot to true", c.Name))
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			violations = append(violations, fmt.Sprintf("container %s runs as root user", c.Name))
+		}
+		seccomp := psc.SeccompProfile
+		if sc.SeccompProfile != nil {
+			seccomp = sc.SeccompProfile
+		}
+		if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault && seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+			violations = append(violations, fmt.Sprintf("container %s has to use the RuntimeDefault or a Localhost seccomp profile", c.Name))
+		}
+		if !dropsAllCapabilities(sc.Capabilities) {
+			violations = append(violations, fmt.Sprintf("container %s has to drop ALL capabilities", c.Name))
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" && baselineCapabilities[capability] {
+					violations = append(violations, fmt.Sprintf("container %s adds capability %s", c.Name, capability))
+				}
+			}
+		}
+	}
+	return
+}
+
+func dropsAllCapabilities(capabilities *corev1.Capabilities) bool {
+	if capabilities == nil {
+		return false
+	}
+	for _, capability := range capabilities.Drop {
+		if capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity
+
+import (
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestNamespaceLabels(t *testing.T) {
+	require.Nil(t, NamespaceLabels(nil))
+	require.Nil(t, NamespaceLabels([]string{"", ""}))
+
+	labels := NamespaceLabels([]string{v1beta1.PodSecurityStandardRestricted, v1beta1.PodSecurityStandardBaseline})
+	require.Equal(t, v1beta1.PodSecurityStandardBaseline, labels[EnforceLabel])
+	require.Equal(t, v1beta1.PodSecurityStandardRestricted, labels[WarnLabel])
+	require.Equal(t, v1beta1.PodSecurityStandardRestricted, labels[AuditLabel])
+	require.Equal(t, LatestVersion, labels[EnforceVersionLabel])
+
+	labels = NamespaceLabels([]string{v1beta1.PodSecurityStandardRestricted, ""})
+	require.Equal(t, v1beta1.PodSecurityStandardPrivileged, labels[EnforceLabel])
+	require.Equal(t, v1beta1.PodSecurityStandardRestricted, labels[WarnLabel])
+}
+
+func TestValidate(t *testing.T) {
+	restricted := corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   utils.BoolPointer(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "config"}}},
+		},
+		Containers: []corev1.Container{
+			{
+				Name: "fluentd",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: utils.BoolPointer(false),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			},
+		},
+	}
+	require.NoError(t, Validate(v1beta1.PodSecurityStandardRestricted, restricted))
+
+	baseline := *restricted.DeepCopy()
+	baseline.Containers[0].SecurityContext = nil
+	require.NoError(t, Validate(v1beta1.PodSecurityStandardBaseline, baseline))
+	err := Validate(v1beta1.PodSecurityStandardRestricted, baseline)
+	require.ErrorContains(t, err, "container fluentd has to set allowPrivilegeEscalation to false")
+	require.ErrorContains(t, err, "container fluentd has to drop ALL capabilities")
+
+	privileged := *baseline.DeepCopy()
+	privileged.Volumes = append(privileged.Volumes, corev1.Volume{
+		Name:         "varlog",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}},
+	})
+	privileged.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: utils.BoolPointer(true)}
+	require.NoError(t, Validate(v1beta1.PodSecurityStandardPrivileged, privileged))
+	require.EqualError(t, Validate(v1beta1.PodSecurityStandardBaseline, privileged),
+		"pod violates the baseline pod security standard: volume varlog uses a hostPath; container fluentd is privileged")
+}
+
+func TestApplyLabels(t *testing.T) {
+	ns := corev1.Namespace{}
+	ns.Labels = map[string]string{"team": "logging"}
+
+	require.False(t, applyLabels(&ns, nil), "labels not set by the operator are left untouched")
+
+	labels := NamespaceLabels([]string{v1beta1.PodSecurityStandardBaseline})
+	require.True(t, applyLabels(&ns, labels))
+	require.Equal(t, v1beta1.PodSecurityStandardBaseline, ns.Labels[EnforceLabel])
+	require.Contains(t, ns.Annotations, ManagedAnnotation)
+	require.False(t, applyLabels(&ns, labels))
+
+	require.True(t, applyLabels(&ns, nil))
+	require.Equal(t, map[string]string{"team": "logging"}, ns.Labels)
+	require.NotContains(t, ns.Annotations, ManagedAnnotation)
+	require.False(t, applyLabels(&ns, nil))
+}
@@ -0,0 +1,89 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretbackend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// KVReader reads the fields of a secret stored in a key-value store
+type KVReader interface {
+	Read(path string) (map[string]string, error)
+}
+
+// NewKVClient returns a reader for a store with a Vault KV version 2 compatible HTTP API
+func NewKVClient(backend v1beta1.KVSecretBackend, token string) KVReader {
+	mount := backend.Mount
+	if mount == "" {
+		mount = DefaultKVMount
+	}
+	return &kvClient{
+		address: strings.TrimSuffix(backend.Address, "/"),
+		mount:   strings.Trim(mount, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type kvClient struct {
+	address string
+	mount   string
+	token   string
+	client  *http.Client
+}
+
+func (c *kvClient) Read(path string) (map[string]string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/data/%s", c.address, c.mount, strings.TrimPrefix(path, "/")), nil)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kv request")
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.WrapIf(err, "kv request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("kv request failed with status %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.WrapIf(err, "failed to decode kv response")
+	}
+	res := make(map[string]string, len(body.Data.Data))
+	for k, v := range body.Data.Data {
+		if s, ok := v.(string); ok {
+			res[k] = s
+		} else {
+			res[k] = fmt.Sprint(v)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,197 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretbackend
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+const (
+	SchemeCSI                 = "csi"
+	SchemeServiceAccountToken = "serviceaccounttoken"
+	SchemeKV                  = "kv"
+
+	DefaultCSIDriver       = "secrets-store.csi.k8s.io"
+	DefaultKVMount         = "secret"
+	serviceAccountTokenKey = "token"
+)
+
+// ParseRef splits a backend reference of the form <scheme>:<name>.
+// Kubernetes Secret names cannot contain a colon, so ok is false for references to real Secrets.
+func ParseRef(name string) (scheme string, ref string, ok bool) {
+	return strings.Cut(name, ":")
+}
+
+// Resolver resolves references to the secret backends configured for a logging
+type Resolver struct {
+	Client           client.Reader
+	Backends         *v1beta1.SecretBackends
+	ControlNamespace string
+	// MountPath is where the volume based backends are mounted in the aggregator
+	MountPath string
+
+	kv KVReader
+}
+
+// LoaderFor wraps a kubernetes secret loader bound to the given namespace so that it resolves backend references as well.
+// Values of mounted references read by the operator are appended to secrets and are available under outputSecretPath.
+func (r *Resolver) LoaderFor(fallback secret.SecretLoader, namespace string, outputSecretPath string, secrets *secret.MountSecrets) secret.SecretLoader {
+	return &loader{
+		SecretLoader:     fallback,
+		resolver:         r,
+		namespace:        namespace,
+		outputSecretPath: outputSecretPath,
+		secrets:          secrets,
+	}
+}
+
+type loader struct {
+	secret.SecretLoader
+	resolver         *Resolver
+	namespace        string
+	outputSecretPath string
+	secrets          *secret.MountSecrets
+}
+
+func (l *loader) Load(s *secret.Secret) (string, error) {
+	if s.Value == "" {
+		if s.MountFrom != nil && s.MountFrom.SecretKeyRef != nil {
+			if scheme, ref, ok := ParseRef(s.MountFrom.SecretKeyRef.Name); ok {
+				return l.mount(scheme, ref, s.MountFrom.SecretKeyRef.Key)
+			}
+		}
+		if s.ValueFrom != nil && s.ValueFrom.SecretKeyRef != nil {
+			if scheme, ref, ok := ParseRef(s.ValueFrom.SecretKeyRef.Name); ok {
+				return l.value(scheme, ref, s.ValueFrom.SecretKeyRef.Key)
+			}
+		}
+	}
+	return l.SecretLoader.Load(s)
+}
+
+func (l *loader) mount(scheme, ref, key string) (string, error) {
+	backends := l.resolver.Backends
+	if backends == nil {
+		return "", errors.Errorf("no secret backends configured for reference %s:%s", scheme, ref)
+	}
+	switch scheme {
+	case SchemeCSI:
+		for _, b := range backends.CSI {
+			if b.Name == ref {
+				if !namespaceAllowed(b.Namespaces, l.namespace) {
+					return "", errors.Errorf("csi secret backend %s is not allowed in namespace %s", ref, l.namespace)
+				}
+				return path.Join(l.resolver.MountPath, SchemeCSI, ref, key), nil
+			}
+		}
+		return "", errors.Errorf("csi secret backend %s not found", ref)
+	case SchemeServiceAccountToken:
+		for _, b := range backends.ServiceAccountTokens {
+			if b.Name == ref {
+				if !namespaceAllowed(b.Namespaces, l.namespace) {
+					return "", errors.Errorf("service account token secret backend %s is not allowed in namespace %s", ref, l.namespace)
+				}
+				return path.Join(l.resolver.MountPath, SchemeServiceAccountToken, ref, serviceAccountTokenKey), nil
+			}
+		}
+		return "", errors.Errorf("service account token secret backend %s not found", ref)
+	case SchemeKV:
+		value, err := l.value(scheme, ref, key)
+		if err != nil {
+			return "", err
+		}
+		mappedKey := fmt.Sprintf("%s-%s-%s-%s", SchemeKV, l.namespace, strings.ReplaceAll(ref, "/", "-"), key)
+		*l.secrets = append(*l.secrets, secret.MountSecret{
+			Namespace: l.namespace,
+			Name:      scheme + ":" + ref,
+			Key:       key,
+			MappedKey: mappedKey,
+			Value:     []byte(value),
+		})
+		return l.outputSecretPath + "/" + mappedKey, nil
+	default:
+		return "", errors.Errorf("unknown secret backend %q", scheme)
+	}
+}
+
+func (l *loader) value(scheme, ref, key string) (string, error) {
+	switch scheme {
+	case SchemeKV:
+		kv, err := l.resolver.kvReader()
+		if err != nil {
+			return "", err
+		}
+		// paths are relative to the namespace of the referencing resource to keep tenants apart,
+		// so the ref must stay a single path element that cannot escape it
+		if clean := path.Clean(ref); clean != ref || path.IsAbs(clean) || strings.Contains(clean, "/") || clean == "." || clean == ".." {
+			return "", errors.Errorf("invalid kv secret reference %q, it must be a plain name within namespace %s", ref, l.namespace)
+		}
+		data, err := kv.Read(path.Join(l.namespace, ref))
+		if err != nil {
+			return "", errors.WrapIff(err, "failed to read kv secret %s in namespace %s", ref, l.namespace)
+		}
+		value, ok := data[key]
+		if !ok {
+			return "", errors.Errorf("key %q not found in kv secret %s in namespace %s", key, ref, l.namespace)
+		}
+		return value, nil
+	case SchemeCSI, SchemeServiceAccountToken:
+		return "", errors.Errorf("%s secret backend can only be used with mountFrom", scheme)
+	default:
+		return "", errors.Errorf("unknown secret backend %q", scheme)
+	}
+}
+
+func (r *Resolver) kvReader() (KVReader, error) {
+	if r.kv != nil {
+		return r.kv, nil
+	}
+	if r.Backends == nil || r.Backends.KV == nil {
+		return nil, errors.New("no kv secret backend configured")
+	}
+	var token string
+	if ref := r.Backends.KV.TokenSecret; ref != nil {
+		tokenSecret := &corev1.Secret{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.ControlNamespace, Name: ref.Name}, tokenSecret); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to load kv token secret", "secret", ref.Name, "namespace", r.ControlNamespace)
+		}
+		token = string(tokenSecret.Data[ref.Key])
+	}
+	r.kv = NewKVClient(*r.Backends.KV, token)
+	return r.kv, nil
+}
+
+func namespaceAllowed(namespaces []string, namespace string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
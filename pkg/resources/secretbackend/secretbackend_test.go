@@ -0,0 +1,149 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretbackend
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+type fallbackLoader struct{}
+
+func (fallbackLoader) Load(s *secret.Secret) (string, error) {
+	return "kubernetes", nil
+}
+
+func ref(name, key string) *secret.ValueFrom {
+	return &secret.ValueFrom{SecretKeyRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}}
+}
+
+func TestLoader(t *testing.T) {
+	kv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the secret of the other namespace must never be reached through the tenant's loader
+		if (r.URL.Path != "/v1/secret/data/tenant/elastic" && r.URL.Path != "/v1/secret/data/other/elastic") || r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"password":"s3cr3t"}}}`)
+	}))
+	defer kv.Close()
+
+	resolver := &Resolver{
+		Backends: &v1beta1.SecretBackends{
+			CSI: []v1beta1.CSISecretBackend{
+				{Name: "vault", Namespaces: []string{"tenant"}},
+			},
+			ServiceAccountTokens: []v1beta1.ServiceAccountTokenSecretBackend{
+				{Name: "aws", Audience: "sts.amazonaws.com"},
+			},
+			KV: &v1beta1.KVSecretBackend{Address: kv.URL},
+		},
+		MountPath: "/fluentd/secret-backends",
+	}
+	resolver.kv = NewKVClient(*resolver.Backends.KV, "root")
+
+	var mounted secret.MountSecrets
+	loader := resolver.LoaderFor(fallbackLoader{}, "tenant", "/fluentd/secret", &mounted)
+
+	testCases := map[string]struct {
+		secret  secret.Secret
+		want    string
+		wantErr bool
+	}{
+		"kubernetes secret": {
+			secret: secret.Secret{ValueFrom: ref("creds", "password")},
+			want:   "kubernetes",
+		},
+		"csi mount": {
+			secret: secret.Secret{MountFrom: ref("csi:vault", "password")},
+			want:   "/fluentd/secret-backends/csi/vault/password",
+		},
+		"csi value": {
+			secret:  secret.Secret{ValueFrom: ref("csi:vault", "password")},
+			wantErr: true,
+		},
+		"unknown csi volume": {
+			secret:  secret.Secret{MountFrom: ref("csi:other", "password")},
+			wantErr: true,
+		},
+		"service account token mount": {
+			secret: secret.Secret{MountFrom: ref("serviceaccounttoken:aws", "")},
+			want:   "/fluentd/secret-backends/serviceaccounttoken/aws/token",
+		},
+		"kv value": {
+			secret: secret.Secret{ValueFrom: ref("kv:elastic", "password")},
+			want:   "s3cr3t",
+		},
+		"kv mount": {
+			secret: secret.Secret{MountFrom: ref("kv:elastic", "password")},
+			want:   "/fluentd/secret/kv-tenant-elastic-password",
+		},
+		"kv missing key": {
+			secret:  secret.Secret{ValueFrom: ref("kv:elastic", "username")},
+			wantErr: true,
+		},
+		"kv path traversal": {
+			secret:  secret.Secret{ValueFrom: ref("kv:../other/elastic", "password")},
+			wantErr: true,
+		},
+		"kv absolute path": {
+			secret:  secret.Secret{MountFrom: ref("kv:/other/elastic", "password")},
+			wantErr: true,
+		},
+		"kv nested path": {
+			secret:  secret.Secret{ValueFrom: ref("kv:elastic/../../other/elastic", "password")},
+			wantErr: true,
+		},
+		"kv parent": {
+			secret:  secret.Secret{ValueFrom: ref("kv:..", "password")},
+			wantErr: true,
+		},
+		"unknown backend": {
+			secret:  secret.Secret{ValueFrom: ref("foo:bar", "baz")},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			got, err := loader.Load(&testCase.secret)
+			if testCase.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.want, got)
+		})
+	}
+
+	require.Len(t, mounted, 1)
+	require.Equal(t, "kv-tenant-elastic-password", mounted[0].MappedKey)
+	require.Equal(t, []byte("s3cr3t"), mounted[0].Value)
+
+	// the csi volume is restricted to the tenant namespace
+	_, err := resolver.LoaderFor(fallbackLoader{}, "other", "/fluentd/secret", &mounted).Load(&secret.Secret{MountFrom: ref("csi:vault", "password")})
+	require.Error(t, err)
+}
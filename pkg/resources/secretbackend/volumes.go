@@ -0,0 +1,87 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretbackend
+
+import (
+	"path"
+
+	"github.com/cisco-open/operator-tools/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/kubetool"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// ApplyVolumes mounts the volume based secret backends into the given container under mountPath
+func ApplyVolumes(backends *v1beta1.SecretBackends, containerName string, mountPath string, spec *corev1.PodSpec) {
+	if backends == nil {
+		return
+	}
+	container := kubetool.FindContainerByName(spec.Containers, containerName)
+	for _, b := range backends.CSI {
+		driver := b.Driver
+		if driver == "" {
+			driver = DefaultCSIDriver
+		}
+		name := "secret-backend-csi-" + b.Name
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				CSI: &corev1.CSIVolumeSource{
+					Driver:           driver,
+					ReadOnly:         utils.BoolPointer(true),
+					VolumeAttributes: b.VolumeAttributes,
+				},
+			},
+		})
+		if container != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: path.Join(mountPath, SchemeCSI, b.Name),
+				ReadOnly:  true,
+			})
+		}
+	}
+	for _, b := range backends.ServiceAccountTokens {
+		expiration := b.ExpirationSeconds
+		if expiration == nil {
+			expiration = utils.IntPointer64(3600)
+		}
+		name := "secret-backend-sa-token-" + b.Name
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          b.Audience,
+								ExpirationSeconds: expiration,
+								Path:              serviceAccountTokenKey,
+							},
+						},
+					},
+				},
+			},
+		})
+		if container != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: path.Join(mountPath, SchemeServiceAccountToken, b.Name),
+				ReadOnly:  true,
+			})
+		}
+	}
+}
@@ -17,12 +17,12 @@ package syslogng
 import (
 	"context"
 	"fmt"
-	"hash/fnv"
-	"io"
 
 	"emperror.dev/errors"
 	"github.com/cisco-open/operator-tools/pkg/merge"
 	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -35,14 +35,12 @@ type ConfigCheckResult struct {
 	Valid   bool
 	Ready   bool
 	Message string
+	// Output of the failed config check pod
+	Output string
 }
 
 func (r *Reconciler) configHash() (string, error) {
-	hasher := fnv.New32()
-	if _, err := io.WriteString(hasher, r.config); err != nil {
-		return "", errors.WrapIf(err, "failed to calculate hash for the configmap data")
-	}
-	return fmt.Sprintf("%x", hasher.Sum32()), nil
+	return configcheck.ConfigHash(r.config, r.secrets)
 }
 
 func (r *Reconciler) configCheck(ctx context.Context) (*ConfigCheckResult, error) {
@@ -76,6 +74,9 @@ func (r *Reconciler) configCheck(ctx context.Context) (*ConfigCheckResult, error
 		return nil, errors.WrapIff(err, "failed to create resource description for config check pod %s", hashKey)
 	}
 	configcheck.WithHashLabel(pod, hashKey)
+	if err := podsecurity.Validate(r.Logging.Spec.SyslogNGSpec.PodSecurityStandard, pod.Spec); err != nil {
+		return nil, errors.WrapIf(err, "syslog-ng config check pod")
+	}
 
 	existingPods := &corev1.PodList{}
 	err = r.Client.List(ctx, existingPods, client.MatchingLabels(pod.Labels))
@@ -116,8 +117,9 @@ func (r *Reconciler) configCheck(ctx context.Context) (*ConfigCheckResult, error
 			return &ConfigCheckResult{}, nil
 		case corev1.PodFailed:
 			return &ConfigCheckResult{
-				Ready: true,
-				Valid: false,
+				Ready:  true,
+				Valid:  false,
+				Output: configcheck.FailureOutput(pod),
 			}, nil
 		case corev1.PodUnknown:
 			fallthrough
@@ -188,6 +190,8 @@ func (r *Reconciler) newCheckPod(hashKey string) (*corev1.Pod, error) {
 					Name:            "syslog-ng",
 					Image:           v1beta1.RepositoryWithTag(syslogngImageRepository, syslogngImageTag),
 					ImagePullPolicy: corev1.PullIfNotPresent,
+					// the tail of the logs ends up in the pod status, where the operator picks it up as the failure output
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 					Args: []string{
 						"--cfgfile=" + configDir + "/" + configKey,
 						"-s",
@@ -229,11 +233,14 @@ func (r *Reconciler) newCheckPod(hashKey string) (*corev1.Pod, error) {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, tlsVolume)
 		volumeMount := corev1.VolumeMount{
 			Name:      "syslog-ng-tls",
-			MountPath: "/syslog-ng/tls/",
+			MountPath: TLSPath,
 		}
 		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, volumeMount)
 	}
 
+	// the config may reference the same secret backend volumes as the live statefulset
+	secretbackend.ApplyVolumes(r.Logging.Spec.SecretBackends, ContainerName, SecretBackendsPath, &pod.Spec)
+
 	err := merge.Merge(&pod.Spec, r.Logging.Spec.SyslogNGSpec.ConfigCheckPodOverrides)
 
 	return pod, err
@@ -46,3 +46,8 @@ func (p *DataProvider) GetReplicaCount(ctx context.Context) (*int32, error) {
 	}
 	return sts.Spec.Replicas, nil
 }
+
+// GetShardNamespaces returns nil, syslog-ng can't be sharded
+func (p *DataProvider) GetShardNamespaces() map[int32][]string {
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogng
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+)
+
+func (r *Reconciler) networkPolicy() (runtime.Object, reconciler.DesiredState, error) {
+	desired := &networkingv1.NetworkPolicy{
+		ObjectMeta: r.SyslogNGObjectMeta(NetworkPolicyName, ComponentSyslogNG),
+	}
+	spec := r.Logging.Spec.SyslogNGSpec
+	if spec.NetworkPolicy == nil {
+		return desired, reconciler.StateAbsent, nil
+	}
+
+	openPorts := sourceContainerPorts(spec.Sources)
+	if spec.Metrics != nil {
+		openPorts = append(openPorts, corev1.ContainerPort{
+			Name:          metricsPortName,
+			ContainerPort: metricsPortNumber,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	if spec.BufferVolumeMetrics != nil {
+		openPorts = append(openPorts, generatePortsBufferVolumeMetrics(spec)...)
+	}
+	desired.Spec = podsecurity.AggregatorNetworkPolicySpec(r.Logging.GetSyslogNGLabels(ComponentSyslogNG), r.Logging.Name, ServicePort, openPorts, *spec.NetworkPolicy)
+
+	return desired, reconciler.StatePresent, nil
+}
@@ -22,13 +22,15 @@ import (
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 )
 
 func (r *Reconciler) service() (runtime.Object, reconciler.DesiredState, error) {
 	desired := &corev1.Service{
 		ObjectMeta: r.SyslogNGObjectMeta(ServiceName, ComponentSyslogNG),
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
+			Ports: r.sourceServicePorts([]corev1.ServicePort{
 				{
 					Name:       "tcp-syslog-ng",
 					Protocol:   corev1.ProtocolTCP,
@@ -41,7 +43,7 @@ func (r *Reconciler) service() (runtime.Object, reconciler.DesiredState, error)
 					Port:       514,
 					TargetPort: intstr.IntOrString{IntVal: 514},
 				},
-			},
+			}),
 			Selector: r.Logging.GetSyslogNGLabels(ComponentSyslogNG),
 			Type:     corev1.ServiceTypeClusterIP,
 		},
@@ -184,7 +186,7 @@ func (r *Reconciler) headlessService() (runtime.Object, reconciler.DesiredState,
 	desired := &corev1.Service{
 		ObjectMeta: r.SyslogNGObjectMeta(ServiceName+"-headless", ComponentSyslogNG),
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
+			Ports: r.sourceServicePorts([]corev1.ServicePort{
 				{
 					Name:       "tcp-syslog-ng",
 					Protocol:   corev1.ProtocolTCP,
@@ -197,7 +199,7 @@ func (r *Reconciler) headlessService() (runtime.Object, reconciler.DesiredState,
 					Port:       514,
 					TargetPort: intstr.IntOrString{IntVal: 514},
 				},
-			},
+			}),
 			Selector:  r.Logging.GetSyslogNGLabels(ComponentSyslogNG),
 			Type:      corev1.ServiceTypeClusterIP,
 			ClusterIP: corev1.ClusterIPNone,
@@ -205,3 +207,34 @@ func (r *Reconciler) headlessService() (runtime.Object, reconciler.DesiredState,
 	}
 	return desired, reconciler.StatePresent, nil
 }
+
+// sourceServicePorts appends the ports of the additional sources, unless the port is already exposed with the same protocol
+func (r *Reconciler) sourceServicePorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	for _, src := range r.Logging.Spec.SyslogNGSpec.Sources {
+		protocol := sourceProtocol(src)
+		exposed := false
+		for _, p := range ports {
+			if p.Port == src.Port && p.Protocol == protocol {
+				exposed = true
+				break
+			}
+		}
+		if exposed {
+			continue
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       src.Name,
+			Protocol:   protocol,
+			Port:       src.Port,
+			TargetPort: intstr.IntOrString{IntVal: src.Port},
+		})
+	}
+	return ports
+}
+
+func sourceProtocol(src v1beta1.SyslogNGSource) corev1.Protocol {
+	if src.Transport() == "udp" {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
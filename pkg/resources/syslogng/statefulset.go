@@ -29,7 +29,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/kube-logging/logging-operator/pkg/resources/configcheck"
 	"github.com/kube-logging/logging-operator/pkg/resources/kubetool"
+	"github.com/kube-logging/logging-operator/pkg/resources/podsecurity"
+	"github.com/kube-logging/logging-operator/pkg/resources/secretbackend"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 )
 
@@ -85,6 +88,15 @@ func (r *Reconciler) statefulset() (runtime.Object, reconciler.DesiredState, err
 			buffersVolumeName = name
 		}
 	}
+	secretbackend.ApplyVolumes(r.Logging.Spec.SecretBackends, ContainerName, SecretBackendsPath, &desired.Spec.Template.Spec)
+
+	hash, err := r.configHash()
+	if err != nil {
+		return nil, reconciler.StatePresent, err
+	}
+	// the health status reports the config of the statefulset as live once it has been observed
+	desired.Annotations = util.MergeLabels(desired.Annotations, map[string]string{configcheck.ConfigHashAnnotation: hash})
+
 	syslogngContainer := kubetool.FindContainerByName(desired.Spec.Template.Spec.Containers, ContainerName)
 	if mnt := kubetool.FindVolumeMountByName(syslogngContainer.VolumeMounts, buffersVolumeName); mnt != nil {
 		if !sliceAny(syslogngContainer.Args, func(arg string) bool { return strings.Contains(arg, "--persist-file") }) {
@@ -93,6 +105,10 @@ func (r *Reconciler) statefulset() (runtime.Object, reconciler.DesiredState, err
 		}
 	}
 
+	if err := podsecurity.Validate(r.Logging.Spec.SyslogNGSpec.PodSecurityStandard, desired.Spec.Template.Spec); err != nil {
+		return nil, reconciler.StatePresent, errors.WrapIf(err, "syslog-ng statefulset")
+	}
+
 	return desired, reconciler.StatePresent, nil
 }
 
@@ -101,11 +117,11 @@ func syslogNGContainer(spec *v1beta1.SyslogNGSpec) corev1.Container {
 		Name:            ContainerName,
 		Image:           v1beta1.RepositoryWithTag(syslogngImageRepository, syslogngImageTag),
 		ImagePullPolicy: corev1.PullIfNotPresent,
-		Ports: []corev1.ContainerPort{{
+		Ports: append([]corev1.ContainerPort{{
 			Name:          "syslog-ng-tcp",
 			ContainerPort: ServicePort,
 			Protocol:      corev1.ProtocolTCP,
-		}},
+		}}, sourceContainerPorts(spec.Sources)...),
 		Args: []string{
 			"--cfgfile=" + configDir + "/" + configKey,
 			"--control=" + socketPath,
@@ -140,6 +156,18 @@ func syslogNGContainer(spec *v1beta1.SyslogNGSpec) corev1.Container {
 	}
 }
 
+func sourceContainerPorts(sources []v1beta1.SyslogNGSource) []corev1.ContainerPort {
+	var ports []corev1.ContainerPort
+	for _, src := range sources {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          src.Name,
+			ContainerPort: src.Port,
+			Protocol:      sourceProtocol(src),
+		})
+	}
+	return ports
+}
+
 func generatePortsBufferVolumeMetrics(spec *v1beta1.SyslogNGSpec) []corev1.ContainerPort {
 	port := int32(defaultBufferVolumeMetricsPort)
 	if spec.BufferVolumeMetrics.Port != 0 {
@@ -164,7 +192,7 @@ func generateVolumeMounts(spec *v1beta1.SyslogNGSpec) []corev1.VolumeMount {
 	if spec != nil && spec.TLS.Enabled {
 		res = append(res, corev1.VolumeMount{
 			Name:      tlsVolumeName,
-			MountPath: "/syslog-ng/tls/",
+			MountPath: TLSPath,
 		})
 	}
 	if spec != nil {
@@ -349,14 +377,18 @@ func generateReadinessCheck(spec *v1beta1.SyslogNGSpec) *corev1.Probe {
 }
 
 func configReloadContainer(spec *v1beta1.SyslogNGSpec) corev1.Container {
-	// TODO: ADD TLS reload watch
+	// reload on rotation of the mounted output secrets and the TLS certificates as well
+	watchDirs := []string{configDir, OutputSecretPath}
+	if spec != nil && spec.TLS.Enabled {
+		watchDirs = append(watchDirs, TLSPath)
+	}
 	container := corev1.Container{
 		Name:            "config-reloader",
 		Image:           v1beta1.RepositoryWithTag(configReloaderImageRepository, configReloaderImageTag),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Args: []string{
 			"-cfgjson",
-			generateConfigReloaderConfig(configDir),
+			generateConfigReloaderConfig(watchDirs...),
 		},
 		VolumeMounts: generateVolumeMounts(spec),
 	}
@@ -364,16 +396,15 @@ func configReloadContainer(spec *v1beta1.SyslogNGSpec) corev1.Container {
 	return container
 }
 
-func generateConfigReloaderConfig(configDir string) string {
-	return fmt.Sprintf(`
-	{
-		"events": {
-		  "onFileCreate": {
+func generateConfigReloaderConfig(dirs ...string) string {
+	events := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		events = append(events, fmt.Sprintf(`
 			"%s" : [
 			  {
 				"exec": {
 				  "key": "info",
-				  "command": "echo $(date) config secret changed!"
+				  "command": "echo $(date) %s changed!"
 				}
 			  },
 			  {
@@ -382,11 +413,16 @@ func generateConfigReloaderConfig(configDir string) string {
 					"command": "echo RELOAD | socat - UNIX-CONNECT:%s"
 				}
 			  }
-			]
+			]`, filepath.Join(dir, "..data"), dir, socketPath))
+	}
+	return fmt.Sprintf(`
+	{
+		"events": {
+		  "onFileCreate": {%s
 		  }
 		}
 	  }
-	`, filepath.Join(configDir, "..data"), socketPath)
+	`, strings.Join(events, ","))
 }
 
 func sliceAny[S ~[]E, E any](s S, fn func(E) bool) bool {
@@ -25,7 +25,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,9 +44,12 @@ const (
 	configSecretName                  = "syslog-ng"
 	configKey                         = "syslog-ng.conf"
 	StatefulSetName                   = "syslog-ng"
+	NetworkPolicyName                 = "syslog-ng"
 	outputSecretName                  = "syslog-ng-output"
 	OutputSecretPath                  = "/etc/syslog-ng/secret"
+	SecretBackendsPath                = "/etc/syslog-ng/secret-backends"
 	BufferPath                        = "/buffers"
+	TLSPath                           = "/syslog-ng/tls"
 	serviceAccountName                = "syslog-ng"
 	roleBindingName                   = "syslog-ng"
 	roleName                          = "syslog-ng"
@@ -169,6 +174,16 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 			}
 			if result.Ready {
 				r.Logging.Status.ConfigCheckResults[hash] = result.Valid
+				r.Logging.RecordConfigCheck(v1beta1.ConfigCheckRecord{
+					Component: "syslog-ng",
+					Hash:      hash,
+					Valid:     result.Valid,
+					Time:      metav1.Now(),
+					Output:    result.Output,
+				})
+				if !result.Valid {
+					r.Log.Info("config check failed", "hash", hash, "output", result.Output)
+				}
 				if err := r.Client.Status().Patch(ctx, r.Logging, patchBase); err != nil {
 					return nil, errors.WrapWithDetails(err, "failed to patch status", "logging", r.Logging)
 				} else {
@@ -218,6 +233,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*reconcile.Result, error) {
 		r.headlessService,
 		r.serviceMetrics,
 		r.serviceBufferMetrics,
+		r.networkPolicy,
 	}
 	if resources.IsSupported(ctx, resources.ServiceMonitorKey) {
 		resourceObjects = append(resourceObjects, r.monitorServiceMetrics, r.monitorBufferServiceMetrics)
@@ -283,5 +299,6 @@ func RegisterWatches(builder *builder.Builder) *builder.Builder {
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&batchv1.Job{}).
-		Owns(&corev1.PersistentVolumeClaim{})
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&networkingv1.NetworkPolicy{})
 }
@@ -29,7 +29,11 @@ func (o *Output) ConvertTo(dstRaw conversion.Hub) error {
 	Log.Error(errors.New("unsupported conversion"), "conversion is not supported, spec will be omitted")
 
 	dst.ObjectMeta = o.ObjectMeta
-	dst.Status = v1beta1.OutputStatus(o.Status)
+	dst.Status = v1beta1.OutputStatus{
+		Active:        o.Status.Active,
+		Problems:      o.Status.Problems,
+		ProblemsCount: o.Status.ProblemsCount,
+	}
 
 	return nil
 }
@@ -42,7 +46,11 @@ func (o *Output) ConvertFrom(srcRaw conversion.Hub) error {
 	Log.Error(errors.New("unsupported conversion"), "conversion is not supported, spec will be omitted")
 
 	o.ObjectMeta = src.ObjectMeta
-	o.Status = OutputStatus(src.Status)
+	o.Status = OutputStatus{
+		Active:        src.Status.Active,
+		Problems:      src.Status.Problems,
+		ProblemsCount: src.Status.ProblemsCount,
+	}
 
 	return nil
 }
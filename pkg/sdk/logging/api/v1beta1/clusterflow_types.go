@@ -86,6 +86,24 @@ type ClusterFlowList struct {
 	Items           []ClusterFlow `json:"items"`
 }
 
+// AuditLogsClusterMatch selects the audit events, e.g. to route them to a SIEM ClusterOutput
+func AuditLogsClusterMatch() ClusterMatch {
+	return ClusterMatch{
+		ClusterSelect: &ClusterSelect{
+			ContainerNames: []string{AuditLogsContainerName},
+		},
+	}
+}
+
+// AuditLogsClusterExclude excludes the audit events, e.g. from the ClusterFlows of the application logs
+func AuditLogsClusterExclude() ClusterMatch {
+	return ClusterMatch{
+		ClusterExclude: &ClusterExclude{
+			ContainerNames: []string{AuditLogsContainerName},
+		},
+	}
+}
+
 func init() {
 	SchemeBuilder.Register(&ClusterFlow{}, &ClusterFlowList{})
 }
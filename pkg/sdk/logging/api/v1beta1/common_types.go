@@ -17,6 +17,7 @@ package v1beta1
 import (
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 // +name:"Common"
@@ -80,13 +81,34 @@ type ServiceMonitorConfig struct {
 	TLSConfig          *v1.TLSConfig       `json:"tlsConfig,omitempty"`
 }
 
+// Pod Security Standards levels, see https://kubernetes.io/docs/concepts/security/pod-security-standards/
+const (
+	PodSecurityStandardPrivileged = "privileged"
+	PodSecurityStandardBaseline   = "baseline"
+	PodSecurityStandardRestricted = "restricted"
+)
+
 // Security defines Fluentd, FluentbitAgent deployment security properties
 type Security struct {
-	ServiceAccount               string                     `json:"serviceAccount,omitempty"`
-	RoleBasedAccessControlCreate *bool                      `json:"roleBasedAccessControlCreate,omitempty"`
-	PodSecurityPolicyCreate      bool                       `json:"podSecurityPolicyCreate,omitempty"`
-	SecurityContext              *corev1.SecurityContext    `json:"securityContext,omitempty"`
-	PodSecurityContext           *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	ServiceAccount               string `json:"serviceAccount,omitempty"`
+	RoleBasedAccessControlCreate *bool  `json:"roleBasedAccessControlCreate,omitempty"`
+	// Deprecated: PodSecurityPolicies are removed from Kubernetes 1.25, the field is ignored. Use podSecurityStandard instead.
+	PodSecurityPolicyCreate bool `json:"podSecurityPolicyCreate,omitempty"`
+	// Pod Security Standards level the generated pods have to comply with.
+	// The control namespace is labelled for Pod Security Admission accordingly, see https://kubernetes.io/docs/concepts/security/pod-security-admission/
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	PodSecurityStandard string                     `json:"podSecurityStandard,omitempty"`
+	SecurityContext     *corev1.SecurityContext    `json:"securityContext,omitempty"`
+	PodSecurityContext  *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+}
+
+// AggregatorNetworkPolicy restricts the traffic of the aggregator pods with a NetworkPolicy
+type AggregatorNetworkPolicy struct {
+	// Peers allowed to send records to the aggregator in addition to the fluent-bit agents of the logging
+	AdditionalIngressFrom []networkingv1.NetworkPolicyPeer `json:"additionalIngressFrom,omitempty"`
+	// Endpoints the aggregator is allowed to connect to, typically the destinations of the outputs.
+	// DNS lookups are always allowed.
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
 }
 
 // ReadinessDefaultCheck Enable default readiness checks
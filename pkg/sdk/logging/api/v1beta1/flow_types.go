@@ -41,6 +41,8 @@ type FlowSpec struct {
 	LocalOutputRefs      []string `json:"localOutputRefs,omitempty"`
 	FlowLabel            string   `json:"flowLabel,omitempty"`
 	IncludeLabelInRouter *bool    `json:"includeLabelInRouter,omitempty"`
+	// Outputs in other namespaces, each of them has to be permitted by an OutputGrant in the namespace of the output
+	SharedOutputRefs []OutputReference `json:"sharedOutputRefs,omitempty"`
 }
 
 type Match struct {
@@ -80,11 +82,25 @@ type Filter struct {
 	KubeEventsTimestamp *filter.KubeEventsTimestampConfig `json:"kube_events_timestamp,omitempty"`
 }
 
+const (
+	// FlowConditionSkipped is true when the flow is left out of the rendered config, because it is invalid
+	// and the logging skips invalid resources
+	FlowConditionSkipped = "Skipped"
+
+	// FlowReasonRendered means the flow is part of the rendered config
+	FlowReasonRendered = "Rendered"
+	// FlowReasonRenderFailed means the flow could not be rendered, the message contains the error
+	FlowReasonRenderFailed = "RenderFailed"
+)
+
 // FlowStatus defines the observed state of Flow
 type FlowStatus struct {
 	Active        *bool    `json:"active,omitempty"`
 	Problems      []string `json:"problems,omitempty"`
 	ProblemsCount int      `json:"problemsCount,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
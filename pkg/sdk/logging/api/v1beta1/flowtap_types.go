@@ -0,0 +1,119 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/output"
+)
+
+// +name:"FlowTapSpec"
+// +weight:"200"
+type _hugoFlowTapSpec interface{} //nolint:deadcode,unused
+
+// +name:"FlowTapSpec"
+// +version:"v1beta1"
+// +description:"FlowTapSpec copies the records of a Flow or ClusterFlow to an additional output for debugging"
+type _metaFlowTapSpec interface{} //nolint:deadcode,unused
+
+const (
+	FlowTapKindFlow        = "Flow"
+	FlowTapKindClusterFlow = "ClusterFlow"
+)
+
+// FlowTapSpec copies the records of a Flow or ClusterFlow to an additional output without changing the flow itself.
+// The copy is taken after the filters of the flow. Exactly one of stdout, file and localOutputRef has to be set.
+type FlowTapSpec struct {
+	LoggingRef string `json:"loggingRef,omitempty"`
+	// Flow or ClusterFlow in the namespace of the tap
+	FlowRef FlowTapFlowRef `json:"flowRef"`
+	// Percentage of the records copied to the tap (default: 100)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	SamplingPercentage int32 `json:"samplingPercentage,omitempty"`
+	// Time after which the tap is removed by the operator, counted from its creation
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// Print the records to the log of fluentd
+	Stdout *filter.StdOutFilterConfig `json:"stdout,omitempty"`
+	// Write the records to a file on the buffer volume of fluentd
+	File *output.FileOutputConfig `json:"file,omitempty"`
+	// Send the records to an Output in the namespace of the tap
+	LocalOutputRef string `json:"localOutputRef,omitempty"`
+}
+
+// FlowTapFlowRef points to the flow a tap is attached to
+type FlowTapFlowRef struct {
+	// +kubebuilder:validation:Enum=Flow;ClusterFlow
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// FlowTapStatus defines the observed state of FlowTap
+type FlowTapStatus struct {
+	Active        *bool        `json:"active,omitempty"`
+	ExpiresAt     *metav1.Time `json:"expiresAt,omitempty"`
+	Problems      []string     `json:"problems,omitempty"`
+	ProblemsCount int          `json:"problemsCount,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=logging-all
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.flowRef.kind",description="Kind of the tapped flow"
+// +kubebuilder:printcolumn:name="Flow",type="string",JSONPath=".spec.flowRef.name",description="Name of the tapped flow"
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active",description="Is the tap active?"
+// +kubebuilder:printcolumn:name="Expires",type="string",format="date-time",JSONPath=".status.expiresAt",description="Time the tap is removed"
+// +kubebuilder:printcolumn:name="Problems",type="integer",JSONPath=".status.problemsCount",description="Number of problems"
+
+// FlowTap copies the records of a Flow or ClusterFlow to an additional output for debugging
+type FlowTap struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FlowTapSpec   `json:"spec,omitempty"`
+	Status FlowTapStatus `json:"status,omitempty"`
+}
+
+// ExpiresAt returns the time the tap expires at, or nil if it has no TTL
+func (t *FlowTap) ExpiresAt() *metav1.Time {
+	if t.Spec.TTL == nil {
+		return nil
+	}
+	expiresAt := metav1.NewTime(t.CreationTimestamp.Add(t.Spec.TTL.Duration))
+	return &expiresAt
+}
+
+// Expired returns true if the TTL of the tap has passed
+func (t *FlowTap) Expired(now time.Time) bool {
+	expiresAt := t.ExpiresAt()
+	return expiresAt != nil && !now.Before(expiresAt.Time)
+}
+
+// +kubebuilder:object:root=true
+
+// FlowTapList contains a list of FlowTap
+type FlowTapList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FlowTap `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FlowTap{}, &FlowTapList{})
+}
@@ -19,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cisco-open/operator-tools/pkg/secret"
 	"github.com/cisco-open/operator-tools/pkg/typeoverride"
 	"github.com/cisco-open/operator-tools/pkg/volume"
 	appsv1 "k8s.io/api/apps/v1"
@@ -38,6 +39,8 @@ type _metaFluentbitSpec interface{} //nolint:deadcode,unused
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=fluentbitagents,scope=Cluster,categories=logging-all
+// +kubebuilder:printcolumn:name="Base",type="string",JSONPath=".spec.baseRef",description="Agent the spec is inherited from"
+// +kubebuilder:printcolumn:name="Problems",type="integer",JSONPath=".status.problemsCount",description="Number of problems"
 // +kubebuilder:storageversion
 
 // FluentbitAgent is the Schema for the loggings API
@@ -63,6 +66,9 @@ type FluentbitAgentList struct {
 // FluentbitSpec defines the desired state of FluentbitAgent
 type FluentbitSpec struct {
 	LoggingRef string `json:"loggingRef,omitempty"`
+	// Name of a FluentbitAgent of the same logging this agent inherits its spec from, ignored in the Logging resource.
+	// Fields set in this agent are strategically merged onto the spec of the base, e.g. to target node pools with small differences.
+	BaseRef string `json:"baseRef,omitempty"`
 
 	DaemonSetAnnotations map[string]string `json:"daemonsetAnnotations,omitempty"`
 	Annotations          map[string]string `json:"annotations,omitempty"`
@@ -94,8 +100,14 @@ type FluentbitSpec struct {
 	MountPath         string                   `json:"mountPath,omitempty"`
 	ExtraVolumeMounts []*VolumeMount           `json:"extraVolumeMounts,omitempty"`
 	InputTail         InputTail                `json:"inputTail,omitempty"`
-	FilterAws         *FilterAws               `json:"filterAws,omitempty"`
-	FilterModify      []FilterModify           `json:"filterModify,omitempty"`
+	// Multiple tail inputs with their own routing tags, replaces inputTail when set
+	InputTails []FluentbitTailInput `json:"inputTails,omitempty"`
+	// Read the journal of the node in addition to the container logs
+	InputSystemd *InputSystemd `json:"inputSystemd,omitempty"`
+	// Collect the Kubernetes API server audit log of the control-plane nodes
+	AuditLogs    *FluentbitAuditLogs `json:"auditLogs,omitempty"`
+	FilterAws    *FilterAws          `json:"filterAws,omitempty"`
+	FilterModify []FilterModify      `json:"filterModify,omitempty"`
 	// Deprecated, use inputTail.parser
 	Parser string `json:"parser,omitempty"`
 	// Parameters for Kubernetes metadata filter
@@ -126,10 +138,18 @@ type FluentbitSpec struct {
 	// Specify a custom parser file to load in addition to the default parsers file.
 	// It must be a valid key in the configmap specified by customConfig
 	CustomParsers string `json:"customParsers,omitempty"`
+	// Outputs sending records directly from fluent-bit to a destination, in addition to or instead of the aggregator
+	DirectOutputs []FluentbitDirectOutput `json:"directOutputs,omitempty"`
+	// Do not forward records to the fluentd or syslog-ng aggregator of the logging, only send them to the direct outputs
+	DisableAggregatorOutput bool `json:"disableAggregatorOutput,omitempty"`
+	// Custom filter pipeline applied in the order of the list, after the kubernetes, aws and modify filters
+	Filters []FluentbitFilter `json:"filters,omitempty"`
 }
 
 // FluentbitStatus defines the resource status for FluentbitAgent
 type FluentbitStatus struct {
+	Problems      []string `json:"problems,omitempty"`
+	ProblemsCount int      `json:"problemsCount,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -256,6 +276,61 @@ type InputTail struct {
 	MultilineParser []string `json:"multiline.parser,omitempty"`
 }
 
+// FluentbitTailInput defines one of multiple tail inputs.
+// The records of the input are tagged with the tag prefix, and a kubernetes filter and the aggregator output are configured to match them.
+type FluentbitTailInput struct {
+	// Prefix of the tag of the records read by the input, the Tag of the input must start with it (default Tag: <tagPrefix>.*)
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	TagPrefix string `json:"tagPrefix"`
+	InputTail `json:",inline"`
+}
+
+// InputSystemd defines the systemd input that reads the journal of the node, see https://docs.fluentbit.io/manual/pipeline/inputs/systemd
+// The unit name and the hostname of the journal records are copied under the kubernetes key as container_name and host,
+// so ClusterFlows can select them with `container_names` and `hosts`.
+type InputSystemd struct {
+	// Prefix of the tag of the records, the tag is completed with the name of the unit, e.g. systemd.kubelet.service (default: systemd)
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	TagPrefix string `json:"tagPrefix,omitempty" plugin:"hidden"`
+	// Units to read the journal entries of, e.g. kubelet.service, all units are read if empty
+	Units []string `json:"units,omitempty" plugin:"hidden"`
+	// Path of the journal directory. /var/log is mounted into the agent, other paths have to be mounted with extraVolumeMounts (default: /var/log/journal)
+	Path string `json:"Path,omitempty" plugin:"default:/var/log/journal"`
+	// Database file to keep track of the journal cursor (default: /tail-db/systemd-state.db)
+	DB string `json:"DB,omitempty" plugin:"default:/tail-db/systemd-state.db"`
+	// Start reading new entries only, skipping the entries already in the journal (default: On)
+	ReadFromTail string `json:"Read_From_Tail,omitempty" plugin:"default:On"`
+	// Remove the leading underscores of the journal field names (default: Off)
+	StripUnderscores string `json:"Strip_Underscores,omitempty"`
+	// Maximum number of entries processed in one round (default: 5000)
+	MaxEntries string `json:"Max_Entries,omitempty"`
+	// Set a limit of memory that the input can use when appending data to the engine
+	MemBufLimit string `json:"Mem_Buf_Limit,omitempty"`
+	// Specify the buffering mechanism to use. It can be memory or filesystem. (default:memory)
+	StorageType string `json:"storage.type,omitempty"`
+}
+
+// FluentbitAuditLogs tails the Kubernetes API server audit log on the control-plane nodes.
+// The events are parsed as JSON and tagged audit.*, and they get kubernetes.container_name kube-apiserver-audit
+// and the node name as kubernetes.host, so ClusterFlows can select them with `container_names: [kube-apiserver-audit]`,
+// as returned by the AuditLogsClusterMatch helper.
+// Use nodeSelector to run the agent on the control-plane nodes only.
+type FluentbitAuditLogs struct {
+	// Path of the audit log, as set by the --audit-log-path flag of the API server.
+	// /var/log is mounted into the agent, other paths have to be mounted with extraVolumeMounts (default: /var/log/kubernetes/audit/audit.log)
+	Path string `json:"Path,omitempty" plugin:"default:/var/log/kubernetes/audit/audit.log"`
+	// Database file to keep track of the offset in the audit log (default: /tail-db/tail-audit-state.db)
+	DB string `json:"DB,omitempty" plugin:"default:/tail-db/tail-audit-state.db"`
+	// Set a limit of memory that the input can use when appending data to the engine (default: 5MB)
+	MemBufLimit string `json:"Mem_Buf_Limit,omitempty" plugin:"default:5MB"`
+	// Specify the buffering mechanism to use. It can be memory or filesystem. (default:memory)
+	StorageType string `json:"storage.type,omitempty"`
+	// Set the limit of the buffer size per monitored file, audit events can be large (default: 1MB)
+	BufferMaxSize string `json:"Buffer_Max_Size,omitempty" plugin:"default:1MB"`
+	// Do not add tolerations of the control-plane taints to the agent, e.g. when the agent is scheduled with its own tolerations
+	DisableControlPlaneTolerations bool `json:"disableControlPlaneTolerations,omitempty" plugin:"hidden"`
+}
+
 // FilterKubernetes Fluent Bit Kubernetes Filter allows to enrich your log files with Kubernetes metadata.
 type FilterKubernetes struct {
 	// Match filtered records (default:kube.*)
@@ -459,6 +534,315 @@ type ForwardOptions struct {
 	StorageTotalLimitSize string `json:"storage.total_limit_size,omitempty"`
 }
 
+// FluentbitDirectOutput defines a fluent-bit output plugin that sends records directly to a destination.
+// Exactly one of the output types has to be set.
+// Secrets are passed to fluent-bit as environment variables, so they have to be in the namespace of the fluent-bit daemonset.
+type FluentbitDirectOutput struct {
+	// Tag pattern of the records sent to the output (default: *)
+	Match string `json:"match,omitempty"`
+	// Maximum number of retries of a failed chunk, `no_limits` or `no_retries` (default: 1)
+	RetryLimit string `json:"retryLimit,omitempty"`
+	// Limit the maximum number of chunks in the filesystem for the output
+	StorageTotalLimitSize string `json:"storageTotalLimitSize,omitempty"`
+
+	Loki          *FluentbitLokiOutput          `json:"loki,omitempty"`
+	Elasticsearch *FluentbitElasticsearchOutput `json:"elasticsearch,omitempty"`
+	OpenTelemetry *FluentbitOpenTelemetryOutput `json:"opentelemetry,omitempty"`
+	Kafka         *FluentbitKafkaOutput         `json:"kafka,omitempty"`
+	S3            *FluentbitS3Output            `json:"s3,omitempty"`
+}
+
+// FluentbitLokiOutput sends records to Grafana Loki, see https://docs.fluentbit.io/manual/pipeline/outputs/loki
+type FluentbitLokiOutput struct {
+	// Loki hostname or IP address
+	Host string `json:"host"`
+	// Loki TCP port (default: 3100)
+	Port int `json:"port,omitempty"`
+	// Path of the push API (default: /loki/api/v1/push)
+	URI string `json:"uri,omitempty"`
+	// Tenant ID used by default to push logs to Loki
+	TenantID string `json:"tenant_id,omitempty"`
+	// Username for HTTP basic authentication
+	HTTPUser *secret.Secret `json:"http_user,omitempty"`
+	// Password for HTTP basic authentication
+	HTTPPasswd *secret.Secret `json:"http_passwd,omitempty"`
+	// Bearer token for authentication
+	BearerToken *secret.Secret `json:"bearer_token,omitempty"`
+	// Stream labels, e.g. `job=fluent-bit, $sub['stream']`
+	Labels string `json:"labels,omitempty"`
+	// Record keys used as labels, e.g. `$kubernetes['namespace_name']`
+	LabelKeys string `json:"label_keys,omitempty"`
+	// Record keys removed before sending the record
+	RemoveKeys string `json:"remove_keys,omitempty"`
+	// Format of the log line, json or key_value (default: json)
+	LineFormat string `json:"line_format,omitempty"`
+	// Add the labels of the pod as stream labels
+	AutoKubernetesLabels *bool `json:"auto_kubernetes_labels,omitempty"`
+	// Enable TLS
+	TLS *bool `json:"tls,omitempty"`
+	// Verify the certificate of the server
+	TLSVerify *bool `json:"tls.verify,omitempty"`
+}
+
+// FluentbitElasticsearchOutput sends records to Elasticsearch or OpenSearch, see https://docs.fluentbit.io/manual/pipeline/outputs/elasticsearch
+type FluentbitElasticsearchOutput struct {
+	// IP address or hostname of the target Elasticsearch instance (default: 127.0.0.1)
+	Host string `json:"Host,omitempty"`
+	// TCP port of the target Elasticsearch instance (default: 9200)
+	Port int `json:"Port,omitempty"`
+	// Prefix of the HTTP path, when Elasticsearch is behind a reverse proxy
+	Path string `json:"Path,omitempty"`
+	// Index name (default: fluent-bit)
+	Index string `json:"Index,omitempty"`
+	// Username for HTTP basic authentication
+	HTTPUser *secret.Secret `json:"HTTP_User,omitempty"`
+	// Password for HTTP basic authentication
+	HTTPPasswd *secret.Secret `json:"HTTP_Passwd,omitempty"`
+	// Cloud ID of an Elastic Cloud deployment
+	CloudID string `json:"Cloud_ID,omitempty"`
+	// Credentials of an Elastic Cloud deployment in `user:password` format
+	CloudAuth *secret.Secret `json:"Cloud_Auth,omitempty"`
+	// Enable Logstash format compatibility, the index name is composed of the prefix and the date
+	LogstashFormat *bool `json:"Logstash_Format,omitempty"`
+	// Prefix of the index name in Logstash format (default: logstash)
+	LogstashPrefix string `json:"Logstash_Prefix,omitempty"`
+	// Do not send the type name, required for Elasticsearch 8 and newer
+	SuppressTypeName *bool `json:"Suppress_Type_Name,omitempty"`
+	// Replace dots in field names with underscores, required for Elasticsearch 2.x and newer
+	ReplaceDots *bool `json:"Replace_Dots,omitempty"`
+	// Log the errors returned by Elasticsearch
+	TraceError *bool `json:"Trace_Error,omitempty"`
+	// Generate the _id of the records to avoid duplicates on retries
+	GenerateID *bool `json:"Generate_ID,omitempty"`
+	// Size of the buffer used to read the response of Elasticsearch (default: 512KB)
+	BufferSize string `json:"Buffer_Size,omitempty"`
+	// Enable AWS Sigv4 authentication for Amazon OpenSearch Service
+	AWSAuth *bool `json:"AWS_Auth,omitempty"`
+	// AWS region of the Amazon OpenSearch Service domain
+	AWSRegion string `json:"AWS_Region,omitempty"`
+	// Enable TLS
+	TLS *bool `json:"tls,omitempty"`
+	// Verify the certificate of the server
+	TLSVerify *bool `json:"tls.verify,omitempty"`
+}
+
+// FluentbitOpenTelemetryOutput sends records to an OpenTelemetry collector over OTLP/HTTP, see https://docs.fluentbit.io/manual/pipeline/outputs/opentelemetry
+type FluentbitOpenTelemetryOutput struct {
+	// IP address or hostname of the collector
+	Host string `json:"host"`
+	// TCP port of the collector (default: 80)
+	Port int `json:"port,omitempty"`
+	// Path of the logs endpoint (default: /v1/logs)
+	LogsURI string `json:"logs_uri,omitempty"`
+	// Username for HTTP basic authentication
+	HTTPUser *secret.Secret `json:"http_user,omitempty"`
+	// Password for HTTP basic authentication
+	HTTPPasswd *secret.Secret `json:"http_passwd,omitempty"`
+	// HTTP headers added to the requests, e.g. for authentication
+	Headers map[string]*secret.Secret `json:"header,omitempty" plugin:"hidden"`
+	// Compress the payload, only gzip is supported
+	Compress string `json:"compress,omitempty"`
+	// Enable TLS
+	TLS *bool `json:"tls,omitempty"`
+	// Verify the certificate of the server
+	TLSVerify *bool `json:"tls.verify,omitempty"`
+}
+
+// FluentbitKafkaOutput sends records to Apache Kafka, see https://docs.fluentbit.io/manual/pipeline/outputs/kafka
+type FluentbitKafkaOutput struct {
+	// Comma separated list of brokers, e.g. `192.168.1.3:9092, 192.168.1.4:9092`
+	Brokers string `json:"brokers"`
+	// Comma separated list of topics, the first one is used by default (default: fluent-bit)
+	Topics string `json:"topics,omitempty"`
+	// Record key that selects the topic of the record
+	TopicKey string `json:"topic_key,omitempty"`
+	// Format of the messages, json, msgpack, gelf or raw (default: json)
+	Format string `json:"format,omitempty"`
+	// Key of the messages
+	MessageKey string `json:"message_key,omitempty"`
+	// Record key that is used as the key of the message
+	MessageKeyField string `json:"message_key_field,omitempty"`
+	// Key of the timestamp in the message (default: @timestamp)
+	TimestampKey string `json:"timestamp_key,omitempty"`
+	// Protocol used to communicate with the brokers, e.g. SASL_SSL
+	SecurityProtocol string `json:"rdkafka.security.protocol,omitempty"`
+	// SASL mechanism, e.g. PLAIN or SCRAM-SHA-512
+	SASLMechanism string `json:"rdkafka.sasl.mechanism,omitempty"`
+	// SASL username
+	SASLUsername *secret.Secret `json:"rdkafka.sasl.username,omitempty"`
+	// SASL password
+	SASLPassword *secret.Secret `json:"rdkafka.sasl.password,omitempty"`
+	// Additional librdkafka properties without the `rdkafka.` prefix, see https://github.com/confluentinc/librdkafka/blob/master/CONFIGURATION.md
+	RDKafkaOptions map[string]string `json:"rdkafkaOptions,omitempty" plugin:"hidden"`
+}
+
+// FluentbitS3Output sends records to Amazon S3 or a compatible object store, see https://docs.fluentbit.io/manual/pipeline/outputs/s3
+type FluentbitS3Output struct {
+	// Name of the bucket
+	Bucket string `json:"bucket"`
+	// AWS region of the bucket
+	Region string `json:"region"`
+	// Custom endpoint of an S3 compatible API
+	Endpoint string `json:"endpoint,omitempty"`
+	// Format of the object keys (default: /fluent-bit-logs/$TAG/%Y/%m/%d/%H/%M/%S)
+	S3KeyFormat string `json:"s3_key_format,omitempty"`
+	// Size of the objects uploaded to S3 (default: 100M)
+	TotalFileSize string `json:"total_file_size,omitempty"`
+	// Upload the buffered data after this time even if the file size is not reached (default: 10m)
+	UploadTimeout string `json:"upload_timeout,omitempty"`
+	// Use the PutObject API instead of multipart uploads
+	UsePutObject *bool `json:"use_put_object,omitempty"`
+	// Compression of the uploaded objects, gzip or arrow
+	Compression string `json:"compression,omitempty"`
+	// IAM role to assume
+	RoleARN string `json:"role_arn,omitempty"`
+	// Directory used to buffer the data locally (default: /tmp/fluent-bit/s3)
+	StoreDir string `json:"store_dir,omitempty"`
+	// Access key ID, passed to fluent-bit as the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID *secret.Secret `json:"accessKeyId,omitempty" plugin:"hidden"`
+	// Secret access key, passed to fluent-bit as the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey *secret.Secret `json:"secretAccessKey,omitempty" plugin:"hidden"`
+}
+
+// FluentbitFilter defines a fluent-bit filter plugin of the custom filter pipeline.
+// Exactly one of the filter types has to be set.
+type FluentbitFilter struct {
+	// Tag pattern of the records processed by the filter (default: *)
+	Match string `json:"match,omitempty"`
+
+	Grep       *FluentbitGrepFilter       `json:"grep,omitempty"`
+	Lua        *FluentbitLuaFilter        `json:"lua,omitempty"`
+	RewriteTag *FluentbitRewriteTagFilter `json:"rewriteTag,omitempty"`
+	Throttle   *FluentbitThrottleFilter   `json:"throttle,omitempty"`
+	Nest       *FluentbitNestFilter       `json:"nest,omitempty"`
+	Multiline  *FluentbitMultilineFilter  `json:"multiline,omitempty"`
+}
+
+// FluentbitGrepFilter keeps or drops records based on the values of their keys, see https://docs.fluentbit.io/manual/pipeline/filters/grep
+type FluentbitGrepFilter struct {
+	// Keep records whose KEY matches REGEX, in `KEY REGEX` format
+	Regex []string `json:"regex,omitempty" plugin:"hidden"`
+	// Drop records whose KEY matches REGEX, in `KEY REGEX` format
+	Exclude []string `json:"exclude,omitempty" plugin:"hidden"`
+	// Logical operator applied to multiple rules, AND, OR or legacy (default: legacy)
+	LogicalOp string `json:"logical_op,omitempty"`
+}
+
+// FluentbitLuaFilter modifies records with a Lua script, see https://docs.fluentbit.io/manual/pipeline/filters/lua
+type FluentbitLuaFilter struct {
+	// ConfigMap key holding the Lua script, the ConfigMap has to be in the namespace of the fluent-bit daemonset
+	Script corev1.ConfigMapKeySelector `json:"script" plugin:"hidden"`
+	// Name of the Lua function called for each record
+	Call string `json:"call"`
+	// Space separated list of keys whose values are converted to integers
+	TypeIntKey string `json:"type_int_key,omitempty"`
+	// Run the script in protected mode, so that a failing script does not crash fluent-bit (default: true)
+	ProtectedMode *bool `json:"protected_mode,omitempty"`
+	// Pass the timestamp to the function as a table instead of a float
+	TimeAsTable *bool `json:"time_as_table,omitempty"`
+}
+
+// FluentbitRewriteTagFilter re-emits records with a new tag, see https://docs.fluentbit.io/manual/pipeline/filters/rewrite-tag
+type FluentbitRewriteTagFilter struct {
+	// Rewrite rules in `$KEY REGEX NEW_TAG KEEP` format.
+	// With inputTails the records of the new tags are forwarded to the aggregators as well,
+	// all records are forwarded if a new tag starts with a record accessor.
+	Rules []string `json:"rules" plugin:"hidden"`
+	// Name of the emitter input plugin created by the filter
+	EmitterName string `json:"emitter_name,omitempty"`
+	// Buffering mechanism of the emitter, memory or filesystem (default: memory)
+	EmitterStorageType string `json:"emitter_storage.type,omitempty"`
+	// Memory limit of the emitter (default: 10M)
+	EmitterMemBufLimit string `json:"emitter_mem_buf_limit,omitempty"`
+}
+
+// FluentbitThrottleFilter limits the rate of the records, see https://docs.fluentbit.io/manual/pipeline/filters/throttle
+type FluentbitThrottleFilter struct {
+	// Number of records allowed per interval
+	Rate int `json:"rate,omitempty"`
+	// Number of intervals the average rate is calculated over (default: 5)
+	Window int `json:"window,omitempty"`
+	// Length of an interval, e.g. 1s or 1m (default: 1s)
+	Interval string `json:"interval,omitempty"`
+	// Print the status of the throttling to the fluent-bit log
+	PrintStatus *bool `json:"print_status,omitempty"`
+}
+
+// FluentbitNestFilter nests keys under a new key or lifts nested keys to the top level, see https://docs.fluentbit.io/manual/pipeline/filters/nest
+type FluentbitNestFilter struct {
+	// Operation of the filter, nest or lift
+	// +kubebuilder:validation:Enum=nest;lift
+	Operation string `json:"operation"`
+	// Wildcards selecting the keys to nest
+	Wildcard []string `json:"wildcard,omitempty" plugin:"hidden"`
+	// Key the selected keys are nested under
+	NestUnder string `json:"nest_under,omitempty"`
+	// Key whose nested keys are lifted
+	NestedUnder string `json:"nested_under,omitempty"`
+	// Prefix added to the lifted keys
+	AddPrefix string `json:"add_prefix,omitempty"`
+	// Prefix removed from the lifted keys
+	RemovePrefix string `json:"remove_prefix,omitempty"`
+}
+
+// FluentbitMultilineFilter concatenates multiline messages, see https://docs.fluentbit.io/manual/pipeline/filters/multiline-stacktrace
+type FluentbitMultilineFilter struct {
+	// Built-in or custom multiline parsers, tried in order
+	Parsers []string `json:"parsers" plugin:"hidden"`
+	// Key holding the message to concatenate (default: log)
+	KeyContent string `json:"multiline.key_content,omitempty"`
+	// Mode of the filter, parser or partial_message (default: parser)
+	Mode string `json:"mode,omitempty"`
+	// Buffer the records, required to concatenate records of different chunks
+	Buffer *bool `json:"buffer,omitempty"`
+	// Timeout of a buffered multiline message in milliseconds (default: 2000)
+	FlushMS int `json:"flush_ms,omitempty"`
+	// Name of the emitter input plugin created in buffered mode
+	EmitterName string `json:"emitter_name,omitempty"`
+}
+
+const (
+	// AuditLogsTagPrefix is the tag prefix of the audit events collected by FluentbitSpec.AuditLogs
+	AuditLogsTagPrefix = "audit"
+	// AuditLogsContainerName is set as kubernetes.container_name of the audit events
+	AuditLogsContainerName = "kube-apiserver-audit"
+)
+
+// controlPlaneTaintKeys are the taints of the control-plane nodes set by kubeadm
+var controlPlaneTaintKeys = []string{
+	"node-role.kubernetes.io/control-plane",
+	"node-role.kubernetes.io/master",
+}
+
+// EffectiveTolerations returns the tolerations of the agent, including the control-plane tolerations
+// required to collect the audit logs
+func (spec *FluentbitSpec) EffectiveTolerations() []corev1.Toleration {
+	if spec.AuditLogs == nil || spec.AuditLogs.DisableControlPlaneTolerations {
+		return spec.Tolerations
+	}
+	tolerations := append([]corev1.Toleration{}, spec.Tolerations...)
+	for _, key := range controlPlaneTaintKeys {
+		if toleratesKey(tolerations, key) {
+			continue
+		}
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      key,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return tolerations
+}
+
+func toleratesKey(tolerations []corev1.Toleration, key string) bool {
+	for _, toleration := range tolerations {
+		if toleration.Operator == corev1.TolerationOpExists && (toleration.Key == "" || toleration.Key == key) {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	SchemeBuilder.Register(&FluentbitAgent{}, &FluentbitAgentList{})
 }
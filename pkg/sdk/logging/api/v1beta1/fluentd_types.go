@@ -15,9 +15,12 @@
 package v1beta1
 
 import (
+	"hash/fnv"
+
 	"github.com/cisco-open/operator-tools/pkg/typeoverride"
 	"github.com/cisco-open/operator-tools/pkg/volume"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/input"
 )
@@ -88,13 +91,132 @@ type FluentdSpec struct {
 	// +kubebuilder:validation:enum=stdout,null
 	FluentLogDestination string `json:"fluentLogDestination,omitempty"`
 	// FluentOutLogrotate sends fluent's stdout to file and rotates it
-	FluentOutLogrotate      *FluentOutLogrotate          `json:"fluentOutLogrotate,omitempty"`
-	ForwardInputConfig      *input.ForwardInputConfig    `json:"forwardInputConfig,omitempty"`
+	FluentOutLogrotate *FluentOutLogrotate       `json:"fluentOutLogrotate,omitempty"`
+	ForwardInputConfig *input.ForwardInputConfig `json:"forwardInputConfig,omitempty"`
+	// Additional inputs receiving logs from outside of the cluster, next to the forward input of the log forwarders
+	ExtraInputs             []FluentdInput               `json:"extraInputs,omitempty"`
 	ServiceAccountOverrides *typeoverride.ServiceAccount `json:"serviceAccount,omitempty"`
 	DNSPolicy               corev1.DNSPolicy             `json:"dnsPolicy,omitempty"`
 	DNSConfig               *corev1.PodDNSConfig         `json:"dnsConfig,omitempty"`
 	ExtraArgs               []string                     `json:"extraArgs,omitempty"`
 	CompressConfigFile      bool                         `json:"compressConfigFile,omitempty"`
+
+	// Publish the routes of the label router, the matches and the resolved outputs of the routed flows
+	// as JSON in the fluentd-routing ConfigMap of the control namespace
+	PublishRoutingTable bool `json:"publishRoutingTable,omitempty"`
+
+	// Restrict the traffic of the fluentd pods with a NetworkPolicy, allowing records from the fluent-bit agents only
+	// and connections to the listed egress endpoints
+	NetworkPolicy *AggregatorNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// Split the flows across multiple independent fluentd statefulsets by their namespace.
+	// The fluent-bit agents route the container logs of each namespace to the service of its shard.
+	Shards *FluentdShards `json:"shards,omitempty"`
+
+	// Roll out changes of the flow configuration through a canary statefulset first
+	Canary *FluentdCanary `json:"canary,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+// FluentdCanary rolls out changes of the flow configuration and the output secrets through a single replica canary statefulset.
+// The canary pod is selected by the fluentd service, so it receives its share of the connections of the fluent-bit agents,
+// unless they use the upstream, while the other replicas keep the live config.
+// The config is promoted to every replica once the canary has been ready for the analysis duration without exceeding the tolerated output errors,
+// otherwise the rollout is aborted and the live config is kept until the flow configuration changes again.
+// The canary buffers in the same kind of volume as the fluentd statefulset. With a PVC its buffer is drained by a drainer job
+// once it's removed, if draining is enabled, otherwise the records still buffered are lost or kept on the volume.
+// The routing table is published for the live config until the canary config is promoted.
+// Changes of the fluentd spec itself are applied to every replica at once. Can't be used with shards.
+// Enabling the canary excludes the canary pods from the selector of the fluentd statefulset,
+// which is recreated only if `enableRecreateWorkloadOnImmutableFieldChange` is set.
+type FluentdCanary struct {
+	// Time the canary has to be ready before its config is promoted (default: 5m)
+	AnalysisDuration *metav1.Duration `json:"analysisDuration,omitempty"`
+	// Time the canary has to become ready before the rollout is aborted (default: 5m)
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+	// Output errors of the canary tolerated during the analysis, read from the fluentd metrics if metrics are enabled
+	MaxOutputErrors int32 `json:"maxOutputErrors,omitempty"`
+}
+
+// FluentdShardLabel is set on the resources of every shard when the flows are sharded
+const FluentdShardLabel = "logging.banzaicloud.io/fluentd-shard"
+
+// +kubebuilder:object:generate=true
+
+// FluentdShards splits the flows across multiple fluentd statefulsets.
+// Every shard renders the ClusterFlows and ClusterOutputs, the Flows and Outputs of the namespaces assigned to it,
+// and the Outputs of other namespaces its Flows reference through an OutputGrant.
+// The first shard keeps the resource names of the unsharded fluentd and receives the records of the namespaces without Flows,
+// the other shards get the `<logging>-shard<n>-` prefix.
+// Enabling sharding adds the shard label to the selector of the fluentd statefulset,
+// which is recreated only if `enableRecreateWorkloadOnImmutableFieldChange` is set.
+// Sharding can't be combined with the upstream or the target host of fluent-bit, with node agents or with flowConfigOverride.
+type FluentdShards struct {
+	// Number of shards
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+	// Assign namespaces to shards explicitly, other namespaces are assigned by the hash of their name
+	Namespaces map[string]int32 `json:"namespaces,omitempty"`
+}
+
+// ShardCount returns the number of shards, 1 if sharding is disabled
+func (s *FluentdShards) ShardCount() int32 {
+	if s == nil || s.Count < 1 {
+		return 1
+	}
+	return s.Count
+}
+
+// ShardOf returns the shard of the given namespace
+func (s *FluentdShards) ShardOf(namespace string) int32 {
+	if s == nil {
+		return 0
+	}
+	if shard, ok := s.Namespaces[namespace]; ok {
+		return shard
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(namespace))
+	return int32(hasher.Sum32() % uint32(s.ShardCount()))
+}
+
+// +kubebuilder:object:generate=true
+
+// FluentdInput is an additional input of fluentd, exposed on the fluentd service.
+// Records of the input are attributed to a namespace and carry the `logging.kube-logging.io/input: <name>` label,
+// so flows of the namespace can select them like the logs of a pod.
+// Exactly one of the input types has to be set.
+type FluentdInput struct {
+	// Name of the input, also used as the name of the service port and in the tag of the records (`input.<name>`)
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name"`
+	// Port the input listens on
+	Port int32 `json:"port"`
+	// Namespace the records of the input are attributed to (default: the control namespace)
+	Namespace string `json:"namespace,omitempty"`
+	// Use the certificate of the fluentd TLS settings, unless the transport of the input is configured explicitly
+	TLS bool `json:"tls,omitempty"`
+	// HTTP endpoint for webhook-style senders, the tag of the records is the path of the request
+	HTTP *input.HTTPInputConfig `json:"http,omitempty"`
+	// Syslog messages over UDP, TCP or TLS
+	Syslog *input.SyslogInputConfig `json:"syslog,omitempty"`
+	// Messages over TCP, parsed with the given parser
+	TCP *input.TCPInputConfig `json:"tcp,omitempty"`
+	// Messages over UDP, parsed with the given parser
+	UDP *input.UDPInputConfig `json:"udp,omitempty"`
+}
+
+// Protocol returns the transport protocol of the input
+func (i FluentdInput) Protocol() corev1.Protocol {
+	switch {
+	case i.UDP != nil:
+		return corev1.ProtocolUDP
+	case i.Syslog != nil && !i.TLS && i.Syslog.TransportProtocol() == "udp":
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
 }
 
 // +kubebuilder:object:generate=true
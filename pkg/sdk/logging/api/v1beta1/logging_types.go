@@ -17,6 +17,7 @@ package v1beta1
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	util "github.com/cisco-open/operator-tools/pkg/utils"
 	"github.com/cisco-open/operator-tools/pkg/volume"
@@ -45,6 +46,8 @@ type LoggingSpec struct {
 	LoggingRef string `json:"loggingRef,omitempty"`
 	// Disable configuration check before applying new fluentd configuration.
 	FlowConfigCheckDisabled bool `json:"flowConfigCheckDisabled,omitempty"`
+	// ConfigCheck configures how new aggregator configurations are checked
+	ConfigCheck *ConfigCheck `json:"configCheck,omitempty"`
 	// Whether to skip invalid Flow and ClusterFlow resources
 	SkipInvalidResources bool `json:"skipInvalidResources,omitempty"`
 	// Override generated config. This is a *raw* configuration string for troubleshooting purposes.
@@ -75,6 +78,15 @@ type LoggingSpec struct {
 	ControlNamespace string `json:"controlNamespace"`
 	// Allow configuration of cluster resources from any namespace. Mutually exclusive with ControlNamespace restriction of Cluster resources
 	AllowClusterResourcesFromAllNamespaces bool `json:"allowClusterResourcesFromAllNamespaces,omitempty"`
+	// StrictTenancy confines namespaced resources to their own namespace:
+	// Flows and SyslogNGFlows (and cluster flows outside the control namespace) only receive logs from their own namespace,
+	// custom flow labels and relabel outputs are rejected in tenant namespaces,
+	// and the secret lookups of the tenant flows and outputs are audited to stay within their namespace
+	// (or the namespace of a granted shared output), including the references to secret backends.
+	// Violations are reported in the status of the affected resources.
+	StrictTenancy bool `json:"strictTenancy,omitempty"`
+	// SecretBackends configures secret sources other than Kubernetes Secrets for output credentials
+	SecretBackends *SecretBackends `json:"secretBackends,omitempty"`
 	// InlineNodeAgent Configuration
 	// Deprecated, will be removed with next major version
 	NodeAgents []*InlineNodeAgent `json:"nodeAgents,omitempty"`
@@ -89,12 +101,140 @@ type LoggingSpec struct {
 type LoggingStatus struct {
 	ConfigCheckResults map[string]bool `json:"configCheckResults,omitempty"`
 	Problems           []string        `json:"problems,omitempty"`
+	ProblemsCount      int             `json:"problemsCount,omitempty"`
+
+	// Healthy is true when the aggregator and the fluent-bit daemonsets are fully rolled out with the desired config
+	// and the logging has no problems
+	Healthy bool `json:"healthy,omitempty"`
+	// Observed state of the fluentd or syslog-ng aggregator
+	Aggregator *AggregatorStatus `json:"aggregator,omitempty"`
+	// Observed state of the fluent-bit daemonsets, one per FluentbitAgent
+	FluentbitAgents []FluentbitAgentStatus `json:"fluentbitAgents,omitempty"`
+	// Hash of the most recently rendered aggregator config, the comma separated hashes of the shards if fluentd is sharded
+	DesiredConfigHash string `json:"desiredConfigHash,omitempty"`
+	// Hash of the config served by the aggregator, read from the statefulsets once their controller observed the latest spec
+	LiveConfigHash string `json:"liveConfigHash,omitempty"`
+	// Time of the last reconcile that finished without errors,
+	// refreshed at most once per minute unless the rest of the status changes
+	LastSuccessfulReconcile *metav1.Time `json:"lastSuccessfulReconcile,omitempty"`
+	// Names of the fluentd buffer drainer jobs in progress
+	DrainJobs []string `json:"drainJobs,omitempty"`
+	// Progress of the canary rollout of the latest fluentd config
+	ConfigCanary *ConfigCanaryStatus `json:"configCanary,omitempty"`
+	// Most recent config check results, newest first, bounded by configCheck.historyLimit
+	ConfigCheckHistory []ConfigCheckRecord `json:"configCheckHistory,omitempty"`
+}
+
+// ConfigCheckRecord is the result of checking an aggregator config
+type ConfigCheckRecord struct {
+	// Aggregator the config belongs to, fluentd or syslog-ng
+	Component string `json:"component,omitempty"`
+	// Fluentd shard the config belongs to, only set if the flows are sharded
+	Shard *int32 `json:"shard,omitempty"`
+	// Hash of the checked config
+	Hash string `json:"hash"`
+	// Whether the config passed the check
+	Valid bool `json:"valid"`
+	// Time of the result
+	Time metav1.Time `json:"time"`
+	// Tail of the output of the failed config check containers
+	Output string `json:"output,omitempty"`
+}
+
+// ConfigCheck configures the check of the aggregator configurations.
+// The references in the configurations rendered by the operator are checked before the config check pod is started:
+// the fluentd plugin ids have to be unique and every referenced label has to be defined,
+// the cluster outputs, shared outputs and local outputs referenced by the syslog-ng flows have to exist.
+// A configuration failing the check is reported in the status of the logging right away and never reaches the pod,
+// everything else, like the values of the plugin params, is checked by the aggregator in the config check pod.
+type ConfigCheck struct {
+	// Number of results kept in status.configCheckHistory (default: 10)
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+type ConfigCanaryPhase string
+
+const (
+	ConfigCanaryProgressing ConfigCanaryPhase = "Progressing"
+	ConfigCanaryPromoted    ConfigCanaryPhase = "Promoted"
+	ConfigCanaryAborted     ConfigCanaryPhase = "Aborted"
+)
+
+// ConfigCanaryStatus is the progress of the canary rollout of an aggregator config
+type ConfigCanaryStatus struct {
+	// Hash of the config rolled out through the canary
+	ConfigHash string `json:"configHash"`
+	// Progressing, Promoted or Aborted
+	Phase ConfigCanaryPhase `json:"phase"`
+	// Time the canary has been created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time the canary has become ready, the start of the analysis
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+	// Output errors of the canary observed during the analysis
+	OutputErrors int64 `json:"outputErrors,omitempty"`
+	// Reason of the promotion or the abort
+	Message string `json:"message,omitempty"`
+}
+
+// AggregatorStatus is the observed state of the aggregator statefulset
+type AggregatorStatus struct {
+	// fluentd or syslog-ng
+	Type            string `json:"type"`
+	Replicas        int32  `json:"replicas"`
+	ReadyReplicas   int32  `json:"readyReplicas"`
+	UpdatedReplicas int32  `json:"updatedReplicas"`
+	// Ready replicas out of the desired ones, like 2/3
+	Ready string `json:"ready"`
+}
+
+// IsReady reports whether every desired replica is up to date and ready
+func (s AggregatorStatus) IsReady() bool {
+	return s.ReadyReplicas >= s.Replicas && s.UpdatedReplicas >= s.Replicas
+}
+
+// FluentbitAgentStatus is the observed state of a fluent-bit daemonset
+type FluentbitAgentStatus struct {
+	Name                   string `json:"name"`
+	DesiredNumberScheduled int32  `json:"desiredNumberScheduled"`
+	NumberReady            int32  `json:"numberReady"`
+	UpdatedNumberScheduled int32  `json:"updatedNumberScheduled"`
+}
+
+// IsReady reports whether the daemonset runs an up to date and ready pod on every scheduled node
+func (s FluentbitAgentStatus) IsReady() bool {
+	return s.NumberReady >= s.DesiredNumberScheduled && s.UpdatedNumberScheduled >= s.DesiredNumberScheduled
+}
+
+// WorkloadsReady reports whether the aggregator and the fluent-bit daemonsets are up to date and ready
+func (s LoggingStatus) WorkloadsReady() bool {
+	if s.Aggregator != nil && !s.Aggregator.IsReady() {
+		return false
+	}
+	for _, agent := range s.FluentbitAgents {
+		if !agent.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateHealth derives ProblemsCount and Healthy from the rest of the status,
+// succeeded tells whether the last reconcile finished without errors
+func (s *LoggingStatus) UpdateHealth(succeeded bool) {
+	s.ProblemsCount = len(s.Problems)
+	s.Healthy = succeeded && s.WorkloadsReady() && s.ProblemsCount == 0 && s.LiveConfigHash == s.DesiredConfigHash
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=loggings,scope=Cluster,categories=logging-all
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.healthy",description="Is the logging pipeline healthy?"
+// +kubebuilder:printcolumn:name="Aggregator",type="string",JSONPath=".status.aggregator.ready",description="Ready aggregator replicas"
+// +kubebuilder:printcolumn:name="Problems",type="integer",JSONPath=".status.problemsCount",description="Number of problems"
+// +kubebuilder:printcolumn:name="Last Reconcile",type="date",JSONPath=".status.lastSuccessfulReconcile",description="Time of the last successful reconcile"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Logging is the Schema for the loggings API
 type Logging struct {
@@ -147,6 +287,39 @@ const (
 	DefaultFluentdBufferVolumeImageTag          = "v0.7.1"
 )
 
+// DefaultConfigCheckHistoryLimit is the number of config check results kept in the status by default
+const DefaultConfigCheckHistoryLimit = 10
+
+// ConfigCheckHistoryLimit returns the number of config check results to keep in the status
+func (l *Logging) ConfigCheckHistoryLimit() int {
+	if l.Spec.ConfigCheck != nil && l.Spec.ConfigCheck.HistoryLimit != nil {
+		return int(*l.Spec.ConfigCheck.HistoryLimit)
+	}
+	return DefaultConfigCheckHistoryLimit
+}
+
+// RecordConfigCheck puts the result of a config check at the front of the history,
+// replacing the previous result of the same config of the same component and shard and dropping the ones beyond the history limit
+func (l *Logging) RecordConfigCheck(record ConfigCheckRecord) {
+	history := []ConfigCheckRecord{record}
+	for _, r := range l.Status.ConfigCheckHistory {
+		if !r.sameConfig(record) && len(history) < l.ConfigCheckHistoryLimit() {
+			history = append(history, r)
+		}
+	}
+	l.Status.ConfigCheckHistory = history
+}
+
+func (r ConfigCheckRecord) sameConfig(other ConfigCheckRecord) bool {
+	if r.Hash != other.Hash || r.Component != other.Component {
+		return false
+	}
+	if r.Shard == nil || other.Shard == nil {
+		return r.Shard == other.Shard
+	}
+	return *r.Shard == *other.Shard
+}
+
 // SetDefaults fills empty attributes
 func (l *Logging) SetDefaults() error {
 	if l.Spec.ClusterDomain == nil {
@@ -468,6 +641,31 @@ func FluentBitDefaults(fluentbitSpec *FluentbitSpec) error {
 		if fluentbitSpec.InputTail.Tag == "" {
 			fluentbitSpec.InputTail.Tag = "kubernetes.*"
 		}
+		fluentbitSpec.Tolerations = fluentbitSpec.EffectiveTolerations()
+		for i := range fluentbitSpec.InputTails {
+			input := &fluentbitSpec.InputTails[i]
+			if input.Path == "" {
+				input.Path = "/var/log/containers/*.log"
+			}
+			if input.RefreshInterval == "" {
+				input.RefreshInterval = "5"
+			}
+			if input.SkipLongLines == "" {
+				input.SkipLongLines = "On"
+			}
+			if input.DB == nil {
+				input.DB = util.StringPointer(fmt.Sprintf("/tail-db/tail-%s-state.db", input.TagPrefix))
+			}
+			if input.DBLocking == nil {
+				input.DBLocking = util.BoolPointer(true)
+			}
+			if input.MemBufLimit == "" {
+				input.MemBufLimit = "5MB"
+			}
+			if input.Tag == "" {
+				input.Tag = input.TagPrefix + ".*"
+			}
+		}
 		if fluentbitSpec.Annotations == nil {
 			fluentbitSpec.Annotations = make(map[string]string)
 		}
@@ -671,6 +869,24 @@ func (l *Logging) GetFluentdLabels(component string) map[string]string {
 	)
 }
 
+// FluentdShardQualifiedName is the qualified name of a resource of the given fluentd shard,
+// the first shard keeps the unsharded name
+func (l *Logging) FluentdShardQualifiedName(shard int32, name string) string {
+	if shard == 0 {
+		return l.QualifiedName(name)
+	}
+	return l.QualifiedName(fmt.Sprintf("shard%d-%s", shard, name))
+}
+
+// GetFluentdShardLabels returns the fluentd labels extended with the shard label if sharding is enabled
+func (l *Logging) GetFluentdShardLabels(shard int32, component string) map[string]string {
+	labels := l.GetFluentdLabels(component)
+	if l.Spec.FluentdSpec.Shards != nil {
+		labels[FluentdShardLabel] = strconv.Itoa(int(shard))
+	}
+	return labels
+}
+
 // SyslogNGObjectMeta creates an objectMeta for resource syslog-ng
 func (l *Logging) SyslogNGObjectMeta(name, component string) metav1.ObjectMeta {
 	o := metav1.ObjectMeta{
@@ -0,0 +1,228 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/cisco-open/operator-tools/pkg/utils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+func TestLoggingStatusUpdateHealth(t *testing.T) {
+	readyAggregator := &v1beta1.AggregatorStatus{Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2}
+	readyAgent := v1beta1.FluentbitAgentStatus{Name: "agent", DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3}
+
+	testCases := map[string]struct {
+		status        v1beta1.LoggingStatus
+		succeeded     bool
+		healthy       bool
+		problemsCount int
+	}{
+		"healthy": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				FluentbitAgents:   []v1beta1.FluentbitAgentStatus{readyAgent},
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+			},
+			succeeded: true,
+			healthy:   true,
+		},
+		"no aggregator": {
+			status: v1beta1.LoggingStatus{
+				FluentbitAgents: []v1beta1.FluentbitAgentStatus{readyAgent},
+			},
+			succeeded: true,
+			healthy:   true,
+		},
+		"reconcile failed": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+			},
+		},
+		"problems": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+				Problems:          []string{"first", "second"},
+			},
+			succeeded:     true,
+			problemsCount: 2,
+		},
+		"stale problems count": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+				ProblemsCount:     1,
+			},
+			succeeded: true,
+			healthy:   true,
+		},
+		"aggregator rolling out": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        &v1beta1.AggregatorStatus{Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 1},
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+			},
+			succeeded: true,
+		},
+		"agent not ready": {
+			status: v1beta1.LoggingStatus{
+				Aggregator: readyAggregator,
+				FluentbitAgents: []v1beta1.FluentbitAgentStatus{
+					readyAgent,
+					{Name: "other", DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 3},
+				},
+				DesiredConfigHash: "a",
+				LiveConfigHash:    "a",
+			},
+			succeeded: true,
+		},
+		"config not live yet": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				DesiredConfigHash: "b",
+				LiveConfigHash:    "a",
+			},
+			succeeded: true,
+		},
+		"shard config not live yet": {
+			status: v1beta1.LoggingStatus{
+				Aggregator:        readyAggregator,
+				DesiredConfigHash: "a,b",
+				LiveConfigHash:    "",
+			},
+			succeeded: true,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			status := testCase.status
+			status.UpdateHealth(testCase.succeeded)
+			assert.Equal(t, testCase.healthy, status.Healthy)
+			assert.Equal(t, testCase.problemsCount, status.ProblemsCount)
+		})
+	}
+}
+
+func TestLoggingRecordConfigCheck(t *testing.T) {
+	record := func(component string, shard *int32, hash string, valid bool) v1beta1.ConfigCheckRecord {
+		return v1beta1.ConfigCheckRecord{Component: component, Shard: shard, Hash: hash, Valid: valid}
+	}
+	hashes := func(history []v1beta1.ConfigCheckRecord) (result []string) {
+		for _, r := range history {
+			result = append(result, r.Hash)
+		}
+		return
+	}
+
+	testCases := map[string]struct {
+		historyLimit *int32
+		history      []v1beta1.ConfigCheckRecord
+		record       v1beta1.ConfigCheckRecord
+		want         []string
+		wantValid    []bool
+	}{
+		"first record": {
+			record:    record("fluentd", nil, "a", true),
+			want:      []string{"a"},
+			wantValid: []bool{true},
+		},
+		"newest first": {
+			history:   []v1beta1.ConfigCheckRecord{record("fluentd", nil, "b", true), record("fluentd", nil, "a", true)},
+			record:    record("fluentd", nil, "c", false),
+			want:      []string{"c", "b", "a"},
+			wantValid: []bool{false, true, true},
+		},
+		"same hash replaced": {
+			history:   []v1beta1.ConfigCheckRecord{record("fluentd", nil, "b", true), record("fluentd", nil, "a", false)},
+			record:    record("fluentd", nil, "a", true),
+			want:      []string{"a", "b"},
+			wantValid: []bool{true, true},
+		},
+		"same hash of another shard kept": {
+			history:   []v1beta1.ConfigCheckRecord{record("fluentd", utils.IntPointer(1), "a", false)},
+			record:    record("fluentd", utils.IntPointer(0), "a", true),
+			want:      []string{"a", "a"},
+			wantValid: []bool{true, false},
+		},
+		"same hash of another component kept": {
+			history:   []v1beta1.ConfigCheckRecord{record("syslog-ng", nil, "a", false)},
+			record:    record("fluentd", nil, "a", true),
+			want:      []string{"a", "a"},
+			wantValid: []bool{true, false},
+		},
+		"truncated to the history limit": {
+			historyLimit: utils.IntPointer(3),
+			history: []v1beta1.ConfigCheckRecord{
+				record("fluentd", nil, "d", true),
+				record("fluentd", nil, "c", true),
+				record("fluentd", nil, "b", true),
+				record("fluentd", nil, "a", true),
+			},
+			record:    record("fluentd", nil, "e", true),
+			want:      []string{"e", "d", "c"},
+			wantValid: []bool{true, true, true},
+		},
+		"replaced and truncated": {
+			historyLimit: utils.IntPointer(2),
+			history: []v1beta1.ConfigCheckRecord{
+				record("fluentd", nil, "c", true),
+				record("fluentd", nil, "b", true),
+				record("fluentd", nil, "a", true),
+			},
+			record:    record("fluentd", nil, "b", false),
+			want:      []string{"b", "c"},
+			wantValid: []bool{false, true},
+		},
+		"default history limit": {
+			history: func() (history []v1beta1.ConfigCheckRecord) {
+				for i := 0; i < v1beta1.DefaultConfigCheckHistoryLimit; i++ {
+					history = append(history, record("fluentd", nil, string(rune('a'+i)), true))
+				}
+				return
+			}(),
+			record:    record("fluentd", nil, "z", true),
+			want:      []string{"z", "a", "b", "c", "d", "e", "f", "g", "h", "i"},
+			wantValid: []bool{true, true, true, true, true, true, true, true, true, true},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			logging := v1beta1.Logging{
+				Status: v1beta1.LoggingStatus{ConfigCheckHistory: testCase.history},
+			}
+			if testCase.historyLimit != nil {
+				logging.Spec.ConfigCheck = &v1beta1.ConfigCheck{HistoryLimit: testCase.historyLimit}
+			}
+			logging.RecordConfigCheck(testCase.record)
+			assert.Equal(t, testCase.want, hashes(logging.Status.ConfigCheckHistory))
+			var valid []bool
+			for _, r := range logging.Status.ConfigCheckHistory {
+				valid = append(valid, r.Valid)
+			}
+			assert.Equal(t, testCase.wantValid, valid)
+		})
+	}
+}
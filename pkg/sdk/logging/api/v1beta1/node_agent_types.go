@@ -110,6 +110,7 @@ type NodeAgentFluentbit struct {
 	VarLogsPath             string                  `json:"varLogsPath,omitempty"`
 	ExtraVolumeMounts       []*VolumeMount          `json:"extraVolumeMounts,omitempty"`
 	InputTail               InputTail               `json:"inputTail,omitempty"`
+	InputSystemd            *InputSystemd           `json:"inputSystemd,omitempty"`
 	FilterAws               *FilterAws              `json:"filterAws,omitempty"`
 	FilterKubernetes        FilterKubernetes        `json:"filterKubernetes,omitempty"`
 	DisableKubernetesFilter *bool                   `json:"disableKubernetesFilter,omitempty"`
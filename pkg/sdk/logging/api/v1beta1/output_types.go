@@ -58,13 +58,33 @@ type OutputSpec struct {
 	SQSOutputConfig              *output.SQSOutputConfig              `json:"sqs,omitempty"`
 	MattermostOutputConfig       *output.MattermostOutputConfig       `json:"mattermost,omitempty"`
 	RelabelOutputConfig          *output.RelabelOutputConfig          `json:"relabel,omitempty"`
+
+	// Output or ClusterOutput receiving the chunks this output failed to flush, rendered as a `<secondary>` section.
+	// An Output in the same namespace takes precedence over a ClusterOutput with the same name, ClusterOutputs can only reference ClusterOutputs.
+	// Only the plugin parameters of the referenced output are used, its buffer is ignored.
+	SecondaryOutputRef string `json:"secondaryOutputRef,omitempty"`
 }
 
+const (
+	// OutputConditionSecretsResolved is true when every secret referenced by the output can be loaded
+	OutputConditionSecretsResolved = "SecretsResolved"
+
+	// OutputReasonSecretsLoaded means the referenced secrets have been loaded
+	OutputReasonSecretsLoaded = "SecretsLoaded"
+	// OutputReasonSecretResolutionFailed means some of the referenced secrets cannot be loaded, the message contains the errors
+	OutputReasonSecretResolutionFailed = "SecretResolutionFailed"
+)
+
 // OutputStatus defines the observed state of Output
 type OutputStatus struct {
 	Active        *bool    `json:"active,omitempty"`
 	Problems      []string `json:"problems,omitempty"`
 	ProblemsCount int      `json:"problemsCount,omitempty"`
+	// Flows and other resources sending records to the output, in Kind/namespace/name form
+	ReferencedBy []string `json:"referencedBy,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
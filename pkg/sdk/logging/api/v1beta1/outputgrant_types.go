@@ -0,0 +1,123 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +name:"OutputGrantSpec"
+// +weight:"200"
+type _hugoOutputGrantSpec interface{} //nolint:deadcode,unused
+
+// +name:"OutputGrantSpec"
+// +version:"v1beta1"
+// +description:"OutputGrantSpec allows flows from other namespaces to reference outputs in the namespace of the grant"
+type _metaOutputGrantSpec interface{} //nolint:deadcode,unused
+
+const (
+	OutputGrantKindFlow           = "Flow"
+	OutputGrantKindSyslogNGFlow   = "SyslogNGFlow"
+	OutputGrantKindOutput         = "Output"
+	OutputGrantKindSyslogNGOutput = "SyslogNGOutput"
+)
+
+// OutputGrantSpec allows flows from other namespaces to reference outputs in the namespace of the grant.
+// It is modelled after the ReferenceGrant resource of the Gateway API.
+type OutputGrantSpec struct {
+	// Flows that are allowed to reference the outputs listed in To
+	From []OutputGrantFrom `json:"from"`
+	// Outputs in the namespace of the grant that can be referenced by the flows listed in From
+	To []OutputGrantTo `json:"to"`
+}
+
+// OutputGrantFrom describes the flows that are allowed to reference outputs
+type OutputGrantFrom struct {
+	// Kind of the referencing flow
+	// +kubebuilder:validation:Enum=Flow;SyslogNGFlow
+	Kind string `json:"kind"`
+	// Namespace of the referencing flow
+	Namespace string `json:"namespace"`
+}
+
+// OutputGrantTo describes the outputs that can be referenced
+type OutputGrantTo struct {
+	// Kind of the referenced output
+	// +kubebuilder:validation:Enum=Output;SyslogNGOutput
+	Kind string `json:"kind"`
+	// Name of the referenced output, all outputs of the given kind are granted if empty
+	Name string `json:"name,omitempty"`
+}
+
+// OutputReference points to an output in a given namespace
+type OutputReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=logging-all
+
+// OutputGrant allows flows from other namespaces to reference outputs in the namespace of the grant
+type OutputGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OutputGrantSpec `json:"spec,omitempty"`
+}
+
+// Allows returns true if the grant permits a flow of the given kind from the given namespace
+// to reference the output of the given kind and name in the namespace of the grant
+func (g *OutputGrant) Allows(fromKind, fromNamespace, toKind, toName string) bool {
+	from := false
+	for _, f := range g.Spec.From {
+		if f.Kind == fromKind && f.Namespace == fromNamespace {
+			from = true
+			break
+		}
+	}
+	if !from {
+		return false
+	}
+	for _, t := range g.Spec.To {
+		if t.Kind == toKind && (t.Name == "" || t.Name == toName) {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+
+// OutputGrantList contains a list of OutputGrant
+type OutputGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OutputGrant `json:"items"`
+}
+
+// OutputGrantAllows returns true if any of the grants in the namespace of the output permits the reference
+func OutputGrantAllows(grants []OutputGrant, fromKind, fromNamespace, toKind string, to OutputReference) bool {
+	for i := range grants {
+		if grants[i].Namespace == to.Namespace && grants[i].Allows(fromKind, fromNamespace, toKind, to.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&OutputGrant{}, &OutputGrantList{})
+}
@@ -0,0 +1,78 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// +name:"SecretBackends"
+// +weight:"200"
+type _hugoSecretBackends interface{} //nolint:deadcode,unused
+
+// +name:"SecretBackends"
+// +version:"v1beta1"
+// +description:"SecretBackends configures secret sources other than Kubernetes Secrets"
+type _metaSecretBackends interface{} //nolint:deadcode,unused
+
+// SecretBackends configures secret sources other than Kubernetes Secrets for output credentials.
+// Backends are referenced from the usual `valueFrom` or `mountFrom` secret fields by prefixing the
+// name of the secretKeyRef with the scheme of the backend, e.g. `csi:vault-creds` or `kv:elastic`.
+// Kubernetes Secret names cannot contain a colon, so these references never clash with real Secrets.
+type SecretBackends struct {
+	// CSI volumes (e.g. of the secrets store CSI driver) mounted into the aggregator.
+	// Referenced as `csi:<name>` with the file name as key, only with `mountFrom`.
+	CSI []CSISecretBackend `json:"csi,omitempty"`
+	// Projected service account tokens mounted into the aggregator.
+	// Referenced as `serviceaccounttoken:<name>`, only with `mountFrom`.
+	ServiceAccountTokens []ServiceAccountTokenSecretBackend `json:"serviceAccountTokens,omitempty"`
+	// Key-value store with a Vault KV version 2 compatible HTTP API, read by the operator.
+	// Referenced as `kv:<name>` with the field name as key. Names are resolved in the namespace of the referencing resource and cannot contain path separators.
+	KV *KVSecretBackend `json:"kv,omitempty"`
+}
+
+// CSISecretBackend is a CSI volume mounted into the aggregator
+type CSISecretBackend struct {
+	// Name used to reference the volume
+	Name string `json:"name"`
+	// CSI driver of the volume (default: secrets-store.csi.k8s.io)
+	Driver string `json:"driver,omitempty"`
+	// Attributes passed to the CSI driver, e.g. secretProviderClass
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+	// Namespaces allowed to reference the volume, all namespaces if empty
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// ServiceAccountTokenSecretBackend is a projected service account token mounted into the aggregator
+type ServiceAccountTokenSecretBackend struct {
+	// Name used to reference the token
+	Name string `json:"name"`
+	// Intended audience of the token
+	Audience string `json:"audience,omitempty"`
+	// Requested lifetime of the token (default: 3600)
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+	// Namespaces allowed to reference the token, all namespaces if empty
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// KVSecretBackend is a key-value store with a Vault KV version 2 compatible HTTP API
+type KVSecretBackend struct {
+	// Address of the store, e.g. http://vault.vault.svc:8200
+	Address string `json:"address"`
+	// Mount path of the KV engine (default: secret)
+	Mount string `json:"mount,omitempty"`
+	// Secret in the control namespace holding the token used to authenticate to the store
+	TokenSecret *corev1.SecretKeySelector `json:"tokenSecret,omitempty"`
+}
@@ -35,6 +35,8 @@ type SyslogNGFlowSpec struct {
 	LoggingRef       string           `json:"loggingRef,omitempty"`
 	GlobalOutputRefs []string         `json:"globalOutputRefs,omitempty"`
 	LocalOutputRefs  []string         `json:"localOutputRefs,omitempty"`
+	// Outputs in other namespaces, each of them has to be permitted by an OutputGrant in the namespace of the output
+	SharedOutputRefs []OutputReference `json:"sharedOutputRefs,omitempty"`
 }
 
 type SyslogNGMatch filter.MatchExpr
@@ -47,10 +47,90 @@ type SyslogNGSpec struct {
 	JSONKeyDelimiter                    string                       `json:"jsonKeyDelim,omitempty"`
 	MaxConnections                      int                          `json:"maxConnections,omitempty"`
 	LogIWSize                           int                          `json:"logIWSize,omitempty"`
+	// Filters applied to every record received by syslog-ng, before any of the flows
+	GlobalFilters []SyslogNGFilter `json:"globalFilters,omitempty"`
+	// Flow receiving the records that are not matched by any of the flows
+	DefaultFlow *SyslogNGDefaultFlowSpec `json:"defaultFlow,omitempty"`
+	// SyslogNGClusterOutput receiving the records of the main source that cannot be parsed as JSON, instead of dropping them.
+	// Errors of the filters and outputs of the flows are not redirected here.
+	ParseErrorOutputRef string `json:"parseErrorOutputRef,omitempty"`
+	// Additional sources receiving logs from outside of the cluster, e.g. from network appliances
+	Sources []SyslogNGSource `json:"sources,omitempty"`
+	// Restrict the traffic of the syslog-ng pods with a NetworkPolicy, allowing records from the fluent-bit agents and the sources only
+	// and connections to the listed egress endpoints
+	NetworkPolicy *AggregatorNetworkPolicy `json:"networkPolicy,omitempty"`
+	// Pod Security Standards level the syslog-ng pods have to comply with.
+	// The control namespace is labelled for Pod Security Admission accordingly, see https://kubernetes.io/docs/concepts/security/pod-security-admission/
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	PodSecurityStandard string `json:"podSecurityStandard,omitempty"`
 
 	// TODO: option to turn on/off buffer volume PVC
 }
 
+// SyslogNGDefaultFlowSpec is a log path with the fallback flag, processing the records that are not matched by any flow
+type SyslogNGDefaultFlowSpec struct {
+	Filters          []SyslogNGFilter `json:"filters,omitempty"`
+	GlobalOutputRefs []string         `json:"globalOutputRefs,omitempty"`
+}
+
+// SyslogNGSource is an additional source of the syslog-ng aggregator, exposed on the syslog-ng service.
+// Records of the source are attributed to a namespace, so they are processed by the flows of that namespace and by the cluster flows.
+// Flows can select the records of a source by matching the `${SOURCE}` template against `source_<name>`.
+// Exactly one of the source types has to be set.
+type SyslogNGSource struct {
+	// Name of the source, also used as the name of the service port
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name"`
+	// Port the source listens on
+	Port int32 `json:"port"`
+	// Namespace the records of the source are attributed to (default: the control namespace)
+	Namespace string `json:"namespace,omitempty"`
+	// Syslog messages in RFC5424 or RFC3164 format
+	Syslog *SyslogNGSyslogSource `json:"syslog,omitempty"`
+	// Messages in any format supported by the network() driver
+	Network *SyslogNGNetworkSource `json:"network,omitempty"`
+	// OpenTelemetry logs received over OTLP/gRPC
+	OpenTelemetry *SyslogNGOpenTelemetrySource `json:"opentelemetry,omitempty"`
+}
+
+// SyslogNGSyslogSource configures the syslog() source driver
+type SyslogNGSyslogSource struct {
+	// Transport protocol, the tls transport uses the certificate of the syslog-ng TLS settings (default: tcp)
+	// +kubebuilder:validation:Enum=tcp;udp;tls
+	Transport string `json:"transport,omitempty"`
+	// Flags of the source, e.g. no-multi-line
+	Flags []string `json:"flags,omitempty"`
+}
+
+// SyslogNGNetworkSource configures the network() source driver
+type SyslogNGNetworkSource struct {
+	// Transport protocol, the tls transport uses the certificate of the syslog-ng TLS settings (default: tcp)
+	// +kubebuilder:validation:Enum=tcp;udp;tls
+	Transport string `json:"transport,omitempty"`
+	// Flags of the source, e.g. syslog-protocol or no-parse
+	Flags []string `json:"flags,omitempty"`
+}
+
+// SyslogNGOpenTelemetrySource configures the opentelemetry() source driver
+type SyslogNGOpenTelemetrySource struct {
+}
+
+// Transport returns the transport protocol of the source
+func (s SyslogNGSource) Transport() string {
+	var transport string
+	switch {
+	case s.Syslog != nil:
+		transport = s.Syslog.Transport
+	case s.Network != nil:
+		transport = s.Network.Transport
+	}
+	if transport == "" {
+		return "tcp"
+	}
+	return transport
+}
+
 // +kubebuilder:object:generate=true
 
 // SyslogNGTLS defines the TLS configs
@@ -58,6 +138,9 @@ type SyslogNGTLS struct {
 	Enabled    bool   `json:"enabled"`
 	SecretName string `json:"secretName,omitempty"`
 	SharedKey  string `json:"sharedKey,omitempty"`
+	// Verification of the client certificates on the network source (default: required-trusted)
+	// +kubebuilder:validation:Enum=optional-trusted;optional-untrusted;required-trusted;required-untrusted
+	PeerVerify string `json:"peerVerify,omitempty"`
 }
 
 type GlobalOptions struct {
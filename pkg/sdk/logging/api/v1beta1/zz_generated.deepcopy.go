@@ -20,6 +20,7 @@
 package v1beta1
 
 import (
+	"github.com/cisco-open/operator-tools/pkg/secret"
 	"github.com/cisco-open/operator-tools/pkg/typeoverride"
 	"github.com/cisco-open/operator-tools/pkg/volume"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
@@ -29,10 +30,55 @@ import (
 	syslogngoutput "github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/output"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregatorNetworkPolicy) DeepCopyInto(out *AggregatorNetworkPolicy) {
+	*out = *in
+	if in.AdditionalIngressFrom != nil {
+		in, out := &in.AdditionalIngressFrom, &out.AdditionalIngressFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregatorNetworkPolicy.
+func (in *AggregatorNetworkPolicy) DeepCopy() *AggregatorNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregatorNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregatorStatus) DeepCopyInto(out *AggregatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregatorStatus.
+func (in *AggregatorStatus) DeepCopy() *AggregatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BufferMetrics) DeepCopyInto(out *BufferMetrics) {
 	*out = *in
@@ -64,6 +110,33 @@ func (in *BufferStorage) DeepCopy() *BufferStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSISecretBackend) DeepCopyInto(out *CSISecretBackend) {
+	*out = *in
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSISecretBackend.
+func (in *CSISecretBackend) DeepCopy() *CSISecretBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(CSISecretBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExclude) DeepCopyInto(out *ClusterExclude) {
 	*out = *in
@@ -353,6 +426,70 @@ func (in *ClusterSelect) DeepCopy() *ClusterSelect {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCanaryStatus) DeepCopyInto(out *ConfigCanaryStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReadyTime != nil {
+		in, out := &in.ReadyTime, &out.ReadyTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCanaryStatus.
+func (in *ConfigCanaryStatus) DeepCopy() *ConfigCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCheck) DeepCopyInto(out *ConfigCheck) {
+	*out = *in
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCheck.
+func (in *ConfigCheck) DeepCopy() *ConfigCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCheckRecord) DeepCopyInto(out *ConfigCheckRecord) {
+	*out = *in
+	if in.Shard != nil {
+		in, out := &in.Shard, &out.Shard
+		*out = new(int32)
+		**out = **in
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCheckRecord.
+func (in *ConfigCheckRecord) DeepCopy() *ConfigCheckRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCheckRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DefaultFlowSpec) DeepCopyInto(out *DefaultFlowSpec) {
 	*out = *in
@@ -894,6 +1031,11 @@ func (in *FlowSpec) DeepCopyInto(out *FlowSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SharedOutputRefs != nil {
+		in, out := &in.SharedOutputRefs, &out.SharedOutputRefs
+		*out = make([]OutputReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowSpec.
@@ -919,6 +1061,13 @@ func (in *FlowStatus) DeepCopyInto(out *FlowStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowStatus.
@@ -931,6 +1080,140 @@ func (in *FlowStatus) DeepCopy() *FlowStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowTap) DeepCopyInto(out *FlowTap) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowTap.
+func (in *FlowTap) DeepCopy() *FlowTap {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowTap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlowTap) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowTapFlowRef) DeepCopyInto(out *FlowTapFlowRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowTapFlowRef.
+func (in *FlowTapFlowRef) DeepCopy() *FlowTapFlowRef {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowTapFlowRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowTapList) DeepCopyInto(out *FlowTapList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FlowTap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowTapList.
+func (in *FlowTapList) DeepCopy() *FlowTapList {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowTapList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlowTapList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowTapSpec) DeepCopyInto(out *FlowTapSpec) {
+	*out = *in
+	out.FlowRef = in.FlowRef
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Stdout != nil {
+		in, out := &in.Stdout, &out.Stdout
+		*out = new(filter.StdOutFilterConfig)
+		**out = **in
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(output.FileOutputConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowTapSpec.
+func (in *FlowTapSpec) DeepCopy() *FlowTapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowTapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowTapStatus) DeepCopyInto(out *FlowTapStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Problems != nil {
+		in, out := &in.Problems, &out.Problems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowTapStatus.
+func (in *FlowTapStatus) DeepCopy() *FlowTapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowTapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentOutLogrotate) DeepCopyInto(out *FluentOutLogrotate) {
 	*out = *in
@@ -952,7 +1235,7 @@ func (in *FluentbitAgent) DeepCopyInto(out *FluentbitAgent) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitAgent.
@@ -1006,46 +1289,504 @@ func (in *FluentbitAgentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FluentbitNetwork) DeepCopyInto(out *FluentbitNetwork) {
+func (in *FluentbitAgentStatus) DeepCopyInto(out *FluentbitAgentStatus) {
 	*out = *in
-	if in.ConnectTimeout != nil {
-		in, out := &in.ConnectTimeout, &out.ConnectTimeout
-		*out = new(uint32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitAgentStatus.
+func (in *FluentbitAgentStatus) DeepCopy() *FluentbitAgentStatus {
+	if in == nil {
+		return nil
 	}
-	if in.ConnectTimeoutLogError != nil {
-		in, out := &in.ConnectTimeoutLogError, &out.ConnectTimeoutLogError
-		*out = new(bool)
-		**out = **in
+	out := new(FluentbitAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitAuditLogs) DeepCopyInto(out *FluentbitAuditLogs) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitAuditLogs.
+func (in *FluentbitAuditLogs) DeepCopy() *FluentbitAuditLogs {
+	if in == nil {
+		return nil
 	}
-	if in.DNSPreferIPV4 != nil {
-		in, out := &in.DNSPreferIPV4, &out.DNSPreferIPV4
-		*out = new(bool)
-		**out = **in
+	out := new(FluentbitAuditLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitDirectOutput) DeepCopyInto(out *FluentbitDirectOutput) {
+	*out = *in
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(FluentbitLokiOutput)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Keepalive != nil {
-		in, out := &in.Keepalive, &out.Keepalive
-		*out = new(bool)
-		**out = **in
+	if in.Elasticsearch != nil {
+		in, out := &in.Elasticsearch, &out.Elasticsearch
+		*out = new(FluentbitElasticsearchOutput)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.KeepaliveIdleTimeout != nil {
-		in, out := &in.KeepaliveIdleTimeout, &out.KeepaliveIdleTimeout
-		*out = new(uint32)
-		**out = **in
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(FluentbitOpenTelemetryOutput)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.KeepaliveMaxRecycle != nil {
-		in, out := &in.KeepaliveMaxRecycle, &out.KeepaliveMaxRecycle
-		*out = new(uint32)
-		**out = **in
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(FluentbitKafkaOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(FluentbitS3Output)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitNetwork.
-func (in *FluentbitNetwork) DeepCopy() *FluentbitNetwork {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitDirectOutput.
+func (in *FluentbitDirectOutput) DeepCopy() *FluentbitDirectOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(FluentbitNetwork)
+	out := new(FluentbitDirectOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitElasticsearchOutput) DeepCopyInto(out *FluentbitElasticsearchOutput) {
+	*out = *in
+	if in.HTTPUser != nil {
+		in, out := &in.HTTPUser, &out.HTTPUser
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPPasswd != nil {
+		in, out := &in.HTTPPasswd, &out.HTTPPasswd
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudAuth != nil {
+		in, out := &in.CloudAuth, &out.CloudAuth
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogstashFormat != nil {
+		in, out := &in.LogstashFormat, &out.LogstashFormat
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SuppressTypeName != nil {
+		in, out := &in.SuppressTypeName, &out.SuppressTypeName
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplaceDots != nil {
+		in, out := &in.ReplaceDots, &out.ReplaceDots
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TraceError != nil {
+		in, out := &in.TraceError, &out.TraceError
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GenerateID != nil {
+		in, out := &in.GenerateID, &out.GenerateID
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AWSAuth != nil {
+		in, out := &in.AWSAuth, &out.AWSAuth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSVerify != nil {
+		in, out := &in.TLSVerify, &out.TLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitElasticsearchOutput.
+func (in *FluentbitElasticsearchOutput) DeepCopy() *FluentbitElasticsearchOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitElasticsearchOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitFilter) DeepCopyInto(out *FluentbitFilter) {
+	*out = *in
+	if in.Grep != nil {
+		in, out := &in.Grep, &out.Grep
+		*out = new(FluentbitGrepFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lua != nil {
+		in, out := &in.Lua, &out.Lua
+		*out = new(FluentbitLuaFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RewriteTag != nil {
+		in, out := &in.RewriteTag, &out.RewriteTag
+		*out = new(FluentbitRewriteTagFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Throttle != nil {
+		in, out := &in.Throttle, &out.Throttle
+		*out = new(FluentbitThrottleFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Nest != nil {
+		in, out := &in.Nest, &out.Nest
+		*out = new(FluentbitNestFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Multiline != nil {
+		in, out := &in.Multiline, &out.Multiline
+		*out = new(FluentbitMultilineFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitFilter.
+func (in *FluentbitFilter) DeepCopy() *FluentbitFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitGrepFilter) DeepCopyInto(out *FluentbitGrepFilter) {
+	*out = *in
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitGrepFilter.
+func (in *FluentbitGrepFilter) DeepCopy() *FluentbitGrepFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitGrepFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitKafkaOutput) DeepCopyInto(out *FluentbitKafkaOutput) {
+	*out = *in
+	if in.SASLUsername != nil {
+		in, out := &in.SASLUsername, &out.SASLUsername
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SASLPassword != nil {
+		in, out := &in.SASLPassword, &out.SASLPassword
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RDKafkaOptions != nil {
+		in, out := &in.RDKafkaOptions, &out.RDKafkaOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitKafkaOutput.
+func (in *FluentbitKafkaOutput) DeepCopy() *FluentbitKafkaOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitKafkaOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitLokiOutput) DeepCopyInto(out *FluentbitLokiOutput) {
+	*out = *in
+	if in.HTTPUser != nil {
+		in, out := &in.HTTPUser, &out.HTTPUser
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPPasswd != nil {
+		in, out := &in.HTTPPasswd, &out.HTTPPasswd
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoKubernetesLabels != nil {
+		in, out := &in.AutoKubernetesLabels, &out.AutoKubernetesLabels
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSVerify != nil {
+		in, out := &in.TLSVerify, &out.TLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitLokiOutput.
+func (in *FluentbitLokiOutput) DeepCopy() *FluentbitLokiOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitLokiOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitLuaFilter) DeepCopyInto(out *FluentbitLuaFilter) {
+	*out = *in
+	in.Script.DeepCopyInto(&out.Script)
+	if in.ProtectedMode != nil {
+		in, out := &in.ProtectedMode, &out.ProtectedMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TimeAsTable != nil {
+		in, out := &in.TimeAsTable, &out.TimeAsTable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitLuaFilter.
+func (in *FluentbitLuaFilter) DeepCopy() *FluentbitLuaFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitLuaFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitMultilineFilter) DeepCopyInto(out *FluentbitMultilineFilter) {
+	*out = *in
+	if in.Parsers != nil {
+		in, out := &in.Parsers, &out.Parsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Buffer != nil {
+		in, out := &in.Buffer, &out.Buffer
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitMultilineFilter.
+func (in *FluentbitMultilineFilter) DeepCopy() *FluentbitMultilineFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitMultilineFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitNestFilter) DeepCopyInto(out *FluentbitNestFilter) {
+	*out = *in
+	if in.Wildcard != nil {
+		in, out := &in.Wildcard, &out.Wildcard
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitNestFilter.
+func (in *FluentbitNestFilter) DeepCopy() *FluentbitNestFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitNestFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitNetwork) DeepCopyInto(out *FluentbitNetwork) {
+	*out = *in
+	if in.ConnectTimeout != nil {
+		in, out := &in.ConnectTimeout, &out.ConnectTimeout
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.ConnectTimeoutLogError != nil {
+		in, out := &in.ConnectTimeoutLogError, &out.ConnectTimeoutLogError
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DNSPreferIPV4 != nil {
+		in, out := &in.DNSPreferIPV4, &out.DNSPreferIPV4
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Keepalive != nil {
+		in, out := &in.Keepalive, &out.Keepalive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeepaliveIdleTimeout != nil {
+		in, out := &in.KeepaliveIdleTimeout, &out.KeepaliveIdleTimeout
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.KeepaliveMaxRecycle != nil {
+		in, out := &in.KeepaliveMaxRecycle, &out.KeepaliveMaxRecycle
+		*out = new(uint32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitNetwork.
+func (in *FluentbitNetwork) DeepCopy() *FluentbitNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitOpenTelemetryOutput) DeepCopyInto(out *FluentbitOpenTelemetryOutput) {
+	*out = *in
+	if in.HTTPUser != nil {
+		in, out := &in.HTTPUser, &out.HTTPUser
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPPasswd != nil {
+		in, out := &in.HTTPPasswd, &out.HTTPPasswd
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]*secret.Secret, len(*in))
+		for key, val := range *in {
+			var outVal *secret.Secret
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(secret.Secret)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSVerify != nil {
+		in, out := &in.TLSVerify, &out.TLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitOpenTelemetryOutput.
+func (in *FluentbitOpenTelemetryOutput) DeepCopy() *FluentbitOpenTelemetryOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitOpenTelemetryOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitRewriteTagFilter) DeepCopyInto(out *FluentbitRewriteTagFilter) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitRewriteTagFilter.
+func (in *FluentbitRewriteTagFilter) DeepCopy() *FluentbitRewriteTagFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitRewriteTagFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitS3Output) DeepCopyInto(out *FluentbitS3Output) {
+	*out = *in
+	if in.UsePutObject != nil {
+		in, out := &in.UsePutObject, &out.UsePutObject
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccessKeyID != nil {
+		in, out := &in.AccessKeyID, &out.AccessKeyID
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(secret.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitS3Output.
+func (in *FluentbitS3Output) DeepCopy() *FluentbitS3Output {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitS3Output)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1135,6 +1876,23 @@ func (in *FluentbitSpec) DeepCopyInto(out *FluentbitSpec) {
 		}
 	}
 	in.InputTail.DeepCopyInto(&out.InputTail)
+	if in.InputTails != nil {
+		in, out := &in.InputTails, &out.InputTails
+		*out = make([]FluentbitTailInput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InputSystemd != nil {
+		in, out := &in.InputSystemd, &out.InputSystemd
+		*out = new(InputSystemd)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLogs != nil {
+		in, out := &in.AuditLogs, &out.AuditLogs
+		*out = new(FluentbitAuditLogs)
+		**out = **in
+	}
 	if in.FilterAws != nil {
 		in, out := &in.FilterAws, &out.FilterAws
 		*out = new(FilterAws)
@@ -1203,6 +1961,20 @@ func (in *FluentbitSpec) DeepCopyInto(out *FluentbitSpec) {
 		**out = **in
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.DirectOutputs != nil {
+		in, out := &in.DirectOutputs, &out.DirectOutputs
+		*out = make([]FluentbitDirectOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]FluentbitFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitSpec.
@@ -1218,6 +1990,11 @@ func (in *FluentbitSpec) DeepCopy() *FluentbitSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentbitStatus) DeepCopyInto(out *FluentbitStatus) {
 	*out = *in
+	if in.Problems != nil {
+		in, out := &in.Problems, &out.Problems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitStatus.
@@ -1265,6 +2042,67 @@ func (in *FluentbitTLS) DeepCopy() *FluentbitTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitTailInput) DeepCopyInto(out *FluentbitTailInput) {
+	*out = *in
+	in.InputTail.DeepCopyInto(&out.InputTail)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitTailInput.
+func (in *FluentbitTailInput) DeepCopy() *FluentbitTailInput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitTailInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentbitThrottleFilter) DeepCopyInto(out *FluentbitThrottleFilter) {
+	*out = *in
+	if in.PrintStatus != nil {
+		in, out := &in.PrintStatus, &out.PrintStatus
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentbitThrottleFilter.
+func (in *FluentbitThrottleFilter) DeepCopy() *FluentbitThrottleFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentbitThrottleFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentdCanary) DeepCopyInto(out *FluentdCanary) {
+	*out = *in
+	if in.AnalysisDuration != nil {
+		in, out := &in.AnalysisDuration, &out.AnalysisDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdCanary.
+func (in *FluentdCanary) DeepCopy() *FluentdCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentdCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentdDrainConfig) DeepCopyInto(out *FluentdDrainConfig) {
 	*out = *in
@@ -1289,6 +2127,41 @@ func (in *FluentdDrainConfig) DeepCopy() *FluentdDrainConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentdInput) DeepCopyInto(out *FluentdInput) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(input.HTTPInputConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(input.SyslogInputConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCP != nil {
+		in, out := &in.TCP, &out.TCP
+		*out = new(input.TCPInputConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UDP != nil {
+		in, out := &in.UDP, &out.UDP
+		*out = new(input.UDPInputConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdInput.
+func (in *FluentdInput) DeepCopy() *FluentdInput {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentdInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentdScaling) DeepCopyInto(out *FluentdScaling) {
 	*out = *in
@@ -1305,6 +2178,28 @@ func (in *FluentdScaling) DeepCopy() *FluentdScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluentdShards) DeepCopyInto(out *FluentdShards) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdShards.
+func (in *FluentdShards) DeepCopy() *FluentdShards {
+	if in == nil {
+		return nil
+	}
+	out := new(FluentdShards)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentdSpec) DeepCopyInto(out *FluentdSpec) {
 	*out = *in
@@ -1437,6 +2332,13 @@ func (in *FluentdSpec) DeepCopyInto(out *FluentdSpec) {
 		*out = new(input.ForwardInputConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExtraInputs != nil {
+		in, out := &in.ExtraInputs, &out.ExtraInputs
+		*out = make([]FluentdInput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ServiceAccountOverrides != nil {
 		in, out := &in.ServiceAccountOverrides, &out.ServiceAccountOverrides
 		*out = new(typeoverride.ServiceAccount)
@@ -1452,6 +2354,21 @@ func (in *FluentdSpec) DeepCopyInto(out *FluentdSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(AggregatorNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = new(FluentdShards)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(FluentdCanary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdSpec.
@@ -1560,6 +2477,26 @@ func (in *InlineNodeAgent) DeepCopy() *InlineNodeAgent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputSystemd) DeepCopyInto(out *InputSystemd) {
+	*out = *in
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputSystemd.
+func (in *InputSystemd) DeepCopy() *InputSystemd {
+	if in == nil {
+		return nil
+	}
+	out := new(InputSystemd)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InputTail) DeepCopyInto(out *InputTail) {
 	*out = *in
@@ -1595,6 +2532,26 @@ func (in *InputTail) DeepCopy() *InputTail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KVSecretBackend) DeepCopyInto(out *KVSecretBackend) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KVSecretBackend.
+func (in *KVSecretBackend) DeepCopy() *KVSecretBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(KVSecretBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Logging) DeepCopyInto(out *Logging) {
 	*out = *in
@@ -1657,6 +2614,11 @@ func (in *LoggingList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
 	*out = *in
+	if in.ConfigCheck != nil {
+		in, out := &in.ConfigCheck, &out.ConfigCheck
+		*out = new(ConfigCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FluentbitSpec != nil {
 		in, out := &in.FluentbitSpec, &out.FluentbitSpec
 		*out = new(FluentbitSpec)
@@ -1699,6 +2661,11 @@ func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SecretBackends != nil {
+		in, out := &in.SecretBackends, &out.SecretBackends
+		*out = new(SecretBackends)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodeAgents != nil {
 		in, out := &in.NodeAgents, &out.NodeAgents
 		*out = make([]*InlineNodeAgent, len(*in))
@@ -1737,6 +2704,37 @@ func (in *LoggingStatus) DeepCopyInto(out *LoggingStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Aggregator != nil {
+		in, out := &in.Aggregator, &out.Aggregator
+		*out = new(AggregatorStatus)
+		**out = **in
+	}
+	if in.FluentbitAgents != nil {
+		in, out := &in.FluentbitAgents, &out.FluentbitAgents
+		*out = make([]FluentbitAgentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSuccessfulReconcile != nil {
+		in, out := &in.LastSuccessfulReconcile, &out.LastSuccessfulReconcile
+		*out = (*in).DeepCopy()
+	}
+	if in.DrainJobs != nil {
+		in, out := &in.DrainJobs, &out.DrainJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigCanary != nil {
+		in, out := &in.ConfigCanary, &out.ConfigCanary
+		*out = new(ConfigCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigCheckHistory != nil {
+		in, out := &in.ConfigCheckHistory, &out.ConfigCheckHistory
+		*out = make([]ConfigCheckRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingStatus.
@@ -1889,6 +2887,11 @@ func (in *NodeAgentFluentbit) DeepCopyInto(out *NodeAgentFluentbit) {
 		}
 	}
 	in.InputTail.DeepCopyInto(&out.InputTail)
+	if in.InputSystemd != nil {
+		in, out := &in.InputSystemd, &out.InputSystemd
+		*out = new(InputSystemd)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FilterAws != nil {
 		in, out := &in.FilterAws, &out.FilterAws
 		*out = new(FilterAws)
@@ -1924,42 +2927,141 @@ func (in *NodeAgentFluentbit) DeepCopyInto(out *NodeAgentFluentbit) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentFluentbit.
-func (in *NodeAgentFluentbit) DeepCopy() *NodeAgentFluentbit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentFluentbit.
+func (in *NodeAgentFluentbit) DeepCopy() *NodeAgentFluentbit {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAgentFluentbit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAgentList) DeepCopyInto(out *NodeAgentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeAgent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentList.
+func (in *NodeAgentList) DeepCopy() *NodeAgentList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAgentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeAgentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAgentSpec) DeepCopyInto(out *NodeAgentSpec) {
+	*out = *in
+	in.NodeAgentConfig.DeepCopyInto(&out.NodeAgentConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentSpec.
+func (in *NodeAgentSpec) DeepCopy() *NodeAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAgentStatus) DeepCopyInto(out *NodeAgentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentStatus.
+func (in *NodeAgentStatus) DeepCopy() *NodeAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operation) DeepCopyInto(out *Operation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operation.
+func (in *Operation) DeepCopy() *Operation {
+	if in == nil {
+		return nil
+	}
+	out := new(Operation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Output) DeepCopyInto(out *Output) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
+func (in *Output) DeepCopy() *Output {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeAgentFluentbit)
+	out := new(Output)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Output) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeAgentList) DeepCopyInto(out *NodeAgentList) {
+func (in *OutputGrant) DeepCopyInto(out *OutputGrant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NodeAgent, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentList.
-func (in *NodeAgentList) DeepCopy() *NodeAgentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputGrant.
+func (in *OutputGrant) DeepCopy() *OutputGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeAgentList)
+	out := new(OutputGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeAgentList) DeepCopyObject() runtime.Object {
+func (in *OutputGrant) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1967,78 +3069,92 @@ func (in *NodeAgentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeAgentSpec) DeepCopyInto(out *NodeAgentSpec) {
+func (in *OutputGrantFrom) DeepCopyInto(out *OutputGrantFrom) {
 	*out = *in
-	in.NodeAgentConfig.DeepCopyInto(&out.NodeAgentConfig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentSpec.
-func (in *NodeAgentSpec) DeepCopy() *NodeAgentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputGrantFrom.
+func (in *OutputGrantFrom) DeepCopy() *OutputGrantFrom {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeAgentSpec)
+	out := new(OutputGrantFrom)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeAgentStatus) DeepCopyInto(out *NodeAgentStatus) {
+func (in *OutputGrantList) DeepCopyInto(out *OutputGrantList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OutputGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAgentStatus.
-func (in *NodeAgentStatus) DeepCopy() *NodeAgentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputGrantList.
+func (in *OutputGrantList) DeepCopy() *OutputGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeAgentStatus)
+	out := new(OutputGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OutputGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Operation) DeepCopyInto(out *Operation) {
+func (in *OutputGrantSpec) DeepCopyInto(out *OutputGrantSpec) {
 	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]OutputGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]OutputGrantTo, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operation.
-func (in *Operation) DeepCopy() *Operation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputGrantSpec.
+func (in *OutputGrantSpec) DeepCopy() *OutputGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Operation)
+	out := new(OutputGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Output) DeepCopyInto(out *Output) {
+func (in *OutputGrantTo) DeepCopyInto(out *OutputGrantTo) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
-func (in *Output) DeepCopy() *Output {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputGrantTo.
+func (in *OutputGrantTo) DeepCopy() *OutputGrantTo {
 	if in == nil {
 		return nil
 	}
-	out := new(Output)
+	out := new(OutputGrantTo)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Output) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OutputList) DeepCopyInto(out *OutputList) {
 	*out = *in
@@ -2071,6 +3187,21 @@ func (in *OutputList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputReference) DeepCopyInto(out *OutputReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputReference.
+func (in *OutputReference) DeepCopy() *OutputReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
 	*out = *in
@@ -2234,6 +3365,18 @@ func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReferencedBy != nil {
+		in, out := &in.ReferencedBy, &out.ReferencedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
@@ -2261,6 +3404,40 @@ func (in *ReadinessDefaultCheck) DeepCopy() *ReadinessDefaultCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretBackends) DeepCopyInto(out *SecretBackends) {
+	*out = *in
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = make([]CSISecretBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountTokens != nil {
+		in, out := &in.ServiceAccountTokens, &out.ServiceAccountTokens
+		*out = make([]ServiceAccountTokenSecretBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KV != nil {
+		in, out := &in.KV, &out.KV
+		*out = new(KVSecretBackend)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackends.
+func (in *SecretBackends) DeepCopy() *SecretBackends {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretBackends)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Security) DeepCopyInto(out *Security) {
 	*out = *in
@@ -2323,6 +3500,31 @@ func (in *Select) DeepCopy() *Select {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTokenSecretBackend) DeepCopyInto(out *ServiceAccountTokenSecretBackend) {
+	*out = *in
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTokenSecretBackend.
+func (in *ServiceAccountTokenSecretBackend) DeepCopy() *ServiceAccountTokenSecretBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTokenSecretBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceMonitorConfig) DeepCopyInto(out *ServiceMonitorConfig) {
 	*out = *in
@@ -2607,6 +3809,33 @@ func (in *SyslogNGClusterOutputSpec) DeepCopy() *SyslogNGClusterOutputSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogNGDefaultFlowSpec) DeepCopyInto(out *SyslogNGDefaultFlowSpec) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]SyslogNGFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GlobalOutputRefs != nil {
+		in, out := &in.GlobalOutputRefs, &out.GlobalOutputRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGDefaultFlowSpec.
+func (in *SyslogNGDefaultFlowSpec) DeepCopy() *SyslogNGDefaultFlowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogNGDefaultFlowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyslogNGFilter) DeepCopyInto(out *SyslogNGFilter) {
 	*out = *in
@@ -2723,6 +3952,11 @@ func (in *SyslogNGFlowSpec) DeepCopyInto(out *SyslogNGFlowSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SharedOutputRefs != nil {
+		in, out := &in.SharedOutputRefs, &out.SharedOutputRefs
+		*out = make([]OutputReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGFlowSpec.
@@ -2748,6 +3982,13 @@ func (in *SyslogNGFlowStatus) DeepCopyInto(out *SyslogNGFlowStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGFlowStatus.
@@ -2799,6 +4040,41 @@ func (in *SyslogNGMatch) DeepCopy() *SyslogNGMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogNGNetworkSource) DeepCopyInto(out *SyslogNGNetworkSource) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGNetworkSource.
+func (in *SyslogNGNetworkSource) DeepCopy() *SyslogNGNetworkSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogNGNetworkSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogNGOpenTelemetrySource) DeepCopyInto(out *SyslogNGOpenTelemetrySource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGOpenTelemetrySource.
+func (in *SyslogNGOpenTelemetrySource) DeepCopy() *SyslogNGOpenTelemetrySource {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogNGOpenTelemetrySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyslogNGOutput) DeepCopyInto(out *SyslogNGOutput) {
 	*out = *in
@@ -2926,6 +4202,18 @@ func (in *SyslogNGOutputStatus) DeepCopyInto(out *SyslogNGOutputStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReferencedBy != nil {
+		in, out := &in.ReferencedBy, &out.ReferencedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGOutputStatus.
@@ -2938,6 +4226,36 @@ func (in *SyslogNGOutputStatus) DeepCopy() *SyslogNGOutputStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogNGSource) DeepCopyInto(out *SyslogNGSource) {
+	*out = *in
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(SyslogNGSyslogSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(SyslogNGNetworkSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(SyslogNGOpenTelemetrySource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGSource.
+func (in *SyslogNGSource) DeepCopy() *SyslogNGSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogNGSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyslogNGSpec) DeepCopyInto(out *SyslogNGSpec) {
 	*out = *in
@@ -2988,6 +4306,30 @@ func (in *SyslogNGSpec) DeepCopyInto(out *SyslogNGSpec) {
 		*out = new(GlobalOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GlobalFilters != nil {
+		in, out := &in.GlobalFilters, &out.GlobalFilters
+		*out = make([]SyslogNGFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultFlow != nil {
+		in, out := &in.DefaultFlow, &out.DefaultFlow
+		*out = new(SyslogNGDefaultFlowSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SyslogNGSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(AggregatorNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGSpec.
@@ -3000,6 +4342,26 @@ func (in *SyslogNGSpec) DeepCopy() *SyslogNGSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogNGSyslogSource) DeepCopyInto(out *SyslogNGSyslogSource) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogNGSyslogSource.
+func (in *SyslogNGSyslogSource) DeepCopy() *SyslogNGSyslogSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogNGSyslogSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyslogNGTLS) DeepCopyInto(out *SyslogNGTLS) {
 	*out = *in
@@ -0,0 +1,60 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/common"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+func newInputDirective(pluginType string, config interface{}, parse *filter.ParseSection, transport *common.Transport, secretLoader secret.SecretLoader, id string) (*types.GenericDirective, error) {
+	source := &types.GenericDirective{
+		PluginMeta: types.PluginMeta{
+			Type:      pluginType,
+			Directive: "source",
+			Id:        id + "_" + pluginType,
+		},
+	}
+	if params, err := types.NewStructToStringMapper(secretLoader).StringsMap(config); err != nil {
+		return nil, err
+	} else {
+		source.Params = params
+	}
+	if parse != nil {
+		if section, err := parse.ToDirective(secretLoader, ""); err != nil {
+			return nil, err
+		} else {
+			source.SubDirectives = append(source.SubDirectives, section)
+		}
+	}
+	if transport != nil {
+		if section, err := transport.ToDirective(secretLoader, ""); err != nil {
+			return nil, err
+		} else {
+			source.SubDirectives = append(source.SubDirectives, section)
+		}
+	}
+	return source, nil
+}
+
+// defaultParse keeps the raw message for plugins that require a parser
+func defaultParse(parse *filter.ParseSection) *filter.ParseSection {
+	if parse == nil {
+		return &filter.ParseSection{Type: "none"}
+	}
+	return parse
+}
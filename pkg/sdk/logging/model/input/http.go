@@ -0,0 +1,43 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/common"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// +kubebuilder:object:generate=true
+
+// HTTPInputConfig configures the in_http plugin, the tag of the records is the path of the request
+type HTTPInputConfig struct {
+	Bind             string `json:"bind,omitempty" plugin:"default:0.0.0.0"`
+	BodySizeLimit    string `json:"body_size_limit,omitempty"`
+	KeepaliveTimeout string `json:"keepalive_timeout,omitempty"`
+	AddHTTPHeaders   *bool  `json:"add_http_headers,omitempty"`
+	AddRemoteAddr    *bool  `json:"add_remote_addr,omitempty"`
+	CorsAllowOrigins string `json:"cors_allow_origins,omitempty"`
+	RespondWithEmpty *bool  `json:"respond_with_empty_img,omitempty"`
+	Use204Response   *bool  `json:"use_204_response,omitempty"`
+
+	Parse     *filter.ParseSection `json:"parse,omitempty"`
+	Transport *common.Transport    `json:"transport,omitempty"`
+}
+
+func (c *HTTPInputConfig) ToDirective(secretLoader secret.SecretLoader, id string) (types.Directive, error) {
+	return newInputDirective("http", c, c.Parse, c.Transport, secretLoader, id)
+}
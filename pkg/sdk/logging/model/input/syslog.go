@@ -0,0 +1,67 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/common"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// +kubebuilder:object:generate=true
+
+// SyslogInputConfig configures the in_syslog plugin, the tag of the records is suffixed with the facility and the priority
+type SyslogInputConfig struct {
+	Bind string `json:"bind,omitempty" plugin:"default:0.0.0.0"`
+	// Transport protocol used when TLS is not configured: udp or tcp (default: udp)
+	// +kubebuilder:validation:Enum=udp;tcp
+	Protocol           string `json:"protocol,omitempty" plugin:"hidden"`
+	MessageFormat      string `json:"message_format,omitempty"`
+	FrameType          string `json:"frame_type,omitempty"`
+	MessageLengthLimit string `json:"message_length_limit,omitempty"`
+	SourceHostnameKey  string `json:"source_hostname_key,omitempty"`
+	SourceAddressKey   string `json:"source_address_key,omitempty"`
+	SeverityKey        string `json:"severity_key,omitempty"`
+	FacilityKey        string `json:"facility_key,omitempty"`
+	EmitUnmatchedLines *bool  `json:"emit_unmatched_lines,omitempty"`
+
+	Parse     *filter.ParseSection `json:"parse,omitempty"`
+	Transport *common.Transport    `json:"transport,omitempty"`
+}
+
+func (c *SyslogInputConfig) ToDirective(secretLoader secret.SecretLoader, id string) (types.Directive, error) {
+	directive, err := newInputDirective("syslog", c, c.Parse, c.Transport, secretLoader, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Transport == nil && c.Protocol != "" {
+		directive.SubDirectives = append(directive.SubDirectives, &types.GenericDirective{
+			PluginMeta: types.PluginMeta{
+				Directive: "transport",
+				Tag:       c.Protocol,
+			},
+		})
+	}
+	return directive, nil
+}
+
+// TransportProtocol returns the protocol the input listens on
+func (c *SyslogInputConfig) TransportProtocol() string {
+	if c.Transport != nil || c.Protocol == "tcp" {
+		return "tcp"
+	}
+	return "udp"
+}
@@ -0,0 +1,39 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"github.com/cisco-open/operator-tools/pkg/secret"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// +kubebuilder:object:generate=true
+
+// UDPInputConfig configures the in_udp plugin
+type UDPInputConfig struct {
+	Bind               string `json:"bind,omitempty" plugin:"default:0.0.0.0"`
+	MessageLengthLimit string `json:"message_length_limit,omitempty"`
+	RemoveNewline      *bool  `json:"remove_newline,omitempty"`
+	SourceHostnameKey  string `json:"source_hostname_key,omitempty"`
+	SourceAddressKey   string `json:"source_address_key,omitempty"`
+
+	// Parser of the messages (default: none)
+	Parse *filter.ParseSection `json:"parse,omitempty"`
+}
+
+func (c *UDPInputConfig) ToDirective(secretLoader secret.SecretLoader, id string) (types.Directive, error) {
+	return newInputDirective("udp", c, defaultParse(c.Parse), nil, secretLoader, id)
+}
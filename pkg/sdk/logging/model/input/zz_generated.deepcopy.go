@@ -21,6 +21,7 @@ package input
 
 import (
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/common"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -68,6 +69,106 @@ func (in *ForwardInputConfig) DeepCopy() *ForwardInputConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPInputConfig) DeepCopyInto(out *HTTPInputConfig) {
+	*out = *in
+	if in.AddHTTPHeaders != nil {
+		in, out := &in.AddHTTPHeaders, &out.AddHTTPHeaders
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AddRemoteAddr != nil {
+		in, out := &in.AddRemoteAddr, &out.AddRemoteAddr
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RespondWithEmpty != nil {
+		in, out := &in.RespondWithEmpty, &out.RespondWithEmpty
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Use204Response != nil {
+		in, out := &in.Use204Response, &out.Use204Response
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Parse != nil {
+		in, out := &in.Parse, &out.Parse
+		*out = new(filter.ParseSection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(common.Transport)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPInputConfig.
+func (in *HTTPInputConfig) DeepCopy() *HTTPInputConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPInputConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogInputConfig) DeepCopyInto(out *SyslogInputConfig) {
+	*out = *in
+	if in.EmitUnmatchedLines != nil {
+		in, out := &in.EmitUnmatchedLines, &out.EmitUnmatchedLines
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Parse != nil {
+		in, out := &in.Parse, &out.Parse
+		*out = new(filter.ParseSection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(common.Transport)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogInputConfig.
+func (in *SyslogInputConfig) DeepCopy() *SyslogInputConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogInputConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPInputConfig) DeepCopyInto(out *TCPInputConfig) {
+	*out = *in
+	if in.Parse != nil {
+		in, out := &in.Parse, &out.Parse
+		*out = new(filter.ParseSection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(common.Transport)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPInputConfig.
+func (in *TCPInputConfig) DeepCopy() *TCPInputConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPInputConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TailInputConfig) DeepCopyInto(out *TailInputConfig) {
 	*out = *in
@@ -82,3 +183,28 @@ func (in *TailInputConfig) DeepCopy() *TailInputConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UDPInputConfig) DeepCopyInto(out *UDPInputConfig) {
+	*out = *in
+	if in.RemoveNewline != nil {
+		in, out := &in.RemoveNewline, &out.RemoveNewline
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Parse != nil {
+		in, out := &in.Parse, &out.Parse
+		*out = new(filter.ParseSection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UDPInputConfig.
+func (in *UDPInputConfig) DeepCopy() *UDPInputConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UDPInputConfig)
+	in.DeepCopyInto(out)
+	return out
+}
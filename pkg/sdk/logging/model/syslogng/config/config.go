@@ -48,8 +48,29 @@ type Input struct {
 	Outputs             []v1beta1.SyslogNGOutput
 	ClusterFlows        []v1beta1.SyslogNGClusterFlow
 	Flows               []v1beta1.SyslogNGFlow
+	OutputGrants        []v1beta1.OutputGrant
 	SecretLoaderFactory SecretLoaderFactory
 	SourcePort          int
+	// Directory of the TLS certificate and key of the source, used when TLS is enabled
+	TLSDir string
+	// RecordFlowError is called with the validation result of every flow if set, nil if the flow is rendered
+	RecordFlowError func(flow client.Object, err error)
+	// AuditSecrets wraps the secret loader factory of the flows and outputs of tenants if set and strict tenancy is enabled
+	AuditSecrets func(resource client.Object, secrets SecretLoaderFactory) SecretLoaderFactory
+}
+
+func (in Input) recordFlowError(flow client.Object, err error) {
+	if in.RecordFlowError != nil {
+		in.RecordFlowError(flow, err)
+	}
+}
+
+// tenantSecretLoaderFactory returns the secret loader factory to render a namespaced resource with
+func (in Input) tenantSecretLoaderFactory(resource client.Object) SecretLoaderFactory {
+	if in.AuditSecrets == nil || !in.Logging.Spec.StrictTenancy || resource.GetNamespace() == in.Logging.Spec.ControlNamespace {
+		return in.SecretLoaderFactory
+	}
+	return in.AuditSecrets(resource, in.SecretLoaderFactory)
 }
 
 type SecretLoaderFactory interface {
@@ -80,7 +101,7 @@ func configRenderer(in Input) (render.Renderer, error) {
 		}
 	}
 
-	globalOptions := renderAny(in.Logging.Spec.SyslogNGSpec.GlobalOptions, in.SecretLoaderFactory.SecretLoaderForNamespace(in.Logging.Namespace))
+	globalOptions := renderAny(in.Logging.Spec.SyslogNGSpec.GlobalOptions, in.SecretLoaderFactory.SecretLoaderForNamespace(in.Logging.Spec.ControlNamespace))
 
 	destinationDefs := make([]render.Renderer, 0, len(in.ClusterOutputs)+len(in.Outputs))
 	clusterOutputRefs := make(map[string]types.NamespacedName, len(in.ClusterOutputs))
@@ -91,28 +112,117 @@ func configRenderer(in Input) (render.Renderer, error) {
 		}
 		destinationDefs = append(destinationDefs, renderClusterOutput(co, in.SecretLoaderFactory))
 	}
+	outputRefs := make(map[types.NamespacedName]struct{}, len(in.Outputs))
 	for _, o := range in.Outputs {
-		destinationDefs = append(destinationDefs, renderOutput(o, in.SecretLoaderFactory))
+		o := o
+		outputRefs[client.ObjectKeyFromObject(&o)] = struct{}{}
+		destinationDefs = append(destinationDefs, renderOutput(o, in.tenantSecretLoaderFactory(&o)))
 	}
 
+	syslogNGSpec := in.Logging.Spec.SyslogNGSpec
+	if ref := syslogNGSpec.ParseErrorOutputRef; ref != "" {
+		if _, ok := clusterOutputRefs[ref]; !ok {
+			errs = errors.Append(errs, errors.Errorf("parse error output reference %s cannot be found", ref))
+		}
+	}
+	if syslogNGSpec.DefaultFlow != nil {
+		if err := validateClusterOutputs(clusterOutputRefs, "default", syslogNGSpec.DefaultFlow.GlobalOutputRefs); err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+
+	sourceNames := []string{sourceName}
+	sourceDefs := make([]render.Renderer, 0, len(syslogNGSpec.Sources))
+	if err := validateSources(syslogNGSpec.Sources, in.SourcePort, syslogNGSpec.TLS.Enabled); err != nil {
+		errs = errors.Append(errs, err)
+	}
+	for _, src := range syslogNGSpec.Sources {
+		namespace := src.Namespace
+		if namespace == "" {
+			namespace = in.Logging.Spec.ControlNamespace
+		}
+		sourceNames = append(sourceNames, extraSourceName(src.Name))
+		sourceDefs = append(sourceDefs, renderExtraSource(src, namespace, keyDelim(syslogNGSpec.JSONKeyDelimiter), sourceTLS(syslogNGSpec.TLS, in.TLSDir)))
+	}
+
+	strictTenancy := in.Logging.Spec.StrictTenancy
 	logDefs := make([]render.Renderer, 0, len(in.ClusterFlows)+len(in.Flows))
 	for _, cf := range in.ClusterFlows {
-		if err := validateClusterOutputs(clusterOutputRefs, client.ObjectKeyFromObject(&cf).String(), cf.Spec.GlobalOutputRefs); err != nil {
+		cf := cf
+		err := validateClusterOutputs(clusterOutputRefs, client.ObjectKeyFromObject(&cf).String(), cf.Spec.GlobalOutputRefs)
+		in.recordFlowError(&cf, err)
+		if err != nil {
+			if in.Logging.Spec.SkipInvalidResources {
+				continue
+			}
 			errs = errors.Append(errs, err)
 		}
-		logDefs = append(logDefs, renderClusterFlow(clusterOutputRefs, sourceName, cf, in.SecretLoaderFactory))
+		if strictTenancy && cf.Namespace != in.Logging.Spec.ControlNamespace {
+			cf.Spec.Match = constrainMatchToNamespace(cf.Spec.Match, cf.Namespace, keyDelim(in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter))
+		}
+		logDefs = append(logDefs, renderClusterFlow(clusterOutputRefs, sourceNames, cf, in.SecretLoaderFactory))
 	}
 	for _, f := range in.Flows {
-		if err := validateClusterOutputs(clusterOutputRefs, client.ObjectKeyFromObject(&f).String(), f.Spec.GlobalOutputRefs); err != nil {
+		f := f
+		// the destinations of the log path have to be defined, otherwise syslog-ng refuses to load the config
+		err := errors.Combine(
+			validateClusterOutputs(clusterOutputRefs, client.ObjectKeyFromObject(&f).String(), f.Spec.GlobalOutputRefs),
+			validateSharedOutputs(outputRefs, in.OutputGrants, client.ObjectKeyFromObject(&f), f.Spec.SharedOutputRefs),
+			validateLocalOutputs(outputRefs, client.ObjectKeyFromObject(&f), f.Spec.LocalOutputRefs),
+		)
+		in.recordFlowError(&f, err)
+		if err != nil {
+			// the error is reported on the status of the flow
+			if in.Logging.Spec.SkipInvalidResources {
+				continue
+			}
 			errs = errors.Append(errs, err)
 		}
-		logDefs = append(logDefs, renderFlow(clusterOutputRefs, sourceName, keyDelim(in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter), f, in.SecretLoaderFactory))
+		logDefs = append(logDefs, renderFlow(clusterOutputRefs, sourceNames, keyDelim(in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter), f, in.tenantSecretLoaderFactory(&f)))
 	}
 
 	if in.Logging.Spec.SyslogNGSpec.JSONKeyPrefix == "" {
 		in.Logging.Spec.SyslogNGSpec.JSONKeyPrefix = "json" + keyDelim(in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter)
 	}
 
+	loggingSecretLoader := in.SecretLoaderFactory.SecretLoaderForNamespace(in.Logging.Spec.ControlNamespace)
+	globalFilterDefs, globalFilterRefs := renderGlobalFilters(syslogNGSpec.GlobalFilters, &in.Logging, loggingSecretLoader)
+
+	sourceDriver := NetworkSourceDriver{
+		Transport:      "tcp",
+		Port:           uint16(in.SourcePort),
+		MaxConnections: in.Logging.Spec.SyslogNGSpec.MaxConnections,
+		LogIWSize:      logIWSizeCalculator(in),
+		Flags:          []string{"no-parse"},
+	}
+	if syslogNGSpec.TLS.Enabled {
+		if in.TLSDir == "" {
+			errs = errors.Append(errs, errors.New("TLS is enabled but no TLS directory is provided"))
+		}
+		sourceDriver.Transport = "tls"
+		sourceDriver.TLS = sourceTLS(syslogNGSpec.TLS, in.TLSDir)
+	}
+
+	var sourceTransforms []render.Renderer
+	jsonParser := parserDefStmt("", renderDriver(Field{
+		Value: reflect.ValueOf(JSONParser{
+			Prefix:       in.Logging.Spec.SyslogNGSpec.JSONKeyPrefix,
+			KeyDelimiter: in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter,
+		}),
+	}, nil))
+	var parseErrorFlow, defaultFlow render.Renderer
+	if syslogNGSpec.ParseErrorOutputRef != "" {
+		// records failing to parse are tagged instead of being dropped, so that the error flow can pick them up
+		sourceTransforms = append(sourceTransforms, ifElseStmt(jsonParser, rewriteDefStmt("", parenDefStmt("set-tag", render.Quoted(parseErrorTag)))))
+		parseErrorFlow = renderParseErrorFlow(clusterOutputRefs, sourceName, syslogNGSpec.ParseErrorOutputRef)
+	} else {
+		sourceTransforms = append(sourceTransforms, jsonParser)
+	}
+	sourceTransforms = append(sourceTransforms, globalFilterRefs...)
+	if syslogNGSpec.DefaultFlow != nil {
+		defaultFlow = renderDefaultFlow(clusterOutputRefs, sourceNames, *syslogNGSpec.DefaultFlow, &in.Logging, loggingSecretLoader)
+	}
+
 	return render.AllFrom(seqs.Intersperse(
 		seqs.Filter(
 			seqs.Concat(
@@ -120,29 +230,20 @@ func configRenderer(in Input) (render.Renderer, error) {
 					versionStmt(configVersion),
 					includeStmt("scl.conf"),
 					globalOptionsDefStmt(globalOptions...),
+					render.If(len(syslogNGSpec.GlobalFilters) > 0, globalFilterDefs),
 					sourceDefStmt(sourceName,
 						channelDefStmt(
 							sourceDefStmt("", renderDriver(Field{
-								Value: reflect.ValueOf(NetworkSourceDriver{
-									Transport:      "tcp",
-									Port:           uint16(in.SourcePort),
-									MaxConnections: in.Logging.Spec.SyslogNGSpec.MaxConnections,
-									LogIWSize:      logIWSizeCalculator(in),
-									Flags:          []string{"no-parse"},
-								}),
+								Value: reflect.ValueOf(sourceDriver),
 							}, nil)),
-							[]render.Renderer{
-								parserDefStmt("", renderDriver(Field{
-									Value: reflect.ValueOf(JSONParser{
-										Prefix:       in.Logging.Spec.SyslogNGSpec.JSONKeyPrefix,
-										KeyDelimiter: in.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter,
-									}),
-								}, nil)),
-							},
+							sourceTransforms,
 						)),
 				),
+				seqs.FromSlice(sourceDefs),
 				seqs.FromSlice(destinationDefs),
+				seqs.FromValues(parseErrorFlow),
 				seqs.FromSlice(logDefs),
+				seqs.FromValues(defaultFlow),
 			),
 			func(rnd render.Renderer) bool { return rnd != nil },
 		),
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/filter"
@@ -526,6 +528,36 @@ log {
 			},
 			wantErr: true,
 		},
+		"strict tenancy flow referencing non-existent output": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+						StrictTenancy:    true,
+						ControlNamespace: "logging",
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							LocalOutputRefs: []string{
+								"out",
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
 		"parser": {
 			input: Input{
 				Logging: v1beta1.Logging{
@@ -782,6 +814,229 @@ log {
 };
 `),
 		},
+		"strict tenancy constrains clusterflow outside of the control namespace": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+						ControlNamespace: "logging",
+						StrictTenancy:    true,
+					},
+				},
+				ClusterFlows: []v1beta1.SyslogNGClusterFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGClusterFlowSpec{
+							Match: &v1beta1.SyslogNGMatch{
+								Regexp: &filter.RegexpMatchExpr{
+									Pattern: "nginx",
+									Value:   "json.kubernetes.labels.app",
+								},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantOut: Untab(`@version: current
+
+@include "scl.conf"
+
+source "main_input" {
+    channel {
+        source {
+            network(flags("no-parse") port(601) transport("tcp"));
+        };
+        parser {
+            json-parser(prefix("json."));
+        };
+    };
+};
+
+filter "clusterflow_default_test-flow_match" {
+    (match("default" value("json.kubernetes.namespace_name") type("string")) and match("nginx" value("json.kubernetes.labels.app")));
+};
+log {
+    source("main_input");
+    filter("clusterflow_default_test-flow_match");
+};
+`),
+		},
+		"strict tenancy rejects local output reference from another namespace": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+						ControlNamespace: "logging",
+						StrictTenancy:    true,
+					},
+				},
+				Outputs: []v1beta1.SyslogNGOutput{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "other",
+							Name:      "test-syslog-out",
+						},
+						Spec: v1beta1.SyslogNGOutputSpec{
+							Syslog: &output.SyslogOutput{
+								Host: "test.local",
+							},
+						},
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							LocalOutputRefs: []string{"test-syslog-out"},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"shared output granted to flow": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+						ControlNamespace: "logging",
+					},
+				},
+				Outputs: []v1beta1.SyslogNGOutput{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "shared",
+							Name:      "test-syslog-out",
+						},
+						Spec: v1beta1.SyslogNGOutputSpec{
+							Syslog: &output.SyslogOutput{
+								Host: "test.local",
+							},
+						},
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							SharedOutputRefs: []v1beta1.OutputReference{
+								{Namespace: "shared", Name: "test-syslog-out"},
+							},
+						},
+					},
+				},
+				OutputGrants: []v1beta1.OutputGrant{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "shared",
+							Name:      "test-grant",
+						},
+						Spec: v1beta1.OutputGrantSpec{
+							From: []v1beta1.OutputGrantFrom{
+								{Kind: v1beta1.OutputGrantKindSyslogNGFlow, Namespace: "default"},
+							},
+							To: []v1beta1.OutputGrantTo{
+								{Kind: v1beta1.OutputGrantKindSyslogNGOutput, Name: "test-syslog-out"},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantOut: Untab(`@version: current
+
+@include "scl.conf"
+
+source "main_input" {
+    channel {
+        source {
+            network(flags("no-parse") port(601) transport("tcp"));
+        };
+        parser {
+            json-parser(prefix("json."));
+        };
+    };
+};
+
+destination "output_shared_test-syslog-out" {
+    syslog("test.local" persist_name("output_shared_test-syslog-out"));
+};
+
+filter "flow_default_test-flow_ns_filter" {
+    match("default" value("json.kubernetes.namespace_name") type("string"));
+};
+log {
+    source("main_input");
+    filter("flow_default_test-flow_ns_filter");
+    destination("output_shared_test-syslog-out");
+};
+`),
+		},
+		"shared output without grant": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+						ControlNamespace: "logging",
+					},
+				},
+				Outputs: []v1beta1.SyslogNGOutput{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "shared",
+							Name:      "test-syslog-out",
+						},
+						Spec: v1beta1.SyslogNGOutputSpec{
+							Syslog: &output.SyslogOutput{
+								Host: "test.local",
+							},
+						},
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							SharedOutputRefs: []v1beta1.OutputReference{
+								{Namespace: "shared", Name: "test-syslog-out"},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
 		"custom json key prefix": {
 			input: Input{
 				Logging: v1beta1.Logging{
@@ -814,14 +1069,557 @@ source "main_input" {
 };
 `),
 		},
-	}
-	for name, testCase := range testCases {
-		testCase := testCase
-		t.Run(name, func(t *testing.T) {
-			var buf strings.Builder
-			err := RenderConfigInto(testCase.input, &buf)
-			CheckError(t, testCase.wantErr, err)
-			require.Equal(t, testCase.wantOut, buf.String())
-		})
-	}
+		"global filters, default flow and parse error output": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "logging",
+						Name:      "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							GlobalFilters: []v1beta1.SyslogNGFilter{
+								{
+									Rewrite: []filter.RewriteConfig{
+										{
+											Set: &filter.SetConfig{
+												FieldName: "cluster",
+												Value:     "test-cluster",
+											},
+										},
+									},
+								},
+							},
+							DefaultFlow: &v1beta1.SyslogNGDefaultFlowSpec{
+								GlobalOutputRefs: []string{"catch-all"},
+							},
+							ParseErrorOutputRef: "dead-letter",
+						},
+						ControlNamespace: "logging",
+					},
+				},
+				ClusterOutputs: []v1beta1.SyslogNGClusterOutput{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "catch-all",
+							Namespace: "logging",
+						},
+						Spec: v1beta1.SyslogNGClusterOutputSpec{
+							SyslogNGOutputSpec: v1beta1.SyslogNGOutputSpec{
+								Syslog: &output.SyslogOutput{
+									Host: "127.0.0.1",
+								},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "dead-letter",
+							Namespace: "logging",
+						},
+						Spec: v1beta1.SyslogNGClusterOutputSpec{
+							SyslogNGOutputSpec: v1beta1.SyslogNGOutputSpec{
+								Syslog: &output.SyslogOutput{
+									Host: "127.0.0.2",
+								},
+							},
+						},
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-flow",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							GlobalOutputRefs: []string{"catch-all"},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantOut: Untab(`@version: current
+
+@include "scl.conf"
+
+rewrite "global_filters_0" {
+	set("test-cluster" value("cluster"));
+};
+
+source "main_input" {
+	channel {
+		source {
+			network(flags("no-parse") port(601) transport("tcp"));
+		};
+		if {
+			parser {
+				json-parser(prefix("json."));
+			};
+		} else {
+			rewrite {
+				set-tag("json_parse_error");
+			};
+		};
+		rewrite("global_filters_0");
+	};
+};
+
+destination "clusteroutput_logging_catch-all" {
+	syslog("127.0.0.1" persist_name("clusteroutput_logging_catch-all"));
+};
+
+destination "clusteroutput_logging_dead-letter" {
+	syslog("127.0.0.2" persist_name("clusteroutput_logging_dead-letter"));
+};
+
+log {
+	source("main_input");
+	filter {
+		tags("json_parse_error");
+	};
+	destination("clusteroutput_logging_dead-letter");
+	flags("final");
+};
+
+filter "flow_default_test-flow_ns_filter" {
+	match("default" value("json.kubernetes.namespace_name") type("string"));
+};
+log {
+	source("main_input");
+	filter("flow_default_test-flow_ns_filter");
+	destination("clusteroutput_logging_catch-all");
+};
+
+log {
+	source("main_input");
+	destination("clusteroutput_logging_catch-all");
+	flags("fallback");
+};
+`),
+		},
+		"default flow referencing non-existent cluster output": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							DefaultFlow: &v1beta1.SyslogNGDefaultFlowSpec{
+								GlobalOutputRefs: []string{"clusterout"},
+							},
+						},
+						ControlNamespace: "logging",
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"parse error output referencing non-existent cluster output": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							ParseErrorOutputRef: "clusterout",
+						},
+						ControlNamespace: "logging",
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"tls source": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "logging",
+						Name:      "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							TLS: v1beta1.SyslogNGTLS{
+								Enabled:    true,
+								SecretName: "syslog-ng-tls",
+								PeerVerify: "optional-untrusted",
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+				TLSDir:              "/syslog-ng/tls",
+			},
+			wantOut: Untab(`@version: current
+
+@include "scl.conf"
+
+source "main_input" {
+	channel {
+		source {
+			network(flags("no-parse") port(601) transport("tls") tls(key-file("/syslog-ng/tls/tls.key") cert-file("/syslog-ng/tls/tls.crt") ca-file("/syslog-ng/tls/ca.crt") peer-verify("optional-untrusted")));
+		};
+		parser {
+			json-parser(prefix("json."));
+		};
+	};
+};
+`),
+		},
+		"tls source without tls dir": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "logging",
+						Name:      "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							TLS: v1beta1.SyslogNGTLS{
+								Enabled: true,
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"extra sources": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "logging",
+						Name:      "test",
+					},
+					Spec: v1beta1.LoggingSpec{
+						ControlNamespace: "logging",
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							Sources: []v1beta1.SyslogNGSource{
+								{
+									Name:      "appliances",
+									Port:      514,
+									Namespace: "network",
+									Syslog: &v1beta1.SyslogNGSyslogSource{
+										Transport: "udp",
+									},
+								},
+								{
+									Name:          "otlp",
+									Port:          4317,
+									OpenTelemetry: &v1beta1.SyslogNGOpenTelemetrySource{},
+								},
+							},
+						},
+					},
+				},
+				Outputs: []v1beta1.SyslogNGOutput{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "network",
+							Name:      "test-syslog-out",
+						},
+						Spec: v1beta1.SyslogNGOutputSpec{
+							Syslog: &output.SyslogOutput{
+								Host:      "test.local",
+								Transport: "tcp",
+							},
+						},
+					},
+				},
+				Flows: []v1beta1.SyslogNGFlow{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "network",
+							Name:      "appliances",
+						},
+						Spec: v1beta1.SyslogNGFlowSpec{
+							Match: &v1beta1.SyslogNGMatch{
+								Regexp: &filter.RegexpMatchExpr{
+									Pattern:  "source_appliances",
+									Template: "${SOURCE}",
+									Type:     "string",
+								},
+							},
+							LocalOutputRefs: []string{"test-syslog-out"},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantOut: Untab(`@version: current
+
+@include "scl.conf"
+
+source "main_input" {
+	channel {
+		source {
+			network(flags("no-parse") port(601) transport("tcp"));
+		};
+		parser {
+			json-parser(prefix("json."));
+		};
+	};
+};
+
+source "source_appliances" {
+	channel {
+		source {
+			syslog(port(514) transport("udp"));
+		};
+		rewrite {
+			set("network" value("json.kubernetes.namespace_name"));
+		};
+	};
+};
+
+source "source_otlp" {
+	channel {
+		source {
+			opentelemetry(port(4317));
+		};
+		rewrite {
+			set("logging" value("json.kubernetes.namespace_name"));
+		};
+	};
+};
+
+destination "output_network_test-syslog-out" {
+	syslog("test.local" transport("tcp") persist_name("output_network_test-syslog-out"));
+};
+
+filter "flow_network_appliances_ns_filter" {
+	match("network" value("json.kubernetes.namespace_name") type("string"));
+};
+filter "flow_network_appliances_match" {
+	match("source_appliances" template("${SOURCE}") type("string"));
+};
+log {
+	source("main_input");
+	source("source_appliances");
+	source("source_otlp");
+	filter("flow_network_appliances_ns_filter");
+	filter("flow_network_appliances_match");
+	destination("output_network_test-syslog-out");
+};
+`),
+		},
+		"extra source with conflicting port": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							Sources: []v1beta1.SyslogNGSource{
+								{
+									Name:    "appliances",
+									Port:    601,
+									Network: &v1beta1.SyslogNGNetworkSource{},
+								},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"extra source with multiple source types": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							Sources: []v1beta1.SyslogNGSource{
+								{
+									Name:          "appliances",
+									Port:          514,
+									Syslog:        &v1beta1.SyslogNGSyslogSource{},
+									OpenTelemetry: &v1beta1.SyslogNGOpenTelemetrySource{},
+								},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+		"extra tls source without tls": {
+			input: Input{
+				Logging: v1beta1.Logging{
+					Spec: v1beta1.LoggingSpec{
+						SyslogNGSpec: &v1beta1.SyslogNGSpec{
+							Sources: []v1beta1.SyslogNGSource{
+								{
+									Name:   "appliances",
+									Port:   6514,
+									Syslog: &v1beta1.SyslogNGSyslogSource{Transport: "tls"},
+								},
+							},
+						},
+					},
+				},
+				SecretLoaderFactory: &TestSecretLoaderFactory{},
+				SourcePort:          601,
+			},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			var buf strings.Builder
+			err := RenderConfigInto(testCase.input, &buf)
+			CheckError(t, testCase.wantErr, err)
+			require.Equal(t, testCase.wantOut, buf.String())
+		})
+	}
+}
+
+type namespaceRecordingSecretLoaderFactory struct {
+	TestSecretLoaderFactory
+	namespaces []string
+}
+
+func (f *namespaceRecordingSecretLoaderFactory) SecretLoaderForNamespace(ns string) secret.SecretLoader {
+	f.namespaces = append(f.namespaces, ns)
+	return f.TestSecretLoaderFactory.SecretLoaderForNamespace(ns)
+}
+
+func TestLoggingSecretsLoadedFromControlNamespace(t *testing.T) {
+	secrets := &namespaceRecordingSecretLoaderFactory{}
+	in := Input{
+		Logging: v1beta1.Logging{
+			// loggings are cluster scoped, the secrets of the global filters and the default flow live in the control namespace
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: v1beta1.LoggingSpec{
+				SyslogNGSpec: &v1beta1.SyslogNGSpec{
+					GlobalFilters: []v1beta1.SyslogNGFilter{
+						{
+							Rewrite: []filter.RewriteConfig{
+								{Set: &filter.SetConfig{FieldName: "cluster", Value: "test-cluster"}},
+							},
+						},
+					},
+				},
+				ControlNamespace: "logging",
+			},
+		},
+		SecretLoaderFactory: secrets,
+		SourcePort:          601,
+	}
+	require.NoError(t, RenderConfigInto(in, &strings.Builder{}))
+	require.Contains(t, secrets.namespaces, "logging")
+	require.NotContains(t, secrets.namespaces, "")
+}
+
+func TestRenderConfigRecordsFlowErrors(t *testing.T) {
+	recorded := map[string]error{}
+	in := Input{
+		Logging: v1beta1.Logging{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: v1beta1.LoggingSpec{
+				SyslogNGSpec:         &v1beta1.SyslogNGSpec{},
+				ControlNamespace:     "logging",
+				SkipInvalidResources: true,
+			},
+		},
+		ClusterOutputs: []v1beta1.SyslogNGClusterOutput{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "clusterout"},
+				Spec: v1beta1.SyslogNGClusterOutputSpec{
+					SyslogNGOutputSpec: v1beta1.SyslogNGOutputSpec{Syslog: &output.SyslogOutput{Host: "127.0.0.1"}},
+				},
+			},
+		},
+		Flows: []v1beta1.SyslogNGFlow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "valid"},
+				Spec:       v1beta1.SyslogNGFlowSpec{GlobalOutputRefs: []string{"clusterout"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "invalid"},
+				Spec:       v1beta1.SyslogNGFlowSpec{GlobalOutputRefs: []string{"missing"}},
+			},
+			{
+				// local outputs are checked without strict tenancy as well
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing-local"},
+				Spec:       v1beta1.SyslogNGFlowSpec{LocalOutputRefs: []string{"missing"}},
+			},
+		},
+		SecretLoaderFactory: &TestSecretLoaderFactory{},
+		SourcePort:          601,
+		RecordFlowError: func(flow client.Object, err error) {
+			recorded[flow.GetNamespace()+"/"+flow.GetName()] = err
+		},
+	}
+
+	var out strings.Builder
+	require.NoError(t, RenderConfigInto(in, &out))
+	require.Len(t, recorded, 3)
+	require.NoError(t, recorded["default/valid"])
+	require.Error(t, recorded["default/invalid"])
+	require.EqualError(t, recorded["default/missing-local"], "output reference missing for flow default/missing-local cannot be found in namespace default")
+	require.Contains(t, out.String(), "flow_default_valid")
+	require.NotContains(t, out.String(), "flow_default_invalid")
+	require.NotContains(t, out.String(), "flow_default_missing-local")
+
+	in.Logging.Spec.SkipInvalidResources = false
+	require.Error(t, RenderConfigInto(in, &strings.Builder{}))
+}
+
+func TestRenderConfigAuditsTenantSecrets(t *testing.T) {
+	var audited []string
+	in := Input{
+		Logging: v1beta1.Logging{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: v1beta1.LoggingSpec{
+				SyslogNGSpec:     &v1beta1.SyslogNGSpec{},
+				ControlNamespace: "logging",
+				StrictTenancy:    true,
+			},
+		},
+		Outputs: []v1beta1.SyslogNGOutput{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "out"},
+				Spec:       v1beta1.SyslogNGOutputSpec{Syslog: &output.SyslogOutput{Host: "127.0.0.1"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "out"},
+				Spec:       v1beta1.SyslogNGOutputSpec{Syslog: &output.SyslogOutput{Host: "127.0.0.1"}},
+			},
+		},
+		Flows: []v1beta1.SyslogNGFlow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "flow"},
+				Spec:       v1beta1.SyslogNGFlowSpec{LocalOutputRefs: []string{"out"}},
+			},
+		},
+		SecretLoaderFactory: &TestSecretLoaderFactory{},
+		SourcePort:          601,
+		AuditSecrets: func(resource client.Object, secrets SecretLoaderFactory) SecretLoaderFactory {
+			audited = append(audited, reflect.TypeOf(resource).Elem().Name()+"/"+resource.GetNamespace()+"/"+resource.GetName())
+			return secrets
+		},
+	}
+
+	require.NoError(t, RenderConfigInto(in, &strings.Builder{}))
+	// the resources of the control namespace are not audited
+	require.Equal(t, []string{"SyslogNGOutput/default/out", "SyslogNGFlow/default/flow"}, audited)
+
+	audited = nil
+	in.Logging.Spec.StrictTenancy = false
+	require.NoError(t, RenderConfigInto(in, &strings.Builder{}))
+	require.Empty(t, audited)
 }
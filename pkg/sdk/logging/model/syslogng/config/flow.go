@@ -41,7 +41,45 @@ func validateClusterOutputs(clusterOutputRefs map[string]types.NamespacedName, f
 	})
 }
 
-func renderClusterFlow(clusterOutputRefs map[string]types.NamespacedName, sourceName string, f v1beta1.SyslogNGClusterFlow, secretLoaderFactory SecretLoaderFactory) render.Renderer {
+func validateLocalOutputs(outputRefs map[types.NamespacedName]struct{}, flow types.NamespacedName, localOutputRefs []string) error {
+	return seqs.Reduce(seqs.FromSlice(localOutputRefs), nil, func(err error, ref string) error {
+		if _, ok := outputRefs[types.NamespacedName{Namespace: flow.Namespace, Name: ref}]; !ok {
+			return errors.Append(err, errors.Errorf("output reference %s for flow %s cannot be found in namespace %s", ref, flow, flow.Namespace))
+		}
+		return err
+	})
+}
+
+func validateSharedOutputs(outputRefs map[types.NamespacedName]struct{}, grants []v1beta1.OutputGrant, flow types.NamespacedName, sharedOutputRefs []v1beta1.OutputReference) error {
+	return seqs.Reduce(seqs.FromSlice(sharedOutputRefs), nil, func(err error, ref v1beta1.OutputReference) error {
+		if !v1beta1.OutputGrantAllows(grants, v1beta1.OutputGrantKindSyslogNGFlow, flow.Namespace, v1beta1.OutputGrantKindSyslogNGOutput, ref) {
+			return errors.Append(err, errors.Errorf("shared output reference %s/%s for flow %s is not granted", ref.Namespace, ref.Name, flow))
+		}
+		if _, ok := outputRefs[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}]; !ok {
+			return errors.Append(err, errors.Errorf("shared output reference %s/%s for flow %s cannot be found", ref.Namespace, ref.Name, flow))
+		}
+		return err
+	})
+}
+
+// constrainMatchToNamespace restricts a cluster flow match to the logs of the given namespace
+func constrainMatchToNamespace(m *v1beta1.SyslogNGMatch, namespace string, keyDelim string) *v1beta1.SyslogNGMatch {
+	nsMatch := filter.MatchExpr{
+		Regexp: &filter.RegexpMatchExpr{
+			Pattern: namespace,
+			Value:   strings.Join([]string{"json", "kubernetes", "namespace_name"}, keyDelim),
+			Type:    "string",
+		},
+	}
+	if m.IsEmpty() {
+		return (*v1beta1.SyslogNGMatch)(&nsMatch)
+	}
+	return &v1beta1.SyslogNGMatch{
+		And: []filter.MatchExpr{nsMatch, filter.MatchExpr(*m)},
+	}
+}
+
+func renderClusterFlow(clusterOutputRefs map[string]types.NamespacedName, sourceNames []string, f v1beta1.SyslogNGClusterFlow, secretLoaderFactory SecretLoaderFactory) render.Renderer {
 	baseName := fmt.Sprintf("clusterflow_%s_%s", f.Namespace, f.Name)
 	matchName := fmt.Sprintf("%s_match", baseName)
 	filterDefs := seqs.MapWithIndex(seqs.FromSlice(f.Spec.Filters), func(idx int, flt v1beta1.SyslogNGFilter) render.Renderer {
@@ -51,7 +89,7 @@ func renderClusterFlow(clusterOutputRefs map[string]types.NamespacedName, source
 		renderFlowMatch(matchName, f.Spec.Match),
 		render.AllFrom(filterDefs),
 		logDefStmt(
-			[]string{sourceName},
+			sourceNames,
 			seqs.ToSlice(seqs.Concat(
 				seqs.FromValues(
 					render.If(!f.Spec.Match.IsEmpty(), filterRefStmt(matchName)),
@@ -67,7 +105,7 @@ func renderClusterFlow(clusterOutputRefs map[string]types.NamespacedName, source
 	)
 }
 
-func renderFlow(clusterOutputRefs map[string]types.NamespacedName, sourceName string, keyDelim string, f v1beta1.SyslogNGFlow, secretLoaderFactory SecretLoaderFactory) render.Renderer {
+func renderFlow(clusterOutputRefs map[string]types.NamespacedName, sourceNames []string, keyDelim string, f v1beta1.SyslogNGFlow, secretLoaderFactory SecretLoaderFactory) render.Renderer {
 	baseName := fmt.Sprintf("flow_%s_%s", f.Namespace, f.Name)
 	matchName := fmt.Sprintf("%s_match", baseName)
 	nsFilterName := fmt.Sprintf("%s_ns_filter", baseName)
@@ -83,7 +121,7 @@ func renderFlow(clusterOutputRefs map[string]types.NamespacedName, sourceName st
 		renderFlowMatch(matchName, f.Spec.Match),
 		filterDefs,
 		logDefStmt(
-			[]string{sourceName},
+			sourceNames,
 			seqs.ToSlice(seqs.Concat(
 				seqs.FromValues(
 					filterRefStmt(nsFilterName),
@@ -98,11 +136,71 @@ func renderFlow(clusterOutputRefs map[string]types.NamespacedName, sourceName st
 					return clusterOutputDestName(clusterOutputRefs[ref].Namespace, ref)
 				}),
 				seqs.Map(seqs.FromSlice(f.Spec.LocalOutputRefs), func(ref string) string { return outputDestName(f.Namespace, ref) }),
+				seqs.Map(seqs.FromSlice(f.Spec.SharedOutputRefs), func(ref v1beta1.OutputReference) string { return outputDestName(ref.Namespace, ref.Name) }),
 			)),
 		),
 	)
 }
 
+// renderGlobalFilters returns the definitions of the global filters and the statements referencing them
+func renderGlobalFilters(filters []v1beta1.SyslogNGFilter, logging *v1beta1.Logging, secretLoader secret.SecretLoader) (render.Renderer, []render.Renderer) {
+	const baseName = "global"
+	defs := render.AllFrom(seqs.MapWithIndex(seqs.FromSlice(filters), func(idx int, flt v1beta1.SyslogNGFilter) render.Renderer {
+		return renderFlowFilter(flt, logging, idx, baseName, secretLoader)
+	}))
+	refs := seqs.ToSlice(seqs.MapWithIndex(seqs.FromSlice(filters), func(idx int, flt v1beta1.SyslogNGFilter) render.Renderer {
+		return parenDefStmt(filterKind(flt), render.Literal(filterID(flt, idx, baseName)))
+	}))
+	return defs, refs
+}
+
+// renderDefaultFlow renders a log path with the fallback flag, which processes the records not matched by any other log path
+func renderDefaultFlow(clusterOutputRefs map[string]types.NamespacedName, sourceNames []string, f v1beta1.SyslogNGDefaultFlowSpec, logging *v1beta1.Logging, secretLoader secret.SecretLoader) render.Renderer {
+	const baseName = "default_flow"
+	return render.AllOf(
+		render.AllFrom(seqs.MapWithIndex(seqs.FromSlice(f.Filters), func(idx int, flt v1beta1.SyslogNGFilter) render.Renderer {
+			return renderFlowFilter(flt, logging, idx, baseName, secretLoader)
+		})),
+		logDefStmt(
+			sourceNames,
+			seqs.ToSlice(seqs.MapWithIndex(seqs.FromSlice(f.Filters), func(idx int, flt v1beta1.SyslogNGFilter) render.Renderer {
+				return parenDefStmt(filterKind(flt), render.Literal(filterID(flt, idx, baseName)))
+			})),
+			seqs.ToSlice(seqs.Map(seqs.FromSlice(f.GlobalOutputRefs), func(ref string) string {
+				return clusterOutputDestName(clusterOutputRefs[ref].Namespace, ref)
+			})),
+			"fallback",
+		),
+	)
+}
+
+// renderParseErrorFlow renders a log path sending the records tagged as unparsable to the parse error output,
+// it has to precede every other log path as its final flag stops further processing of these records
+func renderParseErrorFlow(clusterOutputRefs map[string]types.NamespacedName, sourceName string, parseErrorOutputRef string) render.Renderer {
+	return logDefStmt(
+		[]string{sourceName},
+		[]render.Renderer{
+			filterDefStmt("", render.Line(render.AllOf(optionExpr("tags", render.Quoted(parseErrorTag)), render.String(";")))),
+		},
+		[]string{clusterOutputDestName(clusterOutputRefs[parseErrorOutputRef].Namespace, parseErrorOutputRef)},
+		"final",
+	)
+}
+
+// parseErrorTag is set on the records that cannot be parsed by the main source when a parse error output is configured
+const parseErrorTag = "json_parse_error"
+
+// ifElseStmt renders an if-else junction, the else branch processes the records rejected by the if branch
+func ifElseStmt(ifBody render.Renderer, elseBody render.Renderer) render.Renderer {
+	return render.AllOf(
+		render.Line(render.String("if {")),
+		render.Indented(ifBody),
+		render.Line(render.String("} else {")),
+		render.Indented(elseBody),
+		render.Line(render.String("};")),
+	)
+}
+
 func renderFlowMatch(name string, m *v1beta1.SyslogNGMatch) render.Renderer {
 	if m.IsEmpty() {
 		return nil
@@ -19,11 +19,12 @@ import (
 	"github.com/siliconbrain/go-seqs/seqs"
 )
 
-func logDefStmt(sourceRefs []string, transforms []render.Renderer, destRefs []string) render.Renderer {
+func logDefStmt(sourceRefs []string, transforms []render.Renderer, destRefs []string, flags ...string) render.Renderer {
 	return braceDefStmt("log", "", render.AllOf(
 		render.AllFrom(seqs.Map(seqs.FromSlice(sourceRefs), sourceRefStmt)),
 		render.AllOf(transforms...),
 		render.AllFrom(seqs.Map(seqs.FromSlice(destRefs), destinationRefStmt)),
+		render.If(len(flags) > 0, parenDefStmt("flags", seqs.ToSlice(seqs.Map(seqs.FromSlice(flags), render.Literal[string]))...)),
 	))
 }
 
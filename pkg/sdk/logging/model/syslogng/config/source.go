@@ -15,6 +15,14 @@
 package config
 
 import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/config/render"
 )
 
@@ -26,8 +34,131 @@ type NetworkSourceDriver struct {
 	Transport      string   `syslog-ng:"name=transport,optional"`
 	MaxConnections int      `syslog-ng:"name=max-connections,optional"`
 	LogIWSize      int      `syslog-ng:"name=log-iw-size,optional"`
+	TLS            *TLS     `syslog-ng:"name=tls,optional"`
+}
+
+type SyslogSourceDriver struct {
+	__meta    struct{} `syslog-ng:"name=syslog"` //lint:ignore U1000 field used for adding tag to the type
+	Flags     []string `syslog-ng:"name=flags,optional"`
+	Port      uint16   `syslog-ng:"name=port,optional"`
+	Transport string   `syslog-ng:"name=transport,optional"`
+	TLS       *TLS     `syslog-ng:"name=tls,optional"`
+}
+
+type OpenTelemetrySourceDriver struct {
+	__meta struct{} `syslog-ng:"name=opentelemetry"` //lint:ignore U1000 field used for adding tag to the type
+	Port   uint16   `syslog-ng:"name=port,optional"`
+}
+
+type TLS struct {
+	KeyFile    string `syslog-ng:"name=key-file,optional"`
+	CertFile   string `syslog-ng:"name=cert-file,optional"`
+	CaFile     string `syslog-ng:"name=ca-file,optional"`
+	PeerVerify string `syslog-ng:"name=peer-verify,optional"`
 }
 
 func sourceDefStmt(name string, body render.Renderer) render.Renderer {
 	return braceDefStmt("source", name, body)
 }
+
+const defaultPeerVerify = "required-trusted"
+
+func sourceTLS(tls v1beta1.SyslogNGTLS, dir string) *TLS {
+	res := &TLS{
+		KeyFile:    path.Join(dir, "tls.key"),
+		CertFile:   path.Join(dir, "tls.crt"),
+		CaFile:     path.Join(dir, "ca.crt"),
+		PeerVerify: tls.PeerVerify,
+	}
+	setDefault(&res.PeerVerify, defaultPeerVerify)
+	return res
+}
+
+func extraSourceName(name string) string {
+	return "source_" + name
+}
+
+func validateSources(sources []v1beta1.SyslogNGSource, mainPort int, tlsEnabled bool) error {
+	var errs error
+	names := make(map[string]struct{}, len(sources))
+	ports := map[string]string{fmt.Sprintf("tcp/%d", mainPort): sourceName}
+	for _, src := range sources {
+		if _, ok := names[src.Name]; ok {
+			errs = errors.Append(errs, errors.Errorf("duplicate source name %s", src.Name))
+		}
+		names[src.Name] = struct{}{}
+
+		var kinds []string
+		if src.Syslog != nil {
+			kinds = append(kinds, "syslog")
+		}
+		if src.Network != nil {
+			kinds = append(kinds, "network")
+		}
+		if src.OpenTelemetry != nil {
+			kinds = append(kinds, "opentelemetry")
+		}
+		if len(kinds) != 1 {
+			errs = errors.Append(errs, errors.Errorf("source %s must have exactly one source type, got %v", src.Name, kinds))
+		}
+
+		transport := src.Transport()
+		if transport == "tls" && !tlsEnabled {
+			errs = errors.Append(errs, errors.Errorf("source %s uses the tls transport, but TLS is not enabled", src.Name))
+		}
+		protocol := "tcp"
+		if transport == "udp" {
+			protocol = "udp"
+		}
+		port := fmt.Sprintf("%s/%d", protocol, src.Port)
+		if other, ok := ports[port]; ok {
+			errs = errors.Append(errs, errors.Errorf("source %s listens on the same port as %s: %s", src.Name, other, port))
+		}
+		ports[port] = src.Name
+	}
+	return errs
+}
+
+// renderExtraSource renders a source attributing its records to the given namespace,
+// so that they are processed by the flows of the namespace as well
+func renderExtraSource(src v1beta1.SyslogNGSource, namespace string, keyDelim string, tls *TLS) render.Renderer {
+	var driver any
+	switch {
+	case src.Syslog != nil:
+		d := SyslogSourceDriver{
+			Flags:     src.Syslog.Flags,
+			Port:      uint16(src.Port),
+			Transport: src.Transport(),
+		}
+		if d.Transport == "tls" {
+			d.TLS = tls
+		}
+		driver = d
+	case src.Network != nil:
+		d := NetworkSourceDriver{
+			Flags:     src.Network.Flags,
+			Port:      uint16(src.Port),
+			Transport: src.Transport(),
+		}
+		if d.Transport == "tls" {
+			d.TLS = tls
+		}
+		driver = d
+	case src.OpenTelemetry != nil:
+		driver = OpenTelemetrySourceDriver{
+			Port: uint16(src.Port),
+		}
+	default:
+		return render.Error(errors.Errorf("no source type specified on source %s", src.Name))
+	}
+	return sourceDefStmt(extraSourceName(src.Name),
+		channelDefStmt(
+			sourceDefStmt("", renderDriver(Field{Value: reflect.ValueOf(driver)}, nil)),
+			[]render.Renderer{
+				rewriteDefStmt("", parenDefStmt("set",
+					render.Quoted(namespace),
+					optionExpr("value", render.Quoted(strings.Join([]string{"json", "kubernetes", "namespace_name"}, keyDelim))),
+				)),
+			},
+		))
+}
@@ -22,6 +22,7 @@ import (
 
 type SystemBuilder struct {
 	input         Input
+	extraInputs   []Input
 	globalFilters []Filter
 	flows         []*Flow
 	router        *Router
@@ -35,6 +36,10 @@ func NewSystemBuilder(input Input, globalFilers []Filter, router *Router) *Syste
 	}
 }
 
+func (s *SystemBuilder) RegisterInput(input Input) {
+	s.extraInputs = append(s.extraInputs, input)
+}
+
 func (s *SystemBuilder) RegisterFlow(f *Flow) error {
 	for _, e := range s.flows {
 		if e.FlowLabel == f.FlowLabel {
@@ -81,6 +86,7 @@ func (s *SystemBuilder) RegisterDefaultFlow(f *Flow) error {
 func (s *SystemBuilder) Build() (*System, error) {
 	return &System{
 		Input:         s.input,
+		ExtraInputs:   s.extraInputs,
 		GlobalFilters: s.globalFilters,
 		Router:        s.router,
 		Flows:         s.flows,
@@ -29,6 +29,7 @@ type FluentConfig interface {
 
 type System struct {
 	Input         Input    `json:"input"`
+	ExtraInputs   []Input  `json:"extraInputs,omitempty"`
 	GlobalFilters []Filter `json:"globalFilters"`
 	Router        *Router  `json:"router"`
 	Flows         []*Flow  `json:"flows"`
@@ -39,6 +40,9 @@ func (s *System) GetDirectives() []Directive {
 	directives := []Directive{
 		s.Input,
 	}
+	for _, input := range s.ExtraInputs {
+		directives = append(directives, input)
+	}
 	// Add GlobalFilters between input and router
 	for _, filter := range s.GlobalFilters {
 		directives = append(directives, filter)
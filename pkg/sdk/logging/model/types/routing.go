@@ -0,0 +1,152 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+)
+
+// RouteMetadata describes the kubernetes metadata of a record the same way the label_router plugin sees it
+type RouteMetadata struct {
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Container string            `json:"container,omitempty"`
+	Host      string            `json:"host,omitempty"`
+}
+
+// Selects reports whether every non-empty criteria of the match applies to the metadata
+func (f FlowMatch) Selects(metadata RouteMetadata) bool {
+	if !selectsValue(f.Hosts, metadata.Host) {
+		return false
+	}
+	if !selectsValue(f.ContainerNames, metadata.Container) {
+		return false
+	}
+	if !selectsValue(f.Namespaces, metadata.Namespace) {
+		return false
+	}
+	for key, value := range f.Labels {
+		if v, ok := metadata.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesRoute evaluates the matches of a route in order, the first selecting match decides the result
+// and a route without any selecting match does not receive the record
+func MatchesRoute(matches []FlowMatch, metadata RouteMetadata) bool {
+	for _, match := range matches {
+		if match.Selects(metadata) {
+			return !match.Negate
+		}
+	}
+	return false
+}
+
+// RoutingTable is the machine readable form of the routes configured in the label_router
+type RoutingTable struct {
+	Routes       []RouteEntry `json:"routes"`
+	DefaultRoute *RouteEntry  `json:"defaultRoute,omitempty"`
+}
+
+// RouteEntry is a single route with the flow and the outputs it leads to
+type RouteEntry struct {
+	Label   string      `json:"label"`
+	FlowID  string      `json:"flowID,omitempty"`
+	Matches []FlowMatch `json:"matches,omitempty"`
+	Outputs []string    `json:"outputs,omitempty"`
+}
+
+// NewRoutingTable collects the routes of the system's router and resolves the outputs of the target flows
+func NewRoutingTable(system *System) RoutingTable {
+	flows := make(map[string]*Flow, len(system.Flows))
+	for _, flow := range system.Flows {
+		flows[flow.FlowLabel] = flow
+	}
+
+	table := RoutingTable{
+		Routes: []RouteEntry{},
+	}
+	if system.Router == nil {
+		return table
+	}
+	for _, directive := range system.Router.Routes {
+		route, ok := directive.(*FlowRoute)
+		if !ok {
+			continue
+		}
+		entry := newRouteEntry(route.Label, flows)
+		for _, m := range route.Matches {
+			if match, ok := m.(FlowMatch); ok {
+				entry.Matches = append(entry.Matches, match)
+			}
+		}
+		table.Routes = append(table.Routes, entry)
+	}
+	if label := system.Router.Params["default_route"]; label != "" {
+		entry := newRouteEntry(label, flows)
+		table.DefaultRoute = &entry
+	}
+	return table
+}
+
+func newRouteEntry(label string, flows map[string]*Flow) RouteEntry {
+	entry := RouteEntry{
+		Label: label,
+	}
+	if flow, ok := flows[label]; ok {
+		entry.FlowID = flow.FlowID
+		for _, output := range flow.Outputs {
+			entry.Outputs = append(entry.Outputs, output.GetPluginMeta().Id)
+		}
+	}
+	return entry
+}
+
+// Match returns the routes receiving a record with the given metadata, falling back to the default route
+func (t RoutingTable) Match(metadata RouteMetadata) []RouteEntry {
+	var result []RouteEntry
+	for _, route := range t.Routes {
+		if MatchesRoute(route.Matches, metadata) {
+			result = append(result, route)
+		}
+	}
+	if len(result) == 0 && t.DefaultRoute != nil {
+		result = append(result, *t.DefaultRoute)
+	}
+	return result
+}
+
+// JSON renders the routing table in an indented, stable form
+func (t RoutingTable) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// selectsValue mirrors how the plugin parses its comma separated lists: empty items are dropped
+// and an empty list selects every value
+func selectsValue(values []string, value string) bool {
+	empty := true
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if v == value {
+			return true
+		}
+		empty = false
+	}
+	return empty
+}
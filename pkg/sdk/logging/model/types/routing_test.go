@@ -0,0 +1,145 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+func TestMatchesRoute(t *testing.T) {
+	metadata := types.RouteMetadata{
+		Namespace: "apps",
+		Labels:    map[string]string{"app": "web", "tier": "frontend"},
+		Container: "nginx",
+		Host:      "node-1",
+	}
+	testCases := map[string]struct {
+		matches  []types.FlowMatch
+		expected bool
+	}{
+		"no matches": {
+			expected: false,
+		},
+		"empty match selects everything": {
+			matches:  []types.FlowMatch{{}},
+			expected: true,
+		},
+		"namespace": {
+			matches:  []types.FlowMatch{{Namespaces: []string{"other", "apps"}}},
+			expected: true,
+		},
+		"empty namespace selects every namespace": {
+			matches:  []types.FlowMatch{{Namespaces: []string{""}}},
+			expected: true,
+		},
+		"namespace mismatch": {
+			matches:  []types.FlowMatch{{Namespaces: []string{"other"}}},
+			expected: false,
+		},
+		"labels subset": {
+			matches:  []types.FlowMatch{{Labels: map[string]string{"app": "web"}}},
+			expected: true,
+		},
+		"labels mismatch": {
+			matches:  []types.FlowMatch{{Labels: map[string]string{"app": "web", "tier": "backend"}}},
+			expected: false,
+		},
+		"missing label": {
+			matches:  []types.FlowMatch{{Labels: map[string]string{"env": ""}}},
+			expected: false,
+		},
+		"container and host": {
+			matches:  []types.FlowMatch{{ContainerNames: []string{"nginx"}, Hosts: []string{"node-1"}}},
+			expected: true,
+		},
+		"host mismatch": {
+			matches:  []types.FlowMatch{{ContainerNames: []string{"nginx"}, Hosts: []string{"node-2"}}},
+			expected: false,
+		},
+		"exclude before select": {
+			matches: []types.FlowMatch{
+				{Labels: map[string]string{"app": "web"}, Negate: true},
+				{Namespaces: []string{"apps"}},
+			},
+			expected: false,
+		},
+		"select before exclude": {
+			matches: []types.FlowMatch{
+				{Namespaces: []string{"apps"}},
+				{Labels: map[string]string{"app": "web"}, Negate: true},
+			},
+			expected: true,
+		},
+		"exclude only": {
+			matches:  []types.FlowMatch{{Labels: map[string]string{"app": "db"}, Negate: true}},
+			expected: false,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, types.MatchesRoute(tc.matches, metadata))
+		})
+	}
+}
+
+func TestRoutingTable(t *testing.T) {
+	router := types.NewRouter("main", types.Params{})
+	builder := types.NewSystemBuilder(nil, nil, router)
+
+	web, err := types.NewFlow([]types.FlowMatch{{Labels: map[string]string{"app": "web"}, Namespaces: []string{"apps"}}}, "flow:apps:web", "web", "apps", "", nil)
+	require.NoError(t, err)
+	web.WithOutputs(&types.GenericDirective{PluginMeta: types.PluginMeta{Type: "null", Id: "flow:apps:web:output:apps:null", Directive: "match", Tag: "**"}})
+	require.NoError(t, builder.RegisterFlow(web))
+
+	all, err := types.NewFlow([]types.FlowMatch{{Namespaces: []string{"apps"}}}, "flow:apps:all", "all", "apps", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, builder.RegisterFlow(all))
+
+	unrouted, err := types.NewFlow([]types.FlowMatch{{}}, "flow:apps:unrouted", "unrouted", "apps", "custom", nil)
+	require.NoError(t, err)
+	require.NoError(t, builder.RegisterFlow(unrouted))
+
+	fallback, err := types.NewFlow(nil, "logging:default", "default", "logging", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, builder.RegisterDefaultFlow(fallback))
+
+	system, err := builder.Build()
+	require.NoError(t, err)
+
+	table := types.NewRoutingTable(system)
+	require.Len(t, table.Routes, 2)
+	assert.Equal(t, web.FlowLabel, table.Routes[0].Label)
+	assert.Equal(t, "flow:apps:web", table.Routes[0].FlowID)
+	assert.Equal(t, []string{"flow:apps:web:output:apps:null"}, table.Routes[0].Outputs)
+	assert.Equal(t, web.Matches, table.Routes[0].Matches)
+	require.NotNil(t, table.DefaultRoute)
+	assert.Equal(t, fallback.FlowLabel, table.DefaultRoute.Label)
+
+	var ids []string
+	for _, route := range table.Match(types.RouteMetadata{Namespace: "apps", Labels: map[string]string{"app": "web"}}) {
+		ids = append(ids, route.FlowID)
+	}
+	assert.Equal(t, []string{"flow:apps:web", "flow:apps:all"}, ids)
+
+	matched := table.Match(types.RouteMetadata{Namespace: "kube-system"})
+	require.Len(t, matched, 1)
+	assert.Equal(t, "logging:default", matched[0].FlowID)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+)
+
+// builtinLabels are handled by fluentd itself, references to them don't need a <label> directive
+var builtinLabels = map[string]bool{
+	"@ERROR":      true,
+	"@FLUENT_LOG": true,
+	"@ROOT":       true,
+}
+
+type labelReference struct {
+	label string
+	path  string
+}
+
+type configValidator struct {
+	ids       map[string]string
+	labels    map[string]bool
+	labelRefs []labelReference
+	errs      error
+}
+
+// ValidateConfig checks the references of the config without running fluentd:
+// the @id of the plugins must be unique and every label reference must have a <label> directive.
+// Plugin params aren't checked here, the required ones are enforced when the plugin structs are mapped to params,
+// but the values are only validated by fluentd itself.
+func ValidateConfig(config FluentConfig) error {
+	v := configValidator{
+		ids:    make(map[string]string),
+		labels: make(map[string]bool),
+	}
+	v.validateDirectives(config.GetDirectives(), "")
+	for _, ref := range v.labelRefs {
+		if !builtinLabels[ref.label] && !v.labels[ref.label] {
+			v.errs = errors.Append(v.errs, errors.Errorf("%s refers to label %s, but there is no such <label>", ref.path, ref.label))
+		}
+	}
+	return v.errs
+}
+
+func (v *configValidator) validateDirectives(directives []Directive, parent string) {
+	for _, d := range directives {
+		if d == nil {
+			continue
+		}
+		meta := d.GetPluginMeta()
+		path := directivePath(parent, meta)
+		if meta.Directive == "" {
+			v.errs = errors.Append(v.errs, errors.Errorf("%s has no directive name", path))
+		}
+		if meta.Id != "" {
+			if other, ok := v.ids[meta.Id]; ok {
+				v.errs = errors.Append(v.errs, errors.Errorf("duplicate @id %s in %s and %s", meta.Id, other, path))
+			} else {
+				v.ids[meta.Id] = path
+			}
+		}
+		if meta.Directive == "label" {
+			v.labels[meta.Tag] = true
+		}
+		if meta.Label != "" {
+			v.labelRefs = append(v.labelRefs, labelReference{label: meta.Label, path: path})
+		}
+		if meta.Type == "label_router" {
+			if label := d.GetParams()["default_route"]; label != "" {
+				v.labelRefs = append(v.labelRefs, labelReference{label: label, path: path})
+			}
+		}
+		v.validateDirectives(d.GetSections(), path)
+	}
+}
+
+func directivePath(parent string, meta *PluginMeta) string {
+	path := fmt.Sprintf("<%s%s>", meta.Directive, tag(meta.Tag))
+	if meta.Id != "" {
+		path = fmt.Sprintf("%s(@id %s)", path, meta.Id)
+	}
+	if parent != "" {
+		path = parent + "/" + path
+	}
+	return path
+}
+
+func tag(tag string) string {
+	if tag != "" {
+		return " " + tag
+	}
+	return tag
+}
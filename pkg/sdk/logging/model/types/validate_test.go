@@ -0,0 +1,75 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+type directives []types.Directive
+
+func (d directives) GetDirectives() []types.Directive {
+	return d
+}
+
+func TestValidateConfig(t *testing.T) {
+	output := &types.GenericDirective{
+		PluginMeta: types.PluginMeta{Directive: "match", Type: "file", Id: "out", Tag: "**"},
+		Params:     types.Params{"path": "/tmp/out"},
+	}
+
+	valid := directives{
+		&types.GenericDirective{
+			PluginMeta: types.PluginMeta{Directive: "match", Type: "relabel", Id: "relabel", Tag: "**", Label: "@flow"},
+		},
+		&types.GenericDirective{
+			PluginMeta:    types.PluginMeta{Directive: "label", Tag: "@flow"},
+			SubDirectives: []types.Directive{output},
+		},
+		&types.GenericDirective{
+			PluginMeta: types.PluginMeta{Directive: "match", Type: "relabel", Tag: "**", Label: "@ERROR"},
+		},
+	}
+	require.NoError(t, types.ValidateConfig(valid))
+
+	router := types.NewRouter("router", map[string]string{"default_route": "@default"})
+	router.Routes = append(router.Routes, &types.FlowRoute{PluginMeta: types.PluginMeta{Directive: "route", Label: "@flow"}})
+	invalid := directives{
+		router,
+		&types.GenericDirective{
+			PluginMeta:    types.PluginMeta{Directive: "label", Tag: "@flow"},
+			SubDirectives: []types.Directive{output, output},
+		},
+	}
+	err := types.ValidateConfig(invalid)
+	require.Error(t, err)
+	require.ElementsMatch(t, []string{
+		"duplicate @id out in <label @flow>/<match **>(@id out) and <label @flow>/<match **>(@id out)",
+		"<match **>(@id router) refers to label @default, but there is no such <label>",
+	}, errorMessages(err))
+}
+
+func errorMessages(err error) []string {
+	var messages []string
+	for _, err := range errors.GetErrors(err) {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
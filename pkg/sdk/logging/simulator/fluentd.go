@@ -0,0 +1,411 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+const defaultTagNormaliserFormat = "${namespace_name}.${pod_name}.${container_name}"
+
+// FlowMatches converts the match statements of a Flow to the routes of the label router
+func FlowMatches(flow v1beta1.Flow) []types.FlowMatch {
+	if flow.Spec.Match == nil {
+		return []types.FlowMatch{
+			{
+				Labels:     flow.Spec.Selectors,
+				Namespaces: []string{flow.Namespace},
+			},
+		}
+	}
+	var matches []types.FlowMatch
+	for _, match := range flow.Spec.Match {
+		if match.Select != nil {
+			matches = append(matches, types.FlowMatch{
+				Labels:         match.Select.Labels,
+				ContainerNames: match.Select.ContainerNames,
+				Hosts:          match.Select.Hosts,
+				Namespaces:     []string{flow.Namespace},
+			})
+		}
+		if match.Exclude != nil {
+			matches = append(matches, types.FlowMatch{
+				Labels:         match.Exclude.Labels,
+				ContainerNames: match.Exclude.ContainerNames,
+				Hosts:          match.Exclude.Hosts,
+				Namespaces:     []string{flow.Namespace},
+				Negate:         true,
+			})
+		}
+	}
+	return matches
+}
+
+// ClusterFlowMatches converts the match statements of a ClusterFlow to the routes of the label router
+func ClusterFlowMatches(flow v1beta1.ClusterFlow) []types.FlowMatch {
+	if flow.Spec.Match == nil {
+		return []types.FlowMatch{
+			{
+				Labels:     flow.Spec.Selectors,
+				Namespaces: []string{""},
+			},
+		}
+	}
+	var matches []types.FlowMatch
+	for _, match := range flow.Spec.Match {
+		if match.ClusterSelect != nil {
+			matches = append(matches, types.FlowMatch{
+				Labels:         match.ClusterSelect.Labels,
+				ContainerNames: match.ClusterSelect.ContainerNames,
+				Hosts:          match.ClusterSelect.Hosts,
+				Namespaces:     match.ClusterSelect.Namespaces,
+			})
+		}
+		if match.ClusterExclude != nil {
+			matches = append(matches, types.FlowMatch{
+				Labels:         match.ClusterExclude.Labels,
+				ContainerNames: match.ClusterExclude.ContainerNames,
+				Hosts:          match.ClusterExclude.Hosts,
+				Namespaces:     match.ClusterExclude.Namespaces,
+				Negate:         true,
+			})
+		}
+	}
+	return matches
+}
+
+// routed reports whether the label router sends records to the flow, see types.NewFlow
+func routed(flowLabel string, includeLabelInRouter *bool) bool {
+	if flowLabel == "" {
+		return includeLabelInRouter == nil || *includeLabelInRouter
+	}
+	return includeLabelInRouter != nil && *includeLabelInRouter
+}
+
+func (s *simulation) fluentdDefaultFlow() *v1beta1.DefaultFlowSpec {
+	if s.resources.Logging == nil {
+		return nil
+	}
+	return s.resources.Logging.Spec.DefaultFlowSpec
+}
+
+// fluentd processes an event the way the fluentd aggregator does: global filters, label router, flows
+func (s *simulation) fluentd(idx int, event Event) bool {
+	tag := event.Tag
+	record := copyRecord(event.Record)
+	if s.resources.Logging != nil {
+		global := Ref{Kind: "Logging", Namespace: s.resources.Logging.Namespace, Name: s.resources.Logging.Name}
+		var keep bool
+		if tag, record, keep = s.fluentdFilters(idx, global, s.resources.Logging.Spec.GlobalFilters, tag, record); !keep {
+			return true
+		}
+	}
+
+	metadata := routeMetadata(record)
+	matched := false
+	for _, flow := range s.resources.Flows {
+		if !routed(flow.Spec.FlowLabel, flow.Spec.IncludeLabelInRouter) || !types.MatchesRoute(FlowMatches(flow), metadata) {
+			continue
+		}
+		matched = true
+		ref := Ref{Kind: "Flow", Namespace: flow.Namespace, Name: flow.Name}
+		var outputs []Ref
+		for _, name := range append(flow.Spec.LocalOutputRefs, flow.Spec.OutputRefs...) {
+			outputs = append(outputs, Ref{Kind: "Output", Namespace: flow.Namespace, Name: name})
+		}
+		for _, name := range flow.Spec.GlobalOutputRefs {
+			outputs = append(outputs, Ref{Kind: "ClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		for _, shared := range flow.Spec.SharedOutputRefs {
+			outputs = append(outputs, Ref{Kind: "Output", Namespace: shared.Namespace, Name: shared.Name})
+		}
+		s.fluentdFlow(idx, ref, flow.Spec.Filters, outputs, tag, record)
+	}
+	for _, flow := range s.resources.ClusterFlows {
+		if !routed(flow.Spec.FlowLabel, flow.Spec.IncludeLabelInRouter) || !types.MatchesRoute(ClusterFlowMatches(flow), metadata) {
+			continue
+		}
+		if s.strictTenancy() && flow.Namespace != s.controlNamespace() && metadata.Namespace != flow.Namespace {
+			continue
+		}
+		matched = true
+		ref := Ref{Kind: "ClusterFlow", Namespace: flow.Namespace, Name: flow.Name}
+		var outputs []Ref
+		for _, name := range append(flow.Spec.GlobalOutputRefs, flow.Spec.OutputRefs...) {
+			outputs = append(outputs, Ref{Kind: "ClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		s.fluentdFlow(idx, ref, flow.Spec.Filters, outputs, tag, record)
+	}
+	if !matched {
+		defaultFlow := s.fluentdDefaultFlow()
+		if defaultFlow == nil {
+			return false
+		}
+		var outputs []Ref
+		for _, name := range append(defaultFlow.GlobalOutputRefs, defaultFlow.OutputRefs...) {
+			outputs = append(outputs, Ref{Kind: "ClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		s.fluentdFlow(idx, Ref{Kind: "DefaultFlow"}, defaultFlow.Filters, outputs, tag, record)
+	}
+	return true
+}
+
+func (s *simulation) fluentdFlow(idx int, flow Ref, filters []v1beta1.Filter, outputs []Ref, tag string, record map[string]any) {
+	tag, record, keep := s.fluentdFilters(idx, flow, filters, tag, copyRecord(record))
+	if keep {
+		s.deliver(idx, flow, outputs, tag, record)
+	}
+}
+
+func (s *simulation) fluentdFilters(idx int, flow Ref, filters []v1beta1.Filter, tag string, record map[string]any) (string, map[string]any, bool) {
+	for i, f := range filters {
+		name := fmt.Sprintf("filters[%d] %s", i, fluentdFilterType(f))
+		switch {
+		case f.Grep != nil:
+			if !s.grep(flow, name, f.Grep, record) {
+				s.drop(idx, flow, name)
+				return tag, record, false
+			}
+		case f.RecordModifier != nil:
+			record = s.recordModifier(flow, name, f.RecordModifier, tag, record)
+		case f.RecordTransformer != nil:
+			record = s.recordTransformer(flow, name, f.RecordTransformer, tag, record)
+		case f.Dedot != nil:
+			record = dedot(f.Dedot, record)
+		case f.TagNormaliser != nil:
+			tag = tagNormaliser(f.TagNormaliser, record)
+		case f.StdOut != nil:
+		default:
+			s.warn("%s %s: filter is not simulated, records pass unchanged", flow, name)
+		}
+	}
+	return tag, record, true
+}
+
+// fluentdFilterType returns the name of the configured filter
+func fluentdFilterType(f v1beta1.Filter) string {
+	v := reflect.ValueOf(f)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			return strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return "unknown"
+}
+
+// compilePattern compiles the regexp of a fluentd parameter, which may be given in the /pattern/ form
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	switch {
+	case len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/i"):
+		pattern = "(?i)" + pattern[1:len(pattern)-2]
+	case len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		pattern = pattern[1 : len(pattern)-1]
+	}
+	return regexp.Compile(pattern)
+}
+
+// grepMatches reports whether the value at the key matches the pattern, missing keys never match
+func (s *simulation) grepMatches(flow Ref, filter string, key, pattern string, record map[string]any) bool {
+	path, err := parseAccessor(key)
+	if err != nil {
+		s.warn("%s %s: %s", flow, filter, err)
+		return false
+	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		s.warn("%s %s: invalid pattern %q: %s", flow, filter, pattern, err)
+		return false
+	}
+	value, ok := lookup(record, path)
+	return ok && re.MatchString(stringValue(value))
+}
+
+// grep reports whether the record is kept by the grep filter
+func (s *simulation) grep(flow Ref, name string, config *filter.GrepConfig, record map[string]any) bool {
+	for _, r := range config.Regexp {
+		if !s.grepMatches(flow, name, r.Key, r.Pattern, record) {
+			return false
+		}
+	}
+	for _, e := range config.Exclude {
+		if s.grepMatches(flow, name, e.Key, e.Pattern, record) {
+			return false
+		}
+	}
+	for _, or := range config.Or {
+		if len(or.Regexp) > 0 {
+			selected := false
+			for _, r := range or.Regexp {
+				selected = selected || s.grepMatches(flow, name, r.Key, r.Pattern, record)
+			}
+			if !selected {
+				return false
+			}
+		}
+		for _, e := range or.Exclude {
+			if s.grepMatches(flow, name, e.Key, e.Pattern, record) {
+				return false
+			}
+		}
+	}
+	for _, and := range config.And {
+		for _, r := range and.Regexp {
+			if !s.grepMatches(flow, name, r.Key, r.Pattern, record) {
+				return false
+			}
+		}
+		if len(and.Exclude) > 0 {
+			all := true
+			for _, e := range and.Exclude {
+				all = all && s.grepMatches(flow, name, e.Key, e.Pattern, record)
+			}
+			if all {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *simulation) expand(flow Ref, name string, value string, tag string, record map[string]any) string {
+	result, unsupported := expandPlaceholders(value, tag, record)
+	for _, placeholder := range unsupported {
+		s.warn("%s %s: placeholder %s is not simulated", flow, name, placeholder)
+	}
+	return result
+}
+
+func (s *simulation) recordModifier(flow Ref, name string, config *filter.RecordModifier, tag string, record map[string]any) map[string]any {
+	result := copyRecord(record)
+	for _, records := range config.Records {
+		for key, value := range records {
+			result[key] = s.expand(flow, name, value, tag, record)
+		}
+	}
+	for _, key := range splitKeys(config.RemoveKeys) {
+		delete(result, key)
+	}
+	if whitelist := splitKeys(config.WhitelistKeys); len(whitelist) > 0 {
+		kept := make(map[string]any, len(whitelist))
+		for _, key := range whitelist {
+			if value, ok := result[key]; ok {
+				kept[key] = value
+			}
+		}
+		result = kept
+	}
+	for _, replace := range config.Replaces {
+		value, ok := result[replace.Key].(string)
+		if !ok {
+			continue
+		}
+		re, err := compilePattern(replace.Expression)
+		if err != nil {
+			s.warn("%s %s: invalid expression %q: %s", flow, name, replace.Expression, err)
+			continue
+		}
+		result[replace.Key] = re.ReplaceAllString(value, replace.Replace)
+	}
+	if config.PrepareValues != "" {
+		s.warn("%s %s: prepare_value is not simulated", flow, name)
+	}
+	return result
+}
+
+func (s *simulation) recordTransformer(flow Ref, name string, config *filter.RecordTransformer, tag string, record map[string]any) map[string]any {
+	var result map[string]any
+	if config.RenewRecord {
+		result = map[string]any{}
+		for _, key := range splitKeys(config.KeepKeys) {
+			if value, ok := record[key]; ok {
+				result[key] = copyValue(value)
+			}
+		}
+	} else {
+		result = copyRecord(record)
+	}
+	for _, records := range config.Records {
+		for key, value := range records {
+			result[key] = s.expand(flow, name, value, tag, record)
+		}
+	}
+	for _, key := range splitKeys(config.RemoveKeys) {
+		path, err := parseAccessor(key)
+		if err != nil {
+			s.warn("%s %s: %s", flow, name, err)
+			continue
+		}
+		remove(result, path)
+	}
+	return result
+}
+
+func dedot(config *filter.DedotFilterConfig, record map[string]any) map[string]any {
+	separator := config.Separator
+	if separator == "" {
+		separator = "_"
+	}
+	return dedotMap(record, separator, config.Nested)
+}
+
+func dedotMap(record map[string]any, separator string, nested bool) map[string]any {
+	result := make(map[string]any, len(record))
+	for key, value := range record {
+		if nested {
+			value = dedotValue(value, separator)
+		}
+		result[strings.ReplaceAll(key, ".", separator)] = value
+	}
+	return result
+}
+
+func dedotValue(value any, separator string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return dedotMap(v, separator, true)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = dedotValue(item, separator)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// tagNormaliser builds the tag from the kubernetes metadata, labels are available as ${labels.<key>}
+func tagNormaliser(config *filter.TagNormaliser, record map[string]any) string {
+	format := config.Format
+	if format == "" {
+		format = defaultTagNormaliserFormat
+	}
+	kubernetes, _ := record["kubernetes"].(map[string]any)
+	return placeholderPattern.ReplaceAllStringFunc(format, func(placeholder string) string {
+		key := placeholder[2 : len(placeholder)-1]
+		if label, ok := strings.CutPrefix(key, "labels."); ok {
+			value, _ := lookup(kubernetes, []string{"labels", label})
+			return stringValue(value)
+		}
+		return stringValue(kubernetes[key])
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/types"
+)
+
+// copyRecord returns a deep copy of the record so that flows can modify it independently
+func copyRecord(record map[string]any) map[string]any {
+	result := make(map[string]any, len(record))
+	for k, v := range record {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return copyRecord(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = copyValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// stringValue converts a record value to the form the plugins match their patterns against
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// lookup returns the value at the given path of nested maps
+func lookup(record map[string]any, path []string) (any, bool) {
+	var current any = record
+	for _, key := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// remove deletes the value at the given path of nested maps
+func remove(record map[string]any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	parent, ok := lookup(record, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	if m, ok := parent.(map[string]any); ok {
+		delete(m, path[len(path)-1])
+	}
+}
+
+var accessorToken = regexp.MustCompile(`^(?:\.([^.\[]+)|\[['"]([^'"]*)['"]\])`)
+
+// parseAccessor parses the record_accessor syntax of fluentd: `$.key.nested`, `$['key']['nested']`
+// or a plain top level key
+func parseAccessor(key string) ([]string, error) {
+	if !strings.HasPrefix(key, "$") {
+		return []string{key}, nil
+	}
+	var path []string
+	rest := key[1:]
+	for rest != "" {
+		m := accessorToken.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, errors.Errorf("invalid record accessor %q", key)
+		}
+		if m[1] != "" {
+			path = append(path, m[1])
+		} else {
+			path = append(path, m[2])
+		}
+		rest = rest[len(m[0]):]
+	}
+	if len(path) == 0 {
+		return nil, errors.Errorf("invalid record accessor %q", key)
+	}
+	return path, nil
+}
+
+// routeMetadata extracts the kubernetes metadata the label router matches on
+func routeMetadata(record map[string]any) types.RouteMetadata {
+	metadata := types.RouteMetadata{
+		Labels: map[string]string{},
+	}
+	kubernetes, ok := record["kubernetes"].(map[string]any)
+	if !ok {
+		return metadata
+	}
+	metadata.Namespace = stringValue(kubernetes["namespace_name"])
+	metadata.Container = stringValue(kubernetes["container_name"])
+	metadata.Host = stringValue(kubernetes["host"])
+	if labels, ok := kubernetes["labels"].(map[string]any); ok {
+		for k, v := range labels {
+			metadata.Labels[k] = stringValue(v)
+		}
+	}
+	return metadata
+}
+
+var placeholderPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+var tagPartPattern = regexp.MustCompile(`^tag_parts\[(-?\d+)\]$`)
+var recordIndexPattern = regexp.MustCompile(`^record((?:\[['"][^'"]*['"]\])+)$`)
+var recordDigPattern = regexp.MustCompile(`^record\.dig\((.*)\)$`)
+var indexPattern = regexp.MustCompile(`\[['"]([^'"]*)['"]\]`)
+
+// expandPlaceholders resolves the placeholders supported by record_modifier and record_transformer:
+// ${tag}, ${tag_parts[N]}, ${record["key"]}, ${record.dig("key", "nested")} and ${key}
+func expandPlaceholders(value string, tag string, record map[string]any) (string, []string) {
+	var unsupported []string
+	result := placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		expr := strings.TrimSpace(placeholder[2 : len(placeholder)-1])
+		if resolved, ok := resolvePlaceholder(expr, tag, record); ok {
+			return resolved
+		}
+		unsupported = append(unsupported, placeholder)
+		return placeholder
+	})
+	return result, unsupported
+}
+
+func resolvePlaceholder(expr string, tag string, record map[string]any) (string, bool) {
+	if expr == "tag" {
+		return tag, true
+	}
+	if m := tagPartPattern.FindStringSubmatch(expr); m != nil {
+		parts := strings.Split(tag, ".")
+		idx, _ := strconv.Atoi(m[1])
+		if idx < 0 {
+			idx += len(parts)
+		}
+		if idx < 0 || idx >= len(parts) {
+			return "", true
+		}
+		return parts[idx], true
+	}
+	if m := recordIndexPattern.FindStringSubmatch(expr); m != nil {
+		var path []string
+		for _, key := range indexPattern.FindAllStringSubmatch(m[1], -1) {
+			path = append(path, key[1])
+		}
+		value, _ := lookup(record, path)
+		return stringValue(value), true
+	}
+	if m := recordDigPattern.FindStringSubmatch(expr); m != nil {
+		var path []string
+		for _, key := range strings.Split(m[1], ",") {
+			path = append(path, strings.Trim(strings.TrimSpace(key), `'"`))
+		}
+		value, _ := lookup(record, path)
+		return stringValue(value), true
+	}
+	if !strings.ContainsAny(expr, " ()[]{}") {
+		return stringValue(record[expr]), true
+	}
+	return "", false
+}
+
+// splitKeys splits the comma separated key lists of the fluentd filters
+func splitKeys(keys string) []string {
+	var result []string
+	for _, key := range strings.Split(keys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
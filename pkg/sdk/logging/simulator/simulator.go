@@ -0,0 +1,173 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulator routes sample log records through the flows of a logging configuration offline
+// and reports what each output would receive.
+//
+// Only a subset of the filters is evaluated: grep, record_modifier, record_transformer, dedot and tag_normaliser
+// for fluentd flows, match and rewrite for syslog-ng flows. Other filters pass the records unchanged and are
+// reported as warnings. Ruby expressions are not evaluated, only the plain placeholders of the record filters.
+package simulator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+)
+
+// Resources are the logging resources the records are routed through.
+// The Logging is optional, it provides the global filters, the default flows and the control namespace.
+type Resources struct {
+	Logging              *v1beta1.Logging
+	Flows                []v1beta1.Flow
+	ClusterFlows         []v1beta1.ClusterFlow
+	SyslogNGFlows        []v1beta1.SyslogNGFlow
+	SyslogNGClusterFlows []v1beta1.SyslogNGClusterFlow
+}
+
+// Event is a log record with kubernetes metadata as it arrives from the log forwarders
+type Event struct {
+	Tag    string         `json:"tag,omitempty"`
+	Record map[string]any `json:"record"`
+}
+
+// Ref identifies a flow or an output
+type Ref struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+func (r Ref) String() string {
+	if r.Name == "" {
+		return r.Kind
+	}
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// Delivery is a record received by an output through a flow
+type Delivery struct {
+	Event  int            `json:"event"`
+	Flow   Ref            `json:"flow"`
+	Output Ref            `json:"output"`
+	Tag    string         `json:"tag,omitempty"`
+	Record map[string]any `json:"record"`
+}
+
+// Drop is a record discarded by a filter of a flow
+type Drop struct {
+	Event  int    `json:"event"`
+	Flow   Ref    `json:"flow"`
+	Filter string `json:"filter"`
+}
+
+// Result collects the outcome of a simulation
+type Result struct {
+	Deliveries []Delivery `json:"deliveries"`
+	Drops      []Drop     `json:"drops,omitempty"`
+	// Events not matched by any flow
+	Unrouted []int    `json:"unrouted,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Received returns the records delivered to the given output
+func (r Result) Received(output Ref) []Delivery {
+	var result []Delivery
+	for _, delivery := range r.Deliveries {
+		if delivery.Output == output {
+			result = append(result, delivery)
+		}
+	}
+	return result
+}
+
+// Reached reports whether the event was delivered to the given output by any flow
+func (r Result) Reached(event int, output Ref) bool {
+	for _, delivery := range r.Received(output) {
+		if delivery.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Simulate routes every event through the fluentd and the syslog-ng flows of the resources
+func Simulate(resources Resources, events []Event) Result {
+	s := &simulation{
+		resources: resources,
+		warnings:  map[string]struct{}{},
+	}
+	for idx, event := range events {
+		routed := false
+		if len(resources.Flows) > 0 || len(resources.ClusterFlows) > 0 || s.fluentdDefaultFlow() != nil {
+			routed = s.fluentd(idx, event) || routed
+		}
+		if len(resources.SyslogNGFlows) > 0 || len(resources.SyslogNGClusterFlows) > 0 || s.syslogNGDefaultFlow() != nil {
+			routed = s.syslogNG(idx, event) || routed
+		}
+		if !routed {
+			s.result.Unrouted = append(s.result.Unrouted, idx)
+		}
+	}
+	for warning := range s.warnings {
+		s.result.Warnings = append(s.result.Warnings, warning)
+	}
+	sort.Strings(s.result.Warnings)
+	if s.result.Deliveries == nil {
+		s.result.Deliveries = []Delivery{}
+	}
+	return s.result
+}
+
+type simulation struct {
+	resources Resources
+	result    Result
+	warnings  map[string]struct{}
+}
+
+func (s *simulation) warn(format string, args ...any) {
+	s.warnings[fmt.Sprintf(format, args...)] = struct{}{}
+}
+
+func (s *simulation) deliver(idx int, flow Ref, outputs []Ref, tag string, record map[string]any) {
+	for _, output := range outputs {
+		s.result.Deliveries = append(s.result.Deliveries, Delivery{
+			Event:  idx,
+			Flow:   flow,
+			Output: output,
+			Tag:    tag,
+			Record: copyRecord(record),
+		})
+	}
+}
+
+func (s *simulation) drop(idx int, flow Ref, filter string) {
+	s.result.Drops = append(s.result.Drops, Drop{
+		Event:  idx,
+		Flow:   flow,
+		Filter: filter,
+	})
+}
+
+func (s *simulation) controlNamespace() string {
+	if s.resources.Logging == nil {
+		return ""
+	}
+	return s.resources.Logging.Spec.ControlNamespace
+}
+
+func (s *simulation) strictTenancy() bool {
+	return s.resources.Logging != nil && s.resources.Logging.Spec.StrictTenancy
+}
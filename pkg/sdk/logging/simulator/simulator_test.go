@@ -0,0 +1,184 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/filter"
+	syslogngfilter "github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/filter"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/simulator"
+)
+
+func podRecord(namespace, container string, labels map[string]any, message string) map[string]any {
+	return map[string]any{
+		"message": message,
+		"kubernetes": map[string]any{
+			"namespace_name": namespace,
+			"pod_name":       container + "-0",
+			"container_name": container,
+			"host":           "node-1",
+			"labels":         labels,
+		},
+	}
+}
+
+func TestSimulateFluentd(t *testing.T) {
+	resources := simulator.Resources{
+		Logging: &v1beta1.Logging{
+			ObjectMeta: metav1.ObjectMeta{Name: "logging"},
+			Spec: v1beta1.LoggingSpec{
+				ControlNamespace: "logging",
+				DefaultFlowSpec: &v1beta1.DefaultFlowSpec{
+					GlobalOutputRefs: []string{"archive"},
+				},
+			},
+		},
+		Flows: []v1beta1.Flow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+				Spec: v1beta1.FlowSpec{
+					Match: []v1beta1.Match{
+						{Exclude: &v1beta1.Exclude{Labels: map[string]string{"debug": "true"}}},
+						{Select: &v1beta1.Select{Labels: map[string]string{"app.kubernetes.io/name": "web"}}},
+					},
+					Filters: []v1beta1.Filter{
+						{TagNormaliser: &filter.TagNormaliser{}},
+						{Grep: &filter.GrepConfig{
+							Exclude: []filter.ExcludeSection{{Key: "message", Pattern: "/^health/"}},
+						}},
+						{RecordTransformer: &filter.RecordTransformer{
+							Records:    []filter.Record{{"source": "${tag_parts[0]}", "app": `${record["kubernetes"]["labels"]["app.kubernetes.io/name"]}`}},
+							RemoveKeys: "$.kubernetes.host",
+						}},
+						{Dedot: &filter.DedotFilterConfig{Nested: true}},
+						{GeoIP: &filter.GeoIP{}},
+					},
+					LocalOutputRefs: []string{"loki"},
+				},
+			},
+		},
+		ClusterFlows: []v1beta1.ClusterFlow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "logging", Name: "security"},
+				Spec: v1beta1.ClusterFlowSpec{
+					Match: []v1beta1.ClusterMatch{
+						{ClusterSelect: &v1beta1.ClusterSelect{Labels: map[string]string{"security": "true"}}},
+					},
+					Filters: []v1beta1.Filter{
+						{Grep: &filter.GrepConfig{
+							Regexp: []filter.RegexpSection{{Key: "$.kubernetes.namespace_name", Pattern: "/^(apps|auth)$/"}},
+						}},
+					},
+					GlobalOutputRefs: []string{"siem"},
+				},
+			},
+		},
+	}
+	events := []simulator.Event{
+		{Record: podRecord("apps", "web", map[string]any{"app.kubernetes.io/name": "web", "security": "true"}, "GET /login")},
+		{Record: podRecord("apps", "web", map[string]any{"app.kubernetes.io/name": "web"}, "health check")},
+		{Record: podRecord("apps", "web", map[string]any{"app.kubernetes.io/name": "web", "debug": "true"}, "debug")},
+		{Record: podRecord("kube-system", "dns", nil, "query")},
+	}
+
+	result := simulator.Simulate(resources, events)
+
+	loki := simulator.Ref{Kind: "Output", Namespace: "apps", Name: "loki"}
+	siem := simulator.Ref{Kind: "ClusterOutput", Namespace: "logging", Name: "siem"}
+	archive := simulator.Ref{Kind: "ClusterOutput", Namespace: "logging", Name: "archive"}
+
+	received := result.Received(loki)
+	require.Len(t, received, 1)
+	assert.Equal(t, 0, received[0].Event)
+	assert.Equal(t, "apps.web-0.web", received[0].Tag)
+	assert.Equal(t, map[string]any{
+		"message": "GET /login",
+		"source":  "apps",
+		"app":     "web",
+		"kubernetes": map[string]any{
+			"namespace_name": "apps",
+			"pod_name":       "web-0",
+			"container_name": "web",
+			"labels":         map[string]any{"app_kubernetes_io/name": "web", "security": "true"},
+		},
+	}, received[0].Record)
+
+	assert.True(t, result.Reached(0, siem))
+	assert.False(t, result.Reached(0, archive))
+	assert.Equal(t, []simulator.Drop{{Event: 1, Flow: simulator.Ref{Kind: "Flow", Namespace: "apps", Name: "web"}, Filter: "filters[1] grep"}}, result.Drops)
+	assert.True(t, result.Reached(2, archive), "excluded records fall back to the default flow")
+	assert.True(t, result.Reached(3, archive))
+	assert.Empty(t, result.Unrouted)
+	assert.Equal(t, []string{"Flow/apps/web filters[4] geoip: filter is not simulated, records pass unchanged"}, result.Warnings)
+}
+
+func TestSimulateSyslogNG(t *testing.T) {
+	resources := simulator.Resources{
+		SyslogNGFlows: []v1beta1.SyslogNGFlow{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+				Spec: v1beta1.SyslogNGFlowSpec{
+					Match: &v1beta1.SyslogNGMatch{
+						Regexp: &syslogngfilter.RegexpMatchExpr{
+							Value:   "json.kubernetes.labels.app.kubernetes.io/name",
+							Pattern: "web",
+							Type:    "string",
+						},
+					},
+					Filters: []v1beta1.SyslogNGFilter{
+						{Match: &syslogngfilter.MatchConfig{
+							Not: &syslogngfilter.MatchExpr{Regexp: &syslogngfilter.RegexpMatchExpr{Pattern: "^health"}},
+						}},
+						{Rewrite: []syslogngfilter.RewriteConfig{
+							{Set: &syslogngfilter.SetConfig{FieldName: "json.team", Value: "${json.kubernetes.namespace_name}-team"}},
+							{Substitute: &syslogngfilter.SubstituteConfig{FieldName: "MESSAGE", Pattern: "password=\\S+", Replacement: "password=***"}},
+							{GroupUnset: &syslogngfilter.GroupUnsetConfig{Pattern: "json.kubernetes.labels.*"}},
+						}},
+					},
+					LocalOutputRefs: []string{"loki"},
+				},
+			},
+		},
+	}
+	events := []simulator.Event{
+		{Record: podRecord("apps", "web", map[string]any{"app.kubernetes.io/name": "web"}, "login password=secret")},
+		{Record: podRecord("apps", "web", map[string]any{"app.kubernetes.io/name": "web"}, "health check")},
+		{Record: podRecord("other", "web", map[string]any{"app.kubernetes.io/name": "web"}, "login")},
+	}
+
+	result := simulator.Simulate(resources, events)
+
+	received := result.Received(simulator.Ref{Kind: "SyslogNGOutput", Namespace: "apps", Name: "loki"})
+	require.Len(t, received, 1)
+	assert.Equal(t, map[string]any{
+		"message": "login password=***",
+		"team":    "apps-team",
+		"kubernetes": map[string]any{
+			"namespace_name": "apps",
+			"pod_name":       "web-0",
+			"container_name": "web",
+			"host":           "node-1",
+			"labels":         map[string]any{},
+		},
+	}, received[0].Record)
+	assert.Equal(t, []simulator.Drop{{Event: 1, Flow: simulator.Ref{Kind: "SyslogNGFlow", Namespace: "apps", Name: "web"}, Filter: "filters[0] match"}}, result.Drops)
+	assert.Equal(t, []int{1, 2}, result.Unrouted)
+}
@@ -0,0 +1,401 @@
+// Copyright © 2023 Kube logging authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/filter"
+)
+
+const (
+	syslogNGJSONPrefix   = "json"
+	syslogNGMessageMacro = "MESSAGE"
+)
+
+func (s *simulation) syslogNGDefaultFlow() *v1beta1.SyslogNGDefaultFlowSpec {
+	if s.resources.Logging == nil || s.resources.Logging.Spec.SyslogNGSpec == nil {
+		return nil
+	}
+	return s.resources.Logging.Spec.SyslogNGSpec.DefaultFlow
+}
+
+func (s *simulation) keyDelim() string {
+	if s.resources.Logging != nil && s.resources.Logging.Spec.SyslogNGSpec != nil && s.resources.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter != "" {
+		return s.resources.Logging.Spec.SyslogNGSpec.JSONKeyDelimiter
+	}
+	return "."
+}
+
+// syslogNG processes an event the way the syslog-ng aggregator does: every log path receives the record,
+// the fallback path receives the records that did not pass the filters of any other path
+func (s *simulation) syslogNG(idx int, event Event) bool {
+	msg := &syslogNGMessage{record: copyRecord(event.Record), delim: s.keyDelim()}
+	if s.resources.Logging != nil && s.resources.Logging.Spec.SyslogNGSpec != nil {
+		global := Ref{Kind: "Logging", Namespace: s.resources.Logging.Namespace, Name: s.resources.Logging.Name}
+		if !s.syslogNGFilters(idx, global, s.resources.Logging.Spec.SyslogNGSpec.GlobalFilters, msg) {
+			return true
+		}
+	}
+
+	namespace := msg.get(strings.Join([]string{syslogNGJSONPrefix, "kubernetes", "namespace_name"}, msg.delim))
+	matched := false
+	for _, flow := range s.resources.SyslogNGClusterFlows {
+		ref := Ref{Kind: "SyslogNGClusterFlow", Namespace: flow.Namespace, Name: flow.Name}
+		if s.strictTenancy() && flow.Namespace != s.controlNamespace() && namespace != flow.Namespace {
+			continue
+		}
+		if flow.Spec.Match != nil && !s.matchExpr(ref, (*filter.MatchExpr)(flow.Spec.Match), msg) {
+			continue
+		}
+		var outputs []Ref
+		for _, name := range flow.Spec.GlobalOutputRefs {
+			outputs = append(outputs, Ref{Kind: "SyslogNGClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		matched = s.syslogNGFlow(idx, ref, flow.Spec.Filters, outputs, msg) || matched
+	}
+	for _, flow := range s.resources.SyslogNGFlows {
+		ref := Ref{Kind: "SyslogNGFlow", Namespace: flow.Namespace, Name: flow.Name}
+		if namespace != flow.Namespace {
+			continue
+		}
+		if flow.Spec.Match != nil && !s.matchExpr(ref, (*filter.MatchExpr)(flow.Spec.Match), msg) {
+			continue
+		}
+		var outputs []Ref
+		for _, name := range flow.Spec.GlobalOutputRefs {
+			outputs = append(outputs, Ref{Kind: "SyslogNGClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		for _, name := range flow.Spec.LocalOutputRefs {
+			outputs = append(outputs, Ref{Kind: "SyslogNGOutput", Namespace: flow.Namespace, Name: name})
+		}
+		for _, shared := range flow.Spec.SharedOutputRefs {
+			outputs = append(outputs, Ref{Kind: "SyslogNGOutput", Namespace: shared.Namespace, Name: shared.Name})
+		}
+		matched = s.syslogNGFlow(idx, ref, flow.Spec.Filters, outputs, msg) || matched
+	}
+	if !matched {
+		defaultFlow := s.syslogNGDefaultFlow()
+		if defaultFlow == nil {
+			return false
+		}
+		var outputs []Ref
+		for _, name := range defaultFlow.GlobalOutputRefs {
+			outputs = append(outputs, Ref{Kind: "SyslogNGClusterOutput", Namespace: s.controlNamespace(), Name: name})
+		}
+		s.syslogNGFlow(idx, Ref{Kind: "SyslogNGDefaultFlow"}, defaultFlow.Filters, outputs, msg)
+	}
+	return true
+}
+
+func (s *simulation) syslogNGFlow(idx int, flow Ref, filters []v1beta1.SyslogNGFilter, outputs []Ref, msg *syslogNGMessage) bool {
+	msg = &syslogNGMessage{record: copyRecord(msg.record), delim: msg.delim}
+	if !s.syslogNGFilters(idx, flow, filters, msg) {
+		return false
+	}
+	s.deliver(idx, flow, outputs, "", msg.record)
+	return true
+}
+
+func (s *simulation) syslogNGFilters(idx int, flow Ref, filters []v1beta1.SyslogNGFilter, msg *syslogNGMessage) bool {
+	for i, f := range filters {
+		name := fmt.Sprintf("filters[%d]", i)
+		if f.ID != "" {
+			name = f.ID
+		}
+		switch {
+		case f.Match != nil:
+			if !s.matchExpr(flow, (*filter.MatchExpr)(f.Match), msg) {
+				s.drop(idx, flow, name+" match")
+				return false
+			}
+		case f.Rewrite != nil:
+			for _, rewrite := range f.Rewrite {
+				s.rewrite(flow, rewrite, msg)
+			}
+		default:
+			s.warn("%s %s: filter is not simulated, records pass unchanged", flow, name)
+		}
+	}
+	return true
+}
+
+func (s *simulation) matchExpr(flow Ref, expr *filter.MatchExpr, msg *syslogNGMessage) bool {
+	switch {
+	case expr == nil:
+		return true
+	case expr.Regexp != nil:
+		value := msg.get(syslogNGMessageMacro)
+		if expr.Regexp.Template != "" {
+			value = msg.expand(expr.Regexp.Template)
+		} else if expr.Regexp.Value != "" {
+			value = msg.get(expr.Regexp.Value)
+		}
+		matched, err := matchPattern(expr.Regexp.Pattern, expr.Regexp.Type, expr.Regexp.Flags, value)
+		if err != nil {
+			s.warn("%s: %s", flow, err)
+		}
+		return matched
+	case expr.Not != nil:
+		return !s.matchExpr(flow, expr.Not, msg)
+	case len(expr.And) > 0:
+		for i := range expr.And {
+			if !s.matchExpr(flow, &expr.And[i], msg) {
+				return false
+			}
+		}
+		return true
+	case len(expr.Or) > 0:
+		for i := range expr.Or {
+			if s.matchExpr(flow, &expr.Or[i], msg) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern evaluates the string, glob and pcre pattern types of syslog-ng
+func matchPattern(pattern, patternType string, flags []string, value string) (bool, error) {
+	ignoreCase := hasFlag(flags, "ignore-case")
+	switch patternType {
+	case "string":
+		if ignoreCase {
+			pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+		}
+		switch {
+		case hasFlag(flags, "prefix"):
+			return strings.HasPrefix(value, pattern), nil
+		case hasFlag(flags, "substring"):
+			return strings.Contains(value, pattern), nil
+		default:
+			return value == pattern, nil
+		}
+	case "glob":
+		return globMatch(pattern, value), nil
+	default:
+		re, err := syslogNGRegexp(pattern, ignoreCase)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value), nil
+	}
+}
+
+// globMatch matches the glob patterns of syslog-ng, where wildcards match any character including slashes
+func globMatch(pattern, value string) bool {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String()).MatchString(value)
+}
+
+func syslogNGRegexp(pattern string, ignoreCase bool) (*regexp.Regexp, error) {
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+func (s *simulation) rewrite(flow Ref, config filter.RewriteConfig, msg *syslogNGMessage) {
+	switch {
+	case config.Set != nil:
+		if s.matchExpr(flow, config.Set.Condition, msg) {
+			msg.set(config.Set.FieldName, msg.expand(config.Set.Value))
+		}
+	case config.Unset != nil:
+		if s.matchExpr(flow, config.Unset.Condition, msg) {
+			msg.unset(config.Unset.FieldName)
+		}
+	case config.Rename != nil:
+		if s.matchExpr(flow, config.Rename.Condition, msg) && msg.has(config.Rename.OldFieldName) {
+			value := msg.get(config.Rename.OldFieldName)
+			msg.unset(config.Rename.OldFieldName)
+			msg.set(config.Rename.NewFieldName, value)
+		}
+	case config.GroupUnset != nil:
+		if s.matchExpr(flow, config.GroupUnset.Condition, msg) {
+			for _, name := range msg.names() {
+				if globMatch(config.GroupUnset.Pattern, name) {
+					msg.unset(name)
+				}
+			}
+		}
+	case config.Substitute != nil:
+		subst := config.Substitute
+		if !s.matchExpr(flow, subst.Condition, msg) || !msg.has(subst.FieldName) {
+			return
+		}
+		value := msg.get(subst.FieldName)
+		global := hasFlag(subst.Flags, "global")
+		if subst.Type == "string" {
+			n := 1
+			if global {
+				n = -1
+			}
+			msg.set(subst.FieldName, strings.Replace(value, subst.Pattern, subst.Replacement, n))
+			return
+		}
+		re, err := syslogNGRegexp(subst.Pattern, hasFlag(subst.Flags, "ignore-case"))
+		if err != nil {
+			s.warn("%s: %s", flow, err)
+			return
+		}
+		if global {
+			msg.set(subst.FieldName, re.ReplaceAllString(value, subst.Replacement))
+		} else if loc := re.FindStringSubmatchIndex(value); loc != nil {
+			replaced := re.ExpandString(nil, subst.Replacement, value, loc)
+			msg.set(subst.FieldName, value[:loc[0]]+string(replaced)+value[loc[1]:])
+		}
+	}
+}
+
+// syslogNGMessage addresses the parsed JSON record with the name-value pairs of syslog-ng,
+// for example json.kubernetes.labels.app with the default key delimiter
+type syslogNGMessage struct {
+	record map[string]any
+	delim  string
+}
+
+// path resolves a name to the keys of the nested record, preferring the longest existing keys
+// so that keys containing the delimiter, like most kubernetes labels, can be addressed
+func (m *syslogNGMessage) path(name string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(name, syslogNGJSONPrefix+m.delim)
+	if !ok {
+		if name == syslogNGMessageMacro {
+			if _, ok := m.record["log"]; ok {
+				if _, ok := m.record["message"]; !ok {
+					return []string{"log"}, true
+				}
+			}
+			return []string{"message"}, true
+		}
+		return nil, false
+	}
+	parts := strings.Split(rest, m.delim)
+	var result []string
+	current := m.record
+	for i := 0; i < len(parts); {
+		j := len(parts)
+		for ; j > i; j-- {
+			if _, ok := current[strings.Join(parts[i:j], m.delim)]; ok {
+				break
+			}
+		}
+		if j == i {
+			return append(result, parts[i:]...), true
+		}
+		key := strings.Join(parts[i:j], m.delim)
+		result = append(result, key)
+		i = j
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return append(result, parts[i:]...), true
+		}
+		current = next
+	}
+	return result, true
+}
+
+func (m *syslogNGMessage) has(name string) bool {
+	p, ok := m.path(name)
+	if !ok {
+		return false
+	}
+	_, ok = lookup(m.record, p)
+	return ok
+}
+
+func (m *syslogNGMessage) get(name string) string {
+	p, ok := m.path(name)
+	if !ok {
+		return ""
+	}
+	value, _ := lookup(m.record, p)
+	return stringValue(value)
+}
+
+func (m *syslogNGMessage) set(name string, value string) {
+	p, ok := m.path(name)
+	if !ok {
+		return
+	}
+	current := m.record
+	for _, key := range p[:len(p)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[key] = next
+		}
+		current = next
+	}
+	current[p[len(p)-1]] = value
+}
+
+func (m *syslogNGMessage) unset(name string) {
+	if p, ok := m.path(name); ok {
+		remove(m.record, p)
+	}
+}
+
+// names lists the name-value pairs of the leaf values of the record
+func (m *syslogNGMessage) names() []string {
+	var result []string
+	var walk func(prefix string, record map[string]any)
+	walk = func(prefix string, record map[string]any) {
+		for key, value := range record {
+			name := prefix + m.delim + key
+			if nested, ok := value.(map[string]any); ok {
+				walk(name, nested)
+				continue
+			}
+			result = append(result, name)
+		}
+	}
+	walk(syslogNGJSONPrefix, m.record)
+	sort.Strings(result)
+	return result
+}
+
+// expand resolves the ${name} references of a template
+func (m *syslogNGMessage) expand(template string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return m.get(placeholder[2 : len(placeholder)-1])
+	})
+}